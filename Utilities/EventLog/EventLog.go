@@ -0,0 +1,118 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FactomProject/factomd/common/eventLog"
+)
+
+func main() {
+	types := flag.String("type", "", "Comma separated event types to show: received, acked, executed, eom, dbsig, saved, election")
+	nodes := flag.String("node", "", "Comma separated names of the nodes to show")
+	height := flag.String("height", "", "Directory block height, or range of heights like 100-120")
+	vm := flag.Int("vm", -1, "VM index to show")
+	hash := flag.String("hash", "", "Hex prefix of the message or block hash to show")
+	since := flag.String("since", "", "Show the events from this time on, RFC3339")
+	until := flag.String("until", "", "Show the events up to this time, RFC3339")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage:")
+		fmt.Fprintln(os.Stderr, "EventLog [options] file.events...")
+		fmt.Fprintln(os.Stderr, "Program merges the consensus event logs of one or more nodes into one timeline")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	filter := new(eventLog.Filter)
+	onlyGenesis := false
+	if *types != "" {
+		for _, name := range strings.Split(*types, ",") {
+			t, err := eventLog.ParseEventType(strings.TrimSpace(name))
+			if err != nil {
+				fail(err)
+			}
+			filter.Types = append(filter.Types, t)
+		}
+	}
+	if *nodes != "" {
+		for _, name := range strings.Split(*nodes, ",") {
+			filter.Nodes = append(filter.Nodes, strings.TrimSpace(name))
+		}
+	}
+	if *height != "" {
+		bounds := strings.SplitN(*height, "-", 2)
+		min, err := strconv.ParseUint(bounds[0], 10, 32)
+		if err != nil {
+			fail(fmt.Errorf("Invalid height %q", *height))
+		}
+		max := min
+		if len(bounds) == 2 {
+			max, err = strconv.ParseUint(bounds[1], 10, 32)
+			if err != nil || max < min {
+				fail(fmt.Errorf("Invalid height %q", *height))
+			}
+		}
+		filter.MinHeight = uint32(min)
+		filter.MaxHeight = uint32(max)
+		// A MaxHeight of zero is no limit in the filter
+		onlyGenesis = max == 0
+	}
+	if *vm >= 0 {
+		filter.VM = vm
+	}
+	if *hash != "" {
+		prefix, err := hex.DecodeString(*hash)
+		if err != nil {
+			fail(fmt.Errorf("Invalid hash prefix %q", *hash))
+		}
+		filter.Hash = prefix
+	}
+	if *since != "" {
+		t, err := time.Parse(time.RFC3339, *since)
+		if err != nil {
+			fail(err)
+		}
+		filter.Since = t
+	}
+	if *until != "" {
+		t, err := time.Parse(time.RFC3339, *until)
+		if err != nil {
+			fail(err)
+		}
+		filter.Until = t
+	}
+
+	logs := [][]*eventLog.Event{}
+	for _, path := range flag.Args() {
+		events, err := eventLog.ReadFile(path)
+		if err != nil {
+			fail(err)
+		}
+		logs = append(logs, events)
+	}
+
+	for _, e := range eventLog.Merge(logs...) {
+		if filter.Match(e) && (!onlyGenesis || e.DBHeight == 0) {
+			fmt.Println(e.String())
+		}
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,113 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/database/badgerdb"
+	"github.com/FactomProject/factomd/database/boltdb"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/hybridDB"
+	"github.com/FactomProject/factomd/database/leveldb"
+	"github.com/FactomProject/factomd/database/securedb"
+)
+
+const level string = "level"
+const bolt string = "bolt"
+const badger string = "badger"
+const encryptedLevel string = "encryptedlevel"
+const encryptedBolt string = "encryptedbolt"
+
+func main() {
+	fmt.Println("Usage:")
+	fmt.Println("RebuildExtIDIndex level/bolt/badger/encryptedlevel/encryptedbolt DBFileLocation [PasswordSource]")
+	fmt.Println("Program will clear the external ID index and index every entry of the database again")
+	fmt.Println("The encrypted databases take the source of their password, env:VARIABLE, file:PATH or prompt (the default)")
+	fmt.Println("factomd must not be running on the database")
+
+	if len(os.Args) < 3 {
+		fmt.Println("\nNot enough arguments passed")
+		os.Exit(1)
+	}
+	if len(os.Args) > 4 {
+		fmt.Println("\nToo many arguments passed")
+		os.Exit(1)
+	}
+
+	dbType := os.Args[1]
+	encrypted := dbType == encryptedLevel || dbType == encryptedBolt
+	if dbType != level && dbType != bolt && dbType != badger && !encrypted {
+		fmt.Println("\nFirst argument should be `level`, `bolt`, `badger`, `encryptedlevel` or `encryptedbolt`")
+		os.Exit(1)
+	}
+	if len(os.Args) > 3 && !encrypted {
+		fmt.Println("\nOnly the encrypted databases take a password source")
+		os.Exit(1)
+	}
+
+	path := os.Args[2]
+
+	var dbase interfaces.IDatabase
+	var err error
+	switch dbType {
+	case bolt:
+		dbase = hybridDB.NewBoltMapHybridDB(nil, path)
+	case level:
+		dbase, err = hybridDB.NewLevelMapHybridDB(path, false)
+		if err != nil {
+			panic(err)
+		}
+	case badger:
+		dbase, err = badgerdb.NewBadgerDB(path, false)
+		if err != nil {
+			panic(err)
+		}
+	default:
+		source := "prompt"
+		if len(os.Args) > 3 {
+			source = os.Args[3]
+		}
+		password, err := securedb.GetPassword(source, "Database password")
+		if err != nil {
+			fmt.Printf("ERROR: %v\n", err)
+			os.Exit(1)
+		}
+
+		var raw interfaces.IDatabase
+		if dbType == encryptedBolt {
+			raw = boltdb.NewBoltDB(nil, path)
+		} else {
+			raw, err = leveldb.NewLevelDB(path, false)
+			if err != nil {
+				panic(err)
+			}
+		}
+		dbase, err = securedb.NewEncryptedDBFromDB(raw, password)
+		if err != nil {
+			raw.Close()
+			fmt.Printf("ERROR: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	dbo := databaseOverlay.NewOverlay(dbase)
+	defer dbo.Close()
+	dbo.SetExtIDIndex(true)
+
+	err = dbo.RebuildExtIDIndex()
+	if err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		os.Exit(1)
+	}
+
+	next, err := dbo.FetchExtIDIndexHeight()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("External ID index rebuilt up to height %d\n", int64(next)-1)
+}
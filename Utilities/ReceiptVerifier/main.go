@@ -0,0 +1,80 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/receipts"
+)
+
+// Checks a receipt offline, from the entry up to the Bitcoin and Ethereum transactions
+// anchoring its directory block.  The transactions themselves are printed, to be
+// looked up in a block explorer or a local node of that network.
+func main() {
+	var (
+		keys     = flag.String("keys", "", "Comma separated anchor signing public keys, in hex, instead of the mainnet keys")
+		anchored = flag.Bool("anchored", false, "Fail if the receipt has no anchor")
+	)
+	flag.Parse()
+
+	if len(flag.Args()) != 1 {
+		fmt.Println("Usage:")
+		fmt.Println("ReceiptVerifier [-keys key1,key2] [-anchored] receipt.json")
+		fmt.Println("Program verifies an entry receipt, as returned by the receipt API call")
+		os.Exit(1)
+	}
+
+	anchorKeys := databaseOverlay.AnchorSigPublicKeys
+	if *keys != "" {
+		anchorKeys = nil
+		for _, k := range strings.Split(*keys, ",") {
+			pub := new(primitives.PublicKey)
+			err := pub.UnmarshalText([]byte(strings.TrimSpace(k)))
+			if err != nil {
+				fmt.Println("Invalid anchor key", k, err)
+				os.Exit(1)
+			}
+			anchorKeys = append(anchorKeys, interfaces.Verifier(pub))
+		}
+	}
+
+	data, err := ioutil.ReadFile(flag.Args()[0])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	receipt, err := receipts.VerifyReceiptJSON(data, anchorKeys)
+	if err != nil {
+		fmt.Println("Receipt is INVALID:", err)
+		os.Exit(2)
+	}
+
+	fmt.Println("Entry:          ", receipt.Entry.EntryHash)
+	fmt.Println("Entry block:    ", receipt.EntryBlockKeyMR.String())
+	fmt.Println("Directory block:", receipt.DirectoryBlockKeyMR.String())
+	for _, a := range receipt.Anchors {
+		if a.Bitcoin != nil {
+			fmt.Printf("Bitcoin:          tx %s in block %d (%s)\n", a.Bitcoin.TXID, a.Bitcoin.BlockHeight, a.Bitcoin.BlockHash)
+		}
+		if a.Ethereum != nil {
+			fmt.Printf("Ethereum:         tx %s in block %d (%s)\n", a.Ethereum.TXID, a.Ethereum.BlockHeight, a.Ethereum.BlockHash)
+		}
+	}
+	if len(receipt.Anchors) == 0 {
+		fmt.Println("The receipt has no anchor, it only proves the entry is in the directory block")
+		if *anchored {
+			os.Exit(2)
+		}
+	}
+	fmt.Println("Receipt is valid")
+}
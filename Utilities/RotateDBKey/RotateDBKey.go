@@ -0,0 +1,87 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/database/boltdb"
+	"github.com/FactomProject/factomd/database/leveldb"
+	"github.com/FactomProject/factomd/database/securedb"
+)
+
+const level string = "level"
+const bolt string = "bolt"
+
+func main() {
+	fmt.Println("Usage:")
+	fmt.Println("RotateDBKey level/bolt DBFileLocation OldPasswordSource NewPasswordSource")
+	fmt.Println("Program will encrypt every record of an EncryptedLDB or EncryptedBolt database with a new password")
+	fmt.Println("Password sources are env:VARIABLE, file:PATH or prompt")
+	fmt.Println("factomd must not be running on the database.  An interrupted rotation is finished by running it again")
+
+	if len(os.Args) < 5 {
+		fmt.Println("\nNot enough arguments passed")
+		os.Exit(1)
+	}
+	if len(os.Args) > 5 {
+		fmt.Println("\nToo many arguments passed")
+		os.Exit(1)
+	}
+
+	levelBolt := os.Args[1]
+	if levelBolt != level && levelBolt != bolt {
+		fmt.Println("\nFirst argument should be `level` or `bolt`")
+		os.Exit(1)
+	}
+
+	path := os.Args[2]
+	if _, err := os.Stat(path); err != nil {
+		fmt.Printf("\nERROR: %v\n", err)
+		os.Exit(1)
+	}
+
+	oldPassword, err := securedb.GetPassword(os.Args[3], "Current database password")
+	if err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	newPassword, err := securedb.GetPassword(os.Args[4], "New database password")
+	if err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	if os.Args[4] == "prompt" {
+		again, err := securedb.GetPassword(os.Args[4], "New database password again")
+		if err != nil {
+			fmt.Printf("ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		if again != newPassword {
+			fmt.Println("ERROR: The new passwords do not match")
+			os.Exit(1)
+		}
+	}
+
+	var dbase interfaces.IDatabase
+	if levelBolt == bolt {
+		dbase = boltdb.NewBoltDB(nil, path)
+	} else {
+		dbase, err = leveldb.NewLevelDB(path, false)
+		if err != nil {
+			panic(err)
+		}
+	}
+	defer dbase.Close()
+
+	count, err := securedb.RotateKey(dbase, oldPassword, newPassword)
+	if err != nil {
+		fmt.Printf("ERROR after %d records: %v\n", count, err)
+		os.Exit(1)
+	}
+	fmt.Printf("Key rotated, %d records encrypted again\n", count)
+}
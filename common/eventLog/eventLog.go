@@ -0,0 +1,213 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package eventLog writes and reads the consensus event log of a node: an append-only
+// file of typed records about the messages and blocks the node goes through, each with
+// a sequence number and the time of the node, so the logs of several nodes can be
+// merged into one timeline.
+//
+// A log starts with a header: the magic "FCEL", the version of the format and the name
+// of the node.  Then every record is its length (4 bytes), the event and the CRC32 of
+// the event (4 bytes).  A node killed while writing leaves a partial record at the
+// end, which readers ignore.
+package eventLog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+const (
+	// Magic starts every event log
+	Magic = "FCEL"
+	// Version of the format of the events
+	Version byte = 1
+)
+
+type EventType byte
+
+const (
+	// MsgReceived is a message put in the input queue of the node
+	MsgReceived EventType = iota + 1
+	// MsgAcked is a message added to a process list with its ack, at Height in VM
+	MsgAcked
+	// MsgExecuted is a message of a process list processed, at Height in VM
+	MsgExecuted
+	// EOMProcessed is the EOM of a VM processed
+	EOMProcessed
+	// DBSigProcessed is the directory block signature of a VM processed
+	DBSigProcessed
+	// DBStateSaved is a block saved to the database, Hash is the directory block KeyMR
+	DBStateSaved
+	// Election is an election message processed, VM is the server being replaced
+	Election
+)
+
+var eventTypeNames = map[EventType]string{
+	MsgReceived:    "received",
+	MsgAcked:       "acked",
+	MsgExecuted:    "executed",
+	EOMProcessed:   "eom",
+	DBSigProcessed: "dbsig",
+	DBStateSaved:   "saved",
+	Election:       "election",
+}
+
+func (t EventType) String() string {
+	if name, ok := eventTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("type%d", byte(t))
+}
+
+// ParseEventType returns the type of a name given by String
+func ParseEventType(name string) (EventType, error) {
+	for t, n := range eventTypeNames {
+		if strings.EqualFold(n, name) {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("Unknown event type %q", name)
+}
+
+// Event is one record of the log.  VM and Height are -1 when they do not apply, and
+// MsgType and Hash are zero for the events that are not about a message.
+type Event struct {
+	// Node is the name of the node of the log, it is not part of the record
+	Node string
+
+	Seq      uint64
+	Time     time.Time
+	Type     EventType
+	DBHeight uint32
+	Minute   int
+	VM       int
+	Height   int
+	MsgType  byte
+	Hash     [32]byte
+	Note     string
+}
+
+func (e *Event) MarshalBinary() ([]byte, error) {
+	buf := primitives.NewBuffer(nil)
+	err := buf.PushUInt64(e.Seq)
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PushInt64(e.Time.UnixNano())
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PushByte(byte(e.Type))
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PushUInt32(e.DBHeight)
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PushByte(byte(e.Minute))
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PushUInt32(uint32(int32(e.VM)))
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PushUInt32(uint32(int32(e.Height)))
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PushByte(e.MsgType)
+	if err != nil {
+		return nil, err
+	}
+	err = buf.Push(e.Hash[:])
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PushString(e.Note)
+	if err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (e *Event) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	buf := primitives.NewBuffer(data)
+	var err error
+	e.Seq, err = buf.PopUInt64()
+	if err != nil {
+		return nil, err
+	}
+	t, err := buf.PopInt64()
+	if err != nil {
+		return nil, err
+	}
+	e.Time = time.Unix(0, t)
+	b, err := buf.PopByte()
+	if err != nil {
+		return nil, err
+	}
+	e.Type = EventType(b)
+	e.DBHeight, err = buf.PopUInt32()
+	if err != nil {
+		return nil, err
+	}
+	b, err = buf.PopByte()
+	if err != nil {
+		return nil, err
+	}
+	e.Minute = int(b)
+	i, err := buf.PopUInt32()
+	if err != nil {
+		return nil, err
+	}
+	e.VM = int(int32(i))
+	i, err = buf.PopUInt32()
+	if err != nil {
+		return nil, err
+	}
+	e.Height = int(int32(i))
+	e.MsgType, err = buf.PopByte()
+	if err != nil {
+		return nil, err
+	}
+	h, err := buf.PopLen(len(e.Hash))
+	if err != nil {
+		return nil, err
+	}
+	copy(e.Hash[:], h)
+	e.Note, err = buf.PopString()
+	if err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (e *Event) UnmarshalBinary(data []byte) error {
+	_, err := e.UnmarshalBinaryData(data)
+	return err
+}
+
+// String formats the event as one line of a timeline
+func (e *Event) String() string {
+	vm := "-"
+	if e.VM >= 0 {
+		vm = fmt.Sprint(e.VM)
+	}
+	height := "-"
+	if e.Height >= 0 {
+		height = fmt.Sprint(e.Height)
+	}
+	hash := "-"
+	if e.Hash != [32]byte{} {
+		hash = fmt.Sprintf("%x", e.Hash[:6])
+	}
+	return fmt.Sprintf("%s %-8s %8d %-8s %7d-:-%-2d vm %2s/%-5s msg %3d %-12s %s",
+		e.Time.Format("15:04:05.000000"), e.Node, e.Seq, e.Type, e.DBHeight, e.Minute, vm, height, e.MsgType, hash, e.Note)
+}
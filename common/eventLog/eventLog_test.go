@@ -0,0 +1,198 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package eventLog_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/FactomProject/factomd/common/eventLog"
+)
+
+func newEvent(t EventType, dbheight uint32, vm int, height int) *Event {
+	e := new(Event)
+	e.Type = t
+	e.DBHeight = dbheight
+	e.VM = vm
+	e.Height = height
+	return e
+}
+
+func TestEventMarshal(t *testing.T) {
+	e := newEvent(MsgAcked, 1234, 2, -1)
+	e.Seq = 99
+	e.Time = time.Unix(1500000000, 123456789)
+	e.Minute = 7
+	e.MsgType = 3
+	e.Hash[0] = 0xAB
+	e.Hash[31] = 0xCD
+	e.Note = "a note"
+
+	b, err := e.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e2 := new(Event)
+	err = e2.UnmarshalBinary(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e2.Seq != e.Seq || !e2.Time.Equal(e.Time) || e2.Type != e.Type || e2.DBHeight != e.DBHeight ||
+		e2.Minute != e.Minute || e2.VM != e.VM || e2.Height != e.Height || e2.MsgType != e.MsgType ||
+		e2.Hash != e.Hash || e2.Note != e.Note {
+		t.Errorf("Got %v, expected %v", e2, e)
+	}
+
+	for _, name := range []string{"received", "acked", "executed", "eom", "dbsig", "saved", "election"} {
+		et, err := ParseEventType(name)
+		if err != nil {
+			t.Error(err)
+		}
+		if et.String() != name {
+			t.Errorf("Got %s, expected %s", et, name)
+		}
+	}
+	if _, err := ParseEventType("bogus"); err == nil {
+		t.Error("Expected an error for an unknown event type")
+	}
+}
+
+func TestWriterReader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventlog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "FNode0.events")
+
+	w, err := Open(path, "FNode0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := w.Log(newEvent(MsgExecuted, uint32(i), 0, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.Close()
+	if err := w.Log(newEvent(MsgExecuted, 5, 0, 5)); err != ErrClosed {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+	var nilWriter *Writer
+	if err := nilWriter.Log(newEvent(MsgExecuted, 5, 0, 5)); err != nil {
+		t.Errorf("A nil writer returned %v", err)
+	}
+
+	// Leave half an event at the end, as if the node died while writing it
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{0, 0, 0, 80, 1, 2, 3})
+	f.Close()
+
+	events, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 5 {
+		t.Fatalf("Got %d events, expected 5", len(events))
+	}
+	for i, e := range events {
+		if e.Seq != uint64(i+1) || e.Node != "FNode0" || e.DBHeight != uint32(i) || e.Height != i {
+			t.Errorf("Wrong event %v", e)
+		}
+	}
+
+	if _, err := Open(path, "FNode1"); err == nil {
+		t.Error("Opened the log of another node")
+	}
+
+	// Reopening cuts the partial event and goes on with the sequence
+	w, err = Open(path, "FNode0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Log(newEvent(DBStateSaved, 5, -1, -1))
+	w.Close()
+	events, err = ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 6 || events[5].Seq != 6 || events[5].Type != DBStateSaved {
+		t.Errorf("Got %d events, the last one %v", len(events), events[len(events)-1])
+	}
+
+	ioutil.WriteFile(path, []byte("not a log"), 0644)
+	if _, err := ReadFile(path); err == nil {
+		t.Error("Read a file that is not an event log")
+	}
+}
+
+func TestFilterMerge(t *testing.T) {
+	start := time.Unix(1500000000, 0)
+	var a, b []*Event
+	for i := 0; i < 4; i++ {
+		e := newEvent(EOMProcessed, uint32(i), i%2, -1)
+		e.Node = "FNode0"
+		e.Seq = uint64(i + 1)
+		e.Time = start.Add(time.Duration(2*i) * time.Second)
+		a = append(a, e)
+
+		e = newEvent(DBSigProcessed, uint32(i), 0, -1)
+		e.Node = "FNode1"
+		e.Seq = uint64(i + 1)
+		e.Time = start.Add(time.Duration(2*i+1) * time.Second)
+		b = append(b, e)
+	}
+	// The clock of FNode0 went back, its events keep their order
+	a[3].Time = start
+
+	all := Merge(a, b)
+	if len(all) != 8 {
+		t.Fatalf("Got %d events, expected 8", len(all))
+	}
+	expected := []*Event{a[0], b[0], a[1], b[1], a[2], a[3], b[2], b[3]}
+	for i := range expected {
+		if all[i] != expected[i] {
+			t.Errorf("Event %d is %v, expected %v", i, all[i], expected[i])
+		}
+	}
+
+	vm := 1
+	f := new(Filter)
+	f.Nodes = []string{"FNode0"}
+	f.VM = &vm
+	f.MinHeight = 1
+	f.MaxHeight = 2
+	count := 0
+	for _, e := range all {
+		if f.Match(e) {
+			count++
+			if e != a[1] {
+				t.Errorf("Wrong event %v", e)
+			}
+		}
+	}
+	if count != 1 {
+		t.Errorf("Got %d events, expected 1", count)
+	}
+
+	f = new(Filter)
+	f.Types = []EventType{DBSigProcessed}
+	f.Since = start.Add(2 * time.Second)
+	count = 0
+	for _, e := range all {
+		if f.Match(e) {
+			count++
+		}
+	}
+	if count != 3 {
+		t.Errorf("Got %d events, expected 3", count)
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package eventLog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// MaxEventSize bounds the size of a record, so a damaged length is not read as one
+const MaxEventSize = 1 << 20
+
+// Reader reads the events of a log in order
+type Reader struct {
+	r       *bufio.Reader
+	offset  int64
+	Node    string
+	Version byte
+}
+
+// NewReader reads the header of a log
+func NewReader(r io.Reader) (*Reader, error) {
+	lr := new(Reader)
+	lr.r = bufio.NewReader(r)
+
+	magic := make([]byte, len(Magic)+1)
+	if _, err := io.ReadFull(lr.r, magic); err != nil {
+		return nil, fmt.Errorf("Not an event log: %v", err)
+	}
+	if !bytes.Equal(magic[:len(Magic)], []byte(Magic)) {
+		return nil, fmt.Errorf("Not an event log")
+	}
+	lr.Version = magic[len(Magic)]
+	if lr.Version != Version {
+		return nil, fmt.Errorf("Unsupported event log version %d", lr.Version)
+	}
+	l, err := binary.ReadUvarint(lr.r)
+	if err != nil || l > MaxEventSize {
+		return nil, fmt.Errorf("Invalid event log header")
+	}
+	node := make([]byte, l)
+	if _, err := io.ReadFull(lr.r, node); err != nil {
+		return nil, fmt.Errorf("Invalid event log header")
+	}
+	lr.Node = string(node)
+	lr.offset = int64(len(magic)) + int64(uvarintLen(l)) + int64(l)
+	return lr, nil
+}
+
+func uvarintLen(v uint64) int {
+	var buf [binary.MaxVarintLen64]byte
+	return binary.PutUvarint(buf[:], v)
+}
+
+// Offset is the size of the header and the events read so far
+func (lr *Reader) Offset() int64 {
+	return lr.offset
+}
+
+// Next returns the next event, or io.EOF after the last one.  A partial record at the
+// end of the log is the event being written when the node stopped, and is ignored.
+func (lr *Reader) Next() (*Event, error) {
+	var l [4]byte
+	if _, err := io.ReadFull(lr.r, l[:]); err != nil {
+		return nil, io.EOF
+	}
+	size := binary.BigEndian.Uint32(l[:])
+	if size > MaxEventSize {
+		return nil, fmt.Errorf("Invalid event size %d at offset %d", size, lr.offset)
+	}
+	record := make([]byte, size+4)
+	if _, err := io.ReadFull(lr.r, record); err != nil {
+		return nil, io.EOF
+	}
+	data := record[:size]
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(record[size:]) {
+		return nil, fmt.Errorf("Damaged event at offset %d", lr.offset)
+	}
+
+	e := new(Event)
+	err := e.UnmarshalBinary(data)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid event at offset %d: %v", lr.offset, err)
+	}
+	e.Node = lr.Node
+	lr.offset += int64(len(l) + len(record))
+	return e, nil
+}
+
+// ReadFile returns all the events of a log file
+func ReadFile(path string) ([]*Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	events := []*Event{}
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		events = append(events, e)
+	}
+}
+
+// Filter selects events.  The zero value of a field selects everything.
+type Filter struct {
+	Types     []EventType
+	Nodes     []string
+	MinHeight uint32
+	MaxHeight uint32 // Zero for no limit
+	VM        *int
+	Hash      []byte // Prefix of the hash of the message or block
+	Since     time.Time
+	Until     time.Time
+}
+
+func (f *Filter) Match(e *Event) bool {
+	if len(f.Types) > 0 {
+		found := false
+		for _, t := range f.Types {
+			found = found || t == e.Type
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.Nodes) > 0 {
+		found := false
+		for _, n := range f.Nodes {
+			found = found || n == e.Node
+		}
+		if !found {
+			return false
+		}
+	}
+	if e.DBHeight < f.MinHeight || (f.MaxHeight > 0 && e.DBHeight > f.MaxHeight) {
+		return false
+	}
+	if f.VM != nil && e.VM != *f.VM {
+		return false
+	}
+	if len(f.Hash) > 0 && !bytes.HasPrefix(e.Hash[:], f.Hash) {
+		return false
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && e.Time.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Merge merges the events of several nodes into one timeline, ordered by the time of
+// the nodes.  The events of a node keep the order of their sequence numbers, even
+// where the clock of the node went back.
+func Merge(logs ...[]*Event) []*Event {
+	sorted := make([][]*Event, len(logs))
+	total := 0
+	for i, events := range logs {
+		sorted[i] = make([]*Event, len(events))
+		copy(sorted[i], events)
+		sort.SliceStable(sorted[i], func(a, b int) bool { return sorted[i][a].Seq < sorted[i][b].Seq })
+		total += len(events)
+	}
+
+	all := make([]*Event, 0, total)
+	for len(all) < total {
+		next := -1
+		for i, events := range sorted {
+			if len(events) == 0 {
+				continue
+			}
+			if next < 0 || events[0].Time.Before(sorted[next][0].Time) ||
+				(events[0].Time.Equal(sorted[next][0].Time) && events[0].Node < sorted[next][0].Node) {
+				next = i
+			}
+		}
+		all = append(all, sorted[next][0])
+		sorted[next] = sorted[next][1:]
+	}
+	return all
+}
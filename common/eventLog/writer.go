@@ -0,0 +1,143 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package eventLog
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// ErrClosed is returned for the events logged after the log was closed, or after an
+// error closed it
+var ErrClosed = errors.New("The event log is closed")
+
+// Writer appends events to the log of a node.  It can be used by several goroutines,
+// and every event is written at once, so the log is complete up to the last event
+// when the node dies.  A nil Writer drops the events.
+type Writer struct {
+	mutex sync.Mutex
+	file  *os.File
+	node  string
+	seq   uint64
+}
+
+// Open opens the log of a node to append events to it, or creates it.  The sequence
+// numbers go on from the last event of an existing log.
+func Open(path string, node string) (*Writer, error) {
+	w := new(Writer)
+	w.node = node
+
+	if f, err := os.Open(path); err == nil {
+		r, err := NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		if r.Node != node {
+			f.Close()
+			return nil, fmt.Errorf("%s is the log of %s", path, r.Node)
+		}
+		for {
+			e, err := r.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				f.Close()
+				return nil, fmt.Errorf("%s: %v", path, err)
+			}
+			w.seq = e.Seq
+		}
+		f.Close()
+
+		// Cut a partial record left by a crash, so the next events can be read
+		err = os.Truncate(path, r.Offset())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if st.Size() == 0 {
+		buf := primitives.NewBuffer([]byte(Magic))
+		buf.PushByte(Version)
+		buf.PushString(node)
+		_, err = f.Write(buf.DeepCopyBytes())
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	w.file = f
+	return w, nil
+}
+
+// Log appends an event, with the next sequence number.  The time of the node is the
+// current time if the event does not have one.
+func (w *Writer) Log(e *Event) error {
+	if w == nil {
+		return nil
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.file == nil {
+		return ErrClosed
+	}
+	w.seq++
+	e.Seq = w.seq
+	e.Node = w.node
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	data, err := e.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	record := make([]byte, 4, 4+len(data)+4)
+	binary.BigEndian.PutUint32(record, uint32(len(data)))
+	record = append(record, data...)
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc32.ChecksumIEEE(data))
+	record = append(record, sum[:]...)
+	_, err = w.file.Write(record)
+	if err != nil {
+		w.file.Close()
+		w.file = nil
+	}
+	return err
+}
+
+func (w *Writer) Close() error {
+	if w == nil {
+		return nil
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
@@ -68,4 +68,8 @@ type FactomParams struct {
 	FixChainHeads            bool // Only matters if CheckChainHeads == true
 	ControlPanelSetting      string
 	WriteProcessedDBStates   bool // Write processed DBStates to debug file
+	RestoreSnapshot          string
+	EventLogPath             string // Directory of the consensus event logs of the nodes
+	ReplayJournal            string // Journal to replay deterministically
+	ReplayExpected           string // Blocks the deterministic replay has to build
 }
@@ -18,6 +18,38 @@ type IDatabase interface {
 	DoesKeyExist(bucket, key []byte) (bool, error)
 }
 
+// IRangeDatabase is a database that can read part of a bucket, in key order, without
+// loading the whole bucket
+type IRangeDatabase interface {
+	// GetRange returns up to limit records of the bucket, or all of them if limit is 0.
+	// Going forward, they start at the first key >= start, going in reverse, at the last
+	// key <= start.  A nil start is the first or the last key of the bucket.
+	GetRange(bucket, start []byte, reverse bool, limit int, sample BinaryMarshallableAndCopyable) ([]BinaryMarshallableAndCopyable, [][]byte, error)
+}
+
+// IViewDatabase is a database that can give a consistent, point in time view of all
+// its records while writes go on
+type IViewDatabase interface {
+	// GetView opens a view of the database as it is now.  Databases that keep the
+	// bucket and the key of a record in one stored key use split to tell where the
+	// bucket ends.
+	GetView(split BucketSplitter) (IDatabaseView, error)
+}
+
+// BucketSplitter returns the length of the bucket a stored key starts with, when the
+// bucket is followed by a separator of sepLen bytes and the key, or -1 if it does not
+// know the bucket
+type BucketSplitter func(stored []byte, sepLen int) int
+
+// IDatabaseView is a read only view of a database.  It has to be released once read.
+type IDatabaseView interface {
+	Get(bucket []byte, key []byte, destination BinaryMarshallable) (BinaryMarshallable, error)
+	// ForEach calls f with every record of the view, and stops at the first error f
+	// returns.  The slices are only valid during the call.
+	ForEach(f func(bucket, key, value []byte) error) error
+	Release()
+}
+
 type Record struct {
 	Bucket []byte
 	Key    []byte
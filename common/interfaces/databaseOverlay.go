@@ -21,6 +21,7 @@ type DBOverlaySimple interface {
 	FetchDBlockHead() (IDirectoryBlock, error)
 	FetchEBlock(IHash) (IEntryBlock, error)
 	FetchEBlockHead(chainID IHash) (IEntryBlock, error)
+	FetchEBlockBySequence(chainID IHash, sequence uint32) (IEntryBlock, error)
 	FetchECBlock(IHash) (IEntryCreditBlock, error)
 	FetchECBlockByHeight(blockHeight uint32) (IEntryCreditBlock, error)
 	FetchECTransaction(hash IHash) (IECBlockEntry, error)
@@ -45,10 +46,37 @@ type DBOverlaySimple interface {
 	StartMultiBatch()
 	Trim()
 	FetchAllEntriesByChainID(chainID IHash) ([]IEBEntry, error)
+	FetchAllEntryIDsByChainID(chainID IHash) ([]IHash, error)
 	SaveKeyValueStore(kvs BinaryMarshallable, key []byte) error
 	FetchKeyValueStore(key []byte, dst BinaryMarshallable) (BinaryMarshallable, error)
 	SaveDatabaseEntryHeight(height uint32) error
 	FetchDatabaseEntryHeight() (uint32, error)
+	SetAddressIndex(enabled bool)
+	IsAddressIndexEnabled() bool
+	BackfillAddressIndex() error
+	FetchAddressTransactions(address IHash, start []byte, reverse bool, limit int) ([]IAddressTransaction, error)
+	PruneEBlockEntries(eblock IEntryBlock, keep map[[32]byte]bool) error
+	FetchEntryHashesByChainFrom(chainID IHash, height uint32) ([]IHash, error)
+	IsEntryPruned(hash IHash) (bool, error)
+	SavePrunedHeight(height uint32) error
+	FetchPrunedHeight() (uint32, error)
+	FetchAnchorEntries(dbHeight uint32) ([]IEBEntry, error)
+	RebuildAnchorIndex() error
+	SetExtIDIndex(enabled bool)
+	IsExtIDIndexEnabled() bool
+	BackfillExtIDIndex() error
+	RebuildExtIDIndex() error
+	FetchEntriesByExtID(chainID IHash, extID []byte) ([]IExtIDIndexEntry, error)
+	FetchEntriesByExtIDRange(chainID IHash, extID []byte, start []byte, reverse bool, limit int) ([]IExtIDIndexEntry, error)
+	SetChainInfo(enabled bool)
+	IsChainInfoEnabled() bool
+	BackfillChainInfo() error
+	FetchChainInfo(chainID IHash) (IChainInfo, error)
+	SetBalanceHistory(enabled bool)
+	IsBalanceHistoryEnabled() bool
+	BackfillBalanceHistory() error
+	FetchBalanceHistoryHeight() (uint32, error)
+	FetchBalanceAtHeight(ec bool, address IHash, height uint32) (int64, error)
 }
 
 // Db defines a generic interface that is used to request and insert data into db
@@ -101,6 +129,9 @@ type DBOverlay interface {
 	// FetchAllEBlocksByChain gets all of the blocks by chain id
 	FetchAllEBlocksByChain(IHash) ([]IEntryBlock, error)
 
+	// FetchEBlockBySequence gets the entry block of a chain with the given sequence number
+	FetchEBlockBySequence(chainID IHash, sequence uint32) (IEntryBlock, error)
+
 	SaveEBlockHead(block DatabaseBlockWithEntries, checkForDuplicateEntries bool) error
 
 	FetchEBlockHead(chainID IHash) (IEntryBlock, error)
@@ -262,6 +293,85 @@ type DBOverlay interface {
 	FetchKeyValueStore(key []byte, dst BinaryMarshallable) (BinaryMarshallable, error)
 	SaveDatabaseEntryHeight(height uint32) error
 	FetchDatabaseEntryHeight() (uint32, error)
+
+	//******************************AddressIndex**********************************//
+	SetAddressIndex(enabled bool)
+	IsAddressIndexEnabled() bool
+	BackfillAddressIndex() error
+	FetchAddressTransactions(address IHash, start []byte, reverse bool, limit int) ([]IAddressTransaction, error)
+
+	//******************************Pruning**********************************//
+	PruneEBlockEntries(eblock IEntryBlock, keep map[[32]byte]bool) error
+	FetchEntryHashesByChainFrom(chainID IHash, height uint32) ([]IHash, error)
+	IsEntryPruned(hash IHash) (bool, error)
+	SavePrunedHeight(height uint32) error
+	FetchPrunedHeight() (uint32, error)
+
+	//******************************AnchorIndex**********************************//
+	// FetchAnchorEntries gets the anchor chain entries anchoring a directory block
+	FetchAnchorEntries(dbHeight uint32) ([]IEBEntry, error)
+	RebuildAnchorIndex() error
+
+	//******************************ExtIDIndex**********************************//
+	SetExtIDIndex(enabled bool)
+	IsExtIDIndexEnabled() bool
+	BackfillExtIDIndex() error
+	RebuildExtIDIndex() error
+	FetchEntriesByExtID(chainID IHash, extID []byte) ([]IExtIDIndexEntry, error)
+	// FetchEntriesByExtIDRange gets a page of the entries of a chain with an external ID
+	FetchEntriesByExtIDRange(chainID IHash, extID []byte, start []byte, reverse bool, limit int) ([]IExtIDIndexEntry, error)
+
+	//******************************ChainInfo**********************************//
+	SetChainInfo(enabled bool)
+	IsChainInfoEnabled() bool
+	BackfillChainInfo() error
+	// FetchChainInfo gets the statistics of a chain, nil if the chain is not saved
+	FetchChainInfo(chainID IHash) (IChainInfo, error)
+
+	//****************************BalanceHistory*******************************//
+	SetBalanceHistory(enabled bool)
+	IsBalanceHistoryEnabled() bool
+	BackfillBalanceHistory() error
+	// FetchBalanceHistoryHeight gets the next height the balance history has to process
+	FetchBalanceHistoryHeight() (uint32, error)
+	// FetchBalanceAtHeight gets the balance of an address after the blocks of a height
+	FetchBalanceAtHeight(ec bool, address IHash, height uint32) (int64, error)
+}
+
+// IAddressTransaction is one record of the address index: a factoid or entry credit
+// transaction that touched an address
+type IAddressTransaction interface {
+	BinaryMarshallableAndCopyable
+
+	GetTxID() IHash
+	GetDBHeight() uint32
+	// IsInput is true when the address was debited by the transaction
+	IsInput() bool
+	GetAmount() uint64
+}
+
+// IExtIDIndexEntry is one record of the external ID index: an entry of a chain that
+// has a given external ID
+type IExtIDIndexEntry interface {
+	BinaryMarshallableAndCopyable
+
+	GetEntryHash() IHash
+	GetDBHeight() uint32
+}
+
+// IChainInfo holds the statistics of a chain
+type IChainInfo interface {
+	BinaryMarshallableAndCopyable
+
+	GetChainID() IHash
+	// GetCreationHeight is the height of the first entry block of the chain
+	GetCreationHeight() uint32
+	// GetFirstEntryHash is the zero hash if the first entry block is not saved yet
+	GetFirstEntryHash() IHash
+	GetEBlockCount() uint32
+	GetEntryCount() uint64
+	// GetPayloadBytes is the size of the external IDs and content of the saved entries
+	GetPayloadBytes() uint64
 }
 
 type ISCDatabaseOverlay interface {
@@ -24,6 +24,40 @@ type IProcessList interface {
 	GetDBHeight() uint32
 }
 
+// ProcessListStatus describes the process list of a block for the debug API
+type ProcessListStatus struct {
+	DBHeight      uint32     `json:"dbheight"`
+	Complete      bool       `json:"complete"`
+	CurrentMinute int        `json:"currentminute"` // of the node, for the process list it is building
+	Timestamp     int64      `json:"timestamp"`     // time of the node, milliseconds
+	VMs           []VMStatus `json:"vms"`
+}
+
+// VMStatus describes a VM of a process list.  Heights are the indexes of the messages
+// in the list of the VM.
+type VMStatus struct {
+	VM            int             `json:"vm"`
+	Leader        string          `json:"leader"` // identity chain of the leader for the minute of the VM
+	Height        int             `json:"height"` // messages processed
+	ListLength    int             `json:"listlength"`
+	Minute        int             `json:"minute"` // minute of the VM, from the EOMs processed
+	Synced        bool            `json:"synced"` // waiting on the other VMs at the end of the minute
+	Signed        bool            `json:"signed"` // the DBSig of the previous block is processed
+	Faulted       bool            `json:"faulted"`
+	Unprocessed   []int           `json:"unprocessed"`   // acknowledged but not processed, waiting on a message below them
+	Missing       []int           `json:"missing"`       // below the end of the list, with no acknowledged message
+	Asks          []MissingMsgAsk `json:"asks"`          // missing messages asked for and not received yet
+	LastProgress  int64           `json:"lastprogress"`  // time Height last moved, milliseconds
+	SinceProgress int64           `json:"sinceprogress"` // milliseconds
+}
+
+// MissingMsgAsk is a missing message request the process list still waits on
+type MissingMsgAsk struct {
+	Height  int   `json:"height"`
+	NextAsk int64 `json:"nextask"` // time the message is asked for next, milliseconds
+	Sent    int   `json:"sent"`    // number of requests sent
+}
+
 type IRequest interface {
 	//Key() (thekey [32]byte)
 }
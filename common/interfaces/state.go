@@ -11,6 +11,39 @@ type DBStateSent struct {
 	Sent     Timestamp
 }
 
+// SnapshotStatus describes the last database snapshot requested through the debug API
+type SnapshotStatus struct {
+	File     string `json:"file"`
+	Pending  bool   `json:"pending"` // waiting for the next block boundary
+	Running  bool   `json:"running"`
+	Height   uint32 `json:"height"`
+	Started  int64  `json:"started,omitempty"`
+	Finished int64  `json:"finished,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// The reasons a message is in the holding queue
+const (
+	HoldFutureHeight   = "future-height" // for a block or a minute after the one being built
+	HoldWaitingAck     = "ack"           // waiting for its acknowledgement
+	HoldWaitingMessage = "message"       // an acknowledgement waiting for its message
+	HoldWaitingCommit  = "commit"        // a reveal waiting for its commit
+	HoldWaitingEntry   = "entry"         // an acknowledged commit waiting for its entry
+	HoldWaitingProcess = "process"       // acknowledged, waiting for the process list to reach it
+)
+
+// HeldMessage describes a message in the holding queue for the debug API
+type HeldMessage struct {
+	Hash        string `json:"hash"` // message hash, the key of the holding queue
+	Type        string `json:"type"`
+	EntryHash   string `json:"entryhash,omitempty"` // of commits and reveals
+	VM          int    `json:"vm"`
+	Timestamp   int64  `json:"timestamp"` // of the message, milliseconds
+	Age         int64  `json:"age"`       // seconds since it was first seen in holding
+	Reason      string `json:"reason"`
+	ResendCount int    `json:"resendcount"`
+}
+
 // IQueue is the interface returned by returning queue functions
 type IQueue interface {
 	Length() int
@@ -76,6 +109,9 @@ type IState interface {
 	SetRpcAuthHash(authHash []byte)
 	GetRpcAuthHash() []byte
 	GetTlsInfo() (bool, string, string)
+	GetAPIRateLimits() (readRate float64, readBurst int, submitRate float64, submitBurst int)
+	GetAPIUsersFile() string
+	GetGRPCInfo() (enabled bool, port int)
 	GetFactomdLocations() string
 
 	// Routine for handling the syncroniztion of the leader and follower processes
@@ -285,6 +321,7 @@ type IState interface {
 	ExchangeRateAuthorityIsValid(IEBEntry) bool
 	FerEntryIsValid(passedFEREntry IFEREntry) bool
 	GetPredictiveFER() uint64
+	GetFERChange() (price uint64, height uint32)
 
 	// Identity Section
 	VerifyIsAuthority(cid IHash) bool // True if is authority
@@ -300,6 +337,7 @@ type IState interface {
 	GetAuthorityInterface(chainid IHash) IAuthority
 	GetLeaderPL() IProcessList
 	GetLLeaderHeight() uint32
+	GetPublishedLeaderHeight() uint32
 	GetEntryDBHeightComplete() uint32
 	GetMissingEntryCount() uint32
 	GetEntryBlockDBHeightProcessing() uint32
@@ -317,8 +355,17 @@ type IState interface {
 
 	// Access to Holding Queue
 	LoadHoldingMap() map[[32]byte]IMsg
+	LoadHoldingList() []HeldMessage
+	EvictHeldMessage(hash IHash, requeue bool) error
 	LoadAcksMap() map[[32]byte]IMsg
 
+	// Database snapshots
+	RequestSnapshot(filename string) (string, error)
+	GetSnapshotStatus() SnapshotStatus
+
+	// Process list inspection, nil if there is no process list at the height
+	GetProcessListStatus(dbheight uint32) *ProcessListStatus
+
 	// Plugins
 	UsingTorrent() bool
 	GetMissingDBState(height uint32) error
@@ -1,8 +1,6 @@
 package controlPanel
 
 import (
-	"crypto/sha256"
-	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	//"io/ioutil"
@@ -22,6 +20,7 @@ import (
 	"github.com/FactomProject/factomd/controlPanel/files"
 	"github.com/FactomProject/factomd/p2p"
 	"github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/wsapi"
 )
 
 // Initiates control panel variables and controls the http requests
@@ -243,6 +242,9 @@ func postHandler(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	case "changelogs":
+		if false == checkControlPanelPermission(w, r, wsapi.ControlPanelChangeLogs) {
+			return
+		}
 		// >= 2 means we have write access
 		if StatePointer.ControlPanelSetting == 2 {
 			newRegex := r.FormValue("logsetting")
@@ -817,7 +819,14 @@ func doEvery(d time.Duration, f func(time.Time)) {
 }
 
 func checkControlPanelPassword(response http.ResponseWriter, request *http.Request) bool {
-	if false == checkAuthHeader(request) {
+	return checkControlPanelPermission(response, request, wsapi.ControlPanelView)
+}
+
+// checkControlPanelPermission turns away unknown users, and users the API users file
+// does not allow to use the method
+func checkControlPanelPermission(response http.ResponseWriter, request *http.Request, method string) bool {
+	user, err := wsapi.Authenticate(StatePointer, request)
+	if err != nil {
 		remoteIP := ""
 		remoteIP += strings.Split(request.RemoteAddr, ":")[0]
 		fmt.Printf("Unauthorized Control Panel client connection attempt from %s\n", remoteIP)
@@ -825,28 +834,8 @@ func checkControlPanelPassword(response http.ResponseWriter, request *http.Reque
 		http.Error(response, "401 Unauthorized.", http.StatusUnauthorized)
 		return false
 	}
-	return true
-}
-
-func checkAuthHeader(r *http.Request) bool {
-	if "" == StatePointer.GetRpcUser() {
-		//no username was specified in the config file or command line, meaning factomd control panel is open access
-		return true
-	}
-
-	authhdr := r.Header["Authorization"]
-	if len(authhdr) == 0 {
-		return false
-	}
-
-	correctAuth := StatePointer.GetRpcAuthHash()
-
-	h := sha256.New()
-	h.Write([]byte(authhdr[0]))
-	presentedPassHash := h.Sum(nil)
-
-	cmp := subtle.ConstantTimeCompare(presentedPassHash, correctAuth) //compare hashes because ConstantTimeCompare takes a constant time based on the slice size.  hashing gives a constant slice size.
-	if cmp != 1 {
+	if !user.Allows(wsapi.APIControlPanel, method) {
+		http.Error(response, "403 Forbidden.", http.StatusForbidden)
 		return false
 	}
 	return true
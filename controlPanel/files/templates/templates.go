@@ -163,6 +163,13 @@ var staticFiles = map[string]*staticFilesFile{
 		mtime: time.Unix(1516307821, 0),
 		size:  1149,
 	},
+	"searchresults/type/extid.html": {
+		data:  "\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xedV_o\x9b0\x10\x7fN>\xc5\x0dE\xd3&\x8d\xb2\xbdR\xd7\xd2\x96Fj\xa5\xbd\xf5\x13\xb8p)V\x8d\xcd\xb0\xb36B|\xf7\x9d\xc14\x94\xd0\xa6\xed\xf3xH\xe0\xee\xfc\xbb\xdf\xfd\xb3\xdd49n\xa5F\x88\xf0\xd1\xc9<j\xdb\xe5\xa2i\x1c\x96\x95\x12\x8e\xa4\x05\x8a\x1c\xebN\xcc>\xc51\xfc2\xf9\x1e\xe2\x98/\x17\xc0,fN\x1a\x0d2\xbf\xa0\xd5\x9525Y\xf2%\x84\x87\xe5\xf2/dJX{\x11\xd5\xe6a\xa4\x99j3\xa3v\xa5\xb6\x13\x8b\xa6\xa9\x85\xbeCX\xc9o\xb0B\x85\x90^\xc0\x19\x11\xf1\xba\xc5\x82X\xae\xee\xbd\x08\xff\x90\x05|\x1f+\xe4\x16V\xf7\x07\xc1\x82\xd9R(\xc5\xd7\x85\x90:\x05&:\xc6[\x919S\xc6\x16E\x9d\x15\xb1\x92\xfa>\x02\xb7\xaf\x90\xe8x;\x1fx\xc4\xc9\x09y>\xbb\xd6\xd5\xce\xb5-K\x04gI\x0f\xf6D\x95\xf0\x81\x15?\xf8\xe6\xd1a\xad\x85\x82\xebK\x08\x0e\xbf\x84\xe5k\xa3\x1djwF&\xd7\x97m\xfbu\xc0`\xb6\x12\x1a\xac\xdb+\xf2\xbaUF\xb8\xb4\x96w\x85\x8b8LV\xfeF}\xe7\x8a\xb6\x85\x8dv\xb5DK\x08\xb4\x94\xc8\x90\xe3\x11\x93\xa6Ae1D\xde\x8b\x809qK\xc9\xf3!\x87X;\xc1$\xd9\x9d1s\xb7T\xde\x19\x05i\xea91\xc9s\xee)\xed\xe1J\xd8\"e\x09}\xbfdw2\xef\xe8\x81\xe6s>\x0fK\xf2\xfa\xddl\x0fe\xb2\xaf\xf3].\xc2\xc3v\xea\xe9\xa3oL\x08\x9dI\xa5\xa6\x0e<\xae\xb1\x05?0\x87\x15]\xc3+\xd9O\x8a\x8f2\xc6@#\x96}\x97\xf9\xbe`\x89\x92\x1c&\x9eP\xe7#0\x96\xec\xd4<\xe1\x8f\xa5h\x80\xa5\xc5!\x80\x17r\xf2ltO\x19tF]o\x1f\x02\xcez\xf8\xd8\xee\xcaR\xf82\x07\x7fp\xd3\x0b\xfc\\\xf2077\x85y\x80\x9fJ=\xcd\x09\xf5\xc0i\x87\xb75$\x1ch\x8b\xda;\xb4\xe9t\x82\xc2\xff0Ho\x87\x1bxv\x0d\xfe\x02\xaa\xd7\xbd\x07s\xb3&X\xeb\xe0\x08o\xb3\xf6\xf2\xb7@\xf5\x1b\x00\xbd}\xb8\x12~\xd6\xa3a\xf7\xc9\xa5\xa5\x1d\x7f\x9fj\xa3\xf1<\xe2\x94\xfc!\xf0\xf43\x96\xb6:?\xfe\x1d\x15\xecJ\xe6\xf8\xbf`\xafC\x15\xf5\xe9\x94\xcc\x93}{;\xbc>\xb6\xef\xdb\"H<\x7f\x1a\x90\xc2\x1f \x93sG\xe7\x13\x9aA\xf6\xac?YB\xa7\xbe\xdfK\x87\x17\xe2\xdd_\"x\xb8_lh\xbb;\xdc1\xc67\x11\x9b\xd5\xb2r6\x0an\xc6*g\x8c\xb2GW\x97\xad1\xae\xbf\xba\x04*\xff\x00!\xf1;\xfd\xec\x08\x00\x00",
+		hash:  "4b210e6aa02f54ec2100c962a3de1292ca6362562f3549c6b15165c4921b0808",
+		mime:  "text/html; charset=utf-8",
+		mtime: time.Unix(1792160608, 0),
+		size:  2284,
+	},
 	"searchresults/type/factoidack.html": {
 		data:  "\x1f\x8b\b\x00\x00\x00\x00\x00\x02\xff\x9c\x92Mn\xc20\x10\x85\xd7\xce)\\\xef\x83Ŷ\x1a\xbc\xa8h%\xd6\xe5\x02&\x1e\x14\vcG\xf6@AV\xee^\xe5\xa7j\x10)\xad\x9aU4Oy\xf9<\xfer6\xb8\xb7\x1e\xb9\xd8늂5\xba:\x88\xb6-X΄\xc7\xc6iB.j\xd4\x06c?\x86\xa7\xb2\xe4/\xc1\\yY\xaa\x82A\u008al\xf0ܚ\x95\xc0K\xe3B\xc4(T\xc1\x18\x18{\xe6\x95\xd3)\xadD\f\x1f\xfd\xecfX\x05w:\xfa4\x04\f\xea\xa5z\x1b\b\xf86j\x9f\xf4\xd0\xfbN\x9aN\td\xbdT\x05\x9fy\x80\xf4\xce\xe1|ƀv\xc1\\\u007f\b\x19P\xbc\x8fz\x162jʰY?\x83$3\xdfs\vc\x14\xe8~\x17\xfd6\x8feB\x1d\xab\xbat\xd6\x1f\x04\xa7k\x83CB\xdf\xedB\xe5\xbc\xd8^6\xeb\xb6\x05\xa9\xd5\xef?\x029\xc7}\x8b\xf1\xe0`_\v\xfd\xebyr^\f\x9ft|\xffgc \x1f\\\x06\xc8\xf1\x1a;Hi\xec\xb97h|\x019J\xa6F\xfd^\xbd\x99(8\x155U\xd16\x94:S\xbb\xdaiD!\xb8tg\xf6>\x04\x1a\xcc\xce\x19\xbdi\xdb\xcf\x00\x00\x00\xff\xff}\xcag\xb0\x10\x03\x00\x00",
 		hash:  "d3778c57993f99c57a010e02aaf2088588c9f9e59abce76a0219877b96a1faf4",
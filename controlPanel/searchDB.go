@@ -74,6 +74,22 @@ func searchDB(searchitem string, st state.State) (bool, string) {
 		bal := fmt.Sprintf("%.8f", float64(st.FactoidState.GetFactoidBalance(fixed))/1e8)
 		return true, `{"Type":"FA","item":` + bal + "}"
 	}
+	// "chainID:extID" looks for the entries of a chain with an external ID
+	if len(searchitem) > 65 && searchitem[64] == ':' {
+		chainID, err := primitives.HexToHash(searchitem[:64])
+		if err != nil {
+			return false, ""
+		}
+		dbase := st.GetDB()
+		if !dbase.IsExtIDIndexEnabled() {
+			return false, ""
+		}
+		list, err := dbase.FetchEntriesByExtID(chainID, []byte(searchitem[65:]))
+		if err != nil || len(list) == 0 {
+			return false, ""
+		}
+		return true, `{"Type":"extid","item":` + fmt.Sprintf("%d", len(list)) + "}"
+	}
 	if len(searchitem) == 64 {
 		hash, err := primitives.HexToHash(searchitem)
 		if err != nil {
@@ -104,6 +104,15 @@ func HandleSearchResult(content *SearchedStruct, w http.ResponseWriter) {
 		err = templates.ExecuteTemplate(w, content.Type, arr)
 		TemplateMutex.Unlock()
 		return
+	case "extid":
+		arr := getExtIDEntries(content.Input)
+		if arr == nil {
+			break
+		}
+		TemplateMutex.Lock()
+		err = templates.ExecuteTemplate(w, content.Type, arr)
+		TemplateMutex.Unlock()
+		return
 	case "eblock":
 		eblk := getEblock(content.Input)
 		if eblk == nil {
@@ -859,3 +868,48 @@ func getAllChainEntries(chainIDString string) []SearchedStruct {
 	}
 	return arr
 }
+
+// getExtIDEntries lists the entries found with a "chainID:extID" search, the chain ID
+// and external ID in the first element
+func getExtIDEntries(search string) []SearchedStruct {
+	if len(search) < 65 || search[64] != ':' {
+		return nil
+	}
+	chainID, err := primitives.HexToHash(search[:64])
+	if err != nil {
+		return nil
+	}
+	extID := search[65:]
+
+	dbase := StatePointer.GetDB()
+	if !dbase.IsExtIDIndexEnabled() {
+		return nil
+	}
+	list, err := dbase.FetchEntriesByExtID(chainID, []byte(extID))
+	if err != nil || len(list) == 0 {
+		return nil
+	}
+
+	arr := make([]SearchedStruct, 0)
+	s := new(SearchedStruct)
+	s.Type = "extid"
+	s.Input = chainID.String()
+	s.Content = struct {
+		ExtID  string
+		Length int
+	}{extID, len(list)}
+	arr = append(arr[:], *s)
+
+	for _, ie := range list {
+		s := new(SearchedStruct)
+		s.Type = "entry"
+		e := getEntry(ie.GetEntryHash().String())
+		if e == nil {
+			continue
+		}
+		s.Content = e
+		s.Input = ie.GetEntryHash().String()
+		arr = append(arr[:], *s)
+	}
+	return arr
+}
@@ -0,0 +1,511 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package badgerdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/dgraph-io/badger"
+)
+
+// BadgerDB is an IDatabase backed by badger, an embedded pure Go LSM store.  Unlike
+// the other backends, Trim() does real work: it garbage collects the value log, so
+// space freed by deletes and overwrites goes back to the disk.
+//
+// Keys are stored as the bucket length (2 bytes), the bucket and the key, so buckets
+// can be listed and no bucket is a prefix of another.
+type BadgerDB struct {
+	// lock preventing multiple entry
+	dbLock sync.RWMutex
+	bDB    *badger.DB
+	closed bool
+
+	// Compaction controls
+	GCInterval     time.Duration // how often Trim() runs the value log GC
+	GCDiscardRatio float64       // a value log file is rewritten if at least this ratio of it is garbage
+	lastGC         time.Time
+	gcRunning      int32 // atomic, 1 while a GC is in progress
+}
+
+var _ interfaces.IDatabase = (*BadgerDB)(nil)
+var _ interfaces.IRangeDatabase = (*BadgerDB)(nil)
+var _ interfaces.IViewDatabase = (*BadgerDB)(nil)
+
+var (
+	DefaultGCInterval     = 10 * time.Minute
+	DefaultGCDiscardRatio = 0.5
+)
+
+func NewBadgerDB(dir string, create bool) (interfaces.IDatabase, error) {
+	return OpenBadgerDB(dir, create)
+}
+
+// OpenBadgerDB opens the database, returning the concrete type for callers that
+// need the compaction, size and backup methods.
+func OpenBadgerDB(dir string, create bool) (*BadgerDB, error) {
+	if create == true {
+		err := os.MkdirAll(dir, 0750)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, err := os.Stat(dir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	opts := badger.DefaultOptions
+	opts.Dir = dir
+	opts.ValueDir = dir
+	bDB, err := badger.Open(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	db := new(BadgerDB)
+	db.bDB = bDB
+	db.GCInterval = DefaultGCInterval
+	db.GCDiscardRatio = DefaultGCDiscardRatio
+	db.lastGC = time.Now()
+	return db, nil
+}
+
+func bucketPrefix(bucket []byte) []byte {
+	prefix := make([]byte, 2, 2+len(bucket))
+	binary.BigEndian.PutUint16(prefix, uint16(len(bucket)))
+	return append(prefix, bucket...)
+}
+
+func CombineBucketAndKey(bucket []byte, key []byte) []byte {
+	prefix := bucketPrefix(bucket)
+	bKey := make([]byte, len(prefix), len(prefix)+len(key))
+	copy(bKey, prefix)
+	return append(bKey, key...)
+}
+
+// Close can be called more than once, badger itself can't
+func (db *BadgerDB) Close() error {
+	db.dbLock.Lock()
+	defer db.dbLock.Unlock()
+
+	if db.closed {
+		return nil
+	}
+	db.closed = true
+	return db.bDB.Close()
+}
+
+func (db *BadgerDB) Get(bucket []byte, key []byte, destination interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
+	db.dbLock.RLock()
+	defer db.dbLock.RUnlock()
+
+	BadgerDBGets.Inc()
+
+	var data []byte
+	err := db.bDB.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(CombineBucketAndKey(bucket, key))
+		if err != nil {
+			return err
+		}
+		data, err = item.ValueCopy(nil)
+		return err
+	})
+	if err == badger.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = destination.UnmarshalBinaryData(data)
+	if err != nil {
+		return nil, err
+	}
+	return destination, nil
+}
+
+func (db *BadgerDB) Put(bucket []byte, key []byte, data interfaces.BinaryMarshallable) error {
+	return db.PutInBatch([]interfaces.Record{{Bucket: bucket, Key: key, Data: data}})
+}
+
+// PutInBatch writes the records in one transaction, so a batch is saved entirely or
+// not at all.  A batch too big for a badger transaction is refused.
+func (db *BadgerDB) PutInBatch(records []interfaces.Record) error {
+	db.dbLock.Lock()
+	defer db.dbLock.Unlock()
+
+	txn := db.bDB.NewTransaction(true)
+	defer txn.Discard()
+
+	for _, v := range records {
+		bKey := CombineBucketAndKey(v.Bucket, v.Key)
+		data, err := v.Data.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		err = txn.Set(bKey, data)
+		if err == badger.ErrTxnTooBig {
+			return fmt.Errorf("A batch of %d records is too big for one badger transaction: %v", len(records), err)
+		}
+		if err != nil {
+			return err
+		}
+		BadgerDBPuts.Inc()
+	}
+	return commit(txn)
+}
+
+func commit(txn *badger.Txn) error {
+	return txn.Commit(nil)
+}
+
+func (db *BadgerDB) Delete(bucket []byte, key []byte) error {
+	db.dbLock.Lock()
+	defer db.dbLock.Unlock()
+
+	return db.bDB.Update(func(txn *badger.Txn) error {
+		return txn.Delete(CombineBucketAndKey(bucket, key))
+	})
+}
+
+func (db *BadgerDB) Clear(bucket []byte) error {
+	keys, err := db.ListAllKeys(bucket)
+	if err != nil {
+		return err
+	}
+
+	db.dbLock.Lock()
+	defer db.dbLock.Unlock()
+
+	txn := db.bDB.NewTransaction(true)
+	defer func() { txn.Discard() }()
+
+	for _, key := range keys {
+		bKey := CombineBucketAndKey(bucket, key)
+		err = txn.Delete(bKey)
+		if err == badger.ErrTxnTooBig {
+			err = commit(txn)
+			if err != nil {
+				return err
+			}
+			txn = db.bDB.NewTransaction(true)
+			err = txn.Delete(bKey)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return commit(txn)
+}
+
+func (db *BadgerDB) ListAllKeys(bucket []byte) ([][]byte, error) {
+	db.dbLock.RLock()
+	defer db.dbLock.RUnlock()
+
+	prefix := bucketPrefix(bucket)
+	var answer [][]byte
+	err := db.bDB.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			key := it.Item().KeyCopy(nil)
+			answer = append(answer, key[len(prefix):])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return answer, nil
+}
+
+func (db *BadgerDB) GetAll(bucket []byte, sample interfaces.BinaryMarshallableAndCopyable) ([]interfaces.BinaryMarshallableAndCopyable, [][]byte, error) {
+	db.dbLock.RLock()
+	defer db.dbLock.RUnlock()
+
+	prefix := bucketPrefix(bucket)
+	answer := []interfaces.BinaryMarshallableAndCopyable{}
+	keys := [][]byte{}
+	err := db.bDB.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			item := it.Item()
+			v, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			tmp := sample.New()
+			err = tmp.UnmarshalBinary(v)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, item.KeyCopy(nil)[len(prefix):])
+			answer = append(answer, tmp)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return answer, keys, nil
+}
+
+func (db *BadgerDB) GetRange(bucket, start []byte, reverse bool, limit int, sample interfaces.BinaryMarshallableAndCopyable) ([]interfaces.BinaryMarshallableAndCopyable, [][]byte, error) {
+	db.dbLock.RLock()
+	defer db.dbLock.RUnlock()
+
+	prefix := bucketPrefix(bucket)
+	answer := []interfaces.BinaryMarshallableAndCopyable{}
+	keys := [][]byte{}
+	err := db.bDB.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Reverse = reverse
+		if limit > 0 && limit < opts.PrefetchSize {
+			opts.PrefetchSize = limit
+		}
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		// In reverse, Seek finds the last key <= the one sought
+		switch {
+		case start != nil:
+			it.Seek(CombineBucketAndKey(bucket, start))
+		case reverse:
+			end := bucketEnd(prefix)
+			if end == nil {
+				it.Rewind()
+			} else {
+				it.Seek(end)
+				if it.Valid() && bytes.Equal(it.Item().Key(), end) {
+					it.Next()
+				}
+			}
+		default:
+			it.Seek(prefix)
+		}
+
+		for ; it.ValidForPrefix(prefix) && (limit == 0 || len(answer) < limit); it.Next() {
+			item := it.Item()
+			v, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			tmp := sample.New()
+			err = tmp.UnmarshalBinary(v)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, item.KeyCopy(nil)[len(prefix):])
+			answer = append(answer, tmp)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return answer, keys, nil
+}
+
+func (db *BadgerDB) ListAllBuckets() ([][]byte, error) {
+	db.dbLock.RLock()
+	defer db.dbLock.RUnlock()
+
+	answer := [][]byte{}
+	err := db.bDB.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		// Jump from bucket to bucket, by seeking past the last possible key of each
+		it.Rewind()
+		for it.Valid() {
+			key := it.Item().Key()
+			l := int(binary.BigEndian.Uint16(key[:2]))
+			bucket := append([]byte{}, key[2:2+l]...)
+			answer = append(answer, bucket)
+
+			end := bucketEnd(bucketPrefix(bucket))
+			if end == nil {
+				break
+			}
+			it.Seek(end)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return answer, nil
+}
+
+// bucketEnd is the first key past every key with the prefix
+func bucketEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		end[i]++
+		if end[i] != 0 {
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+// GetView opens a read only transaction, which sees the database as it is now
+func (db *BadgerDB) GetView(split interfaces.BucketSplitter) (interfaces.IDatabaseView, error) {
+	db.dbLock.RLock()
+	defer db.dbLock.RUnlock()
+
+	return &badgerDBView{txn: db.bDB.NewTransaction(false)}, nil
+}
+
+type badgerDBView struct {
+	txn *badger.Txn
+}
+
+func (v *badgerDBView) Get(bucket []byte, key []byte, destination interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
+	item, err := v.txn.Get(CombineBucketAndKey(bucket, key))
+	if err == badger.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err := item.ValueCopy(nil)
+	if err != nil {
+		return nil, err
+	}
+	_, err = destination.UnmarshalBinaryData(data)
+	if err != nil {
+		return nil, err
+	}
+	return destination, nil
+}
+
+func (v *badgerDBView) ForEach(f func(bucket, key, value []byte) error) error {
+	it := v.txn.NewIterator(badger.DefaultIteratorOptions)
+	defer it.Close()
+
+	for it.Rewind(); it.Valid(); it.Next() {
+		item := it.Item()
+		stored := item.Key()
+		if len(stored) < 2 || len(stored) < 2+int(binary.BigEndian.Uint16(stored[:2])) {
+			continue
+		}
+		l := int(binary.BigEndian.Uint16(stored[:2]))
+		data, err := item.Value()
+		if err != nil {
+			return err
+		}
+		err = f(stored[2:2+l], stored[2+l:], data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *badgerDBView) Release() {
+	v.txn.Discard()
+}
+
+func (db *BadgerDB) DoesKeyExist(bucket, key []byte) (bool, error) {
+	db.dbLock.RLock()
+	defer db.dbLock.RUnlock()
+
+	err := db.bDB.View(func(txn *badger.Txn) error {
+		_, err := txn.Get(CombineBucketAndKey(bucket, key))
+		return err
+	})
+	if err == badger.ErrKeyNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Trim is called often, so it only updates the size stats, and kicks off a value log GC
+// in the background once every GCInterval.
+func (db *BadgerDB) Trim() {
+	lsm, vlog := db.Size()
+	BadgerDBLSMSize.Set(float64(lsm))
+	BadgerDBVlogSize.Set(float64(vlog))
+
+	if time.Since(db.lastGC) < db.GCInterval {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&db.gcRunning, 0, 1) {
+		return
+	}
+	db.lastGC = time.Now()
+	go func() {
+		defer atomic.StoreInt32(&db.gcRunning, 0)
+		db.RunGC()
+	}()
+}
+
+// RunGC garbage collects the value log until there is nothing left worth rewriting
+func (db *BadgerDB) RunGC() error {
+	db.dbLock.RLock()
+	defer db.dbLock.RUnlock()
+
+	for {
+		err := db.bDB.RunValueLogGC(db.GCDiscardRatio)
+		if err == badger.ErrNoRewrite {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		BadgerDBGCRewrites.Inc()
+	}
+}
+
+// Compact flattens the LSM tree into a single level, then garbage collects the value log
+func (db *BadgerDB) Compact() error {
+	db.dbLock.RLock()
+	err := db.bDB.Flatten(1)
+	db.dbLock.RUnlock()
+	if err != nil {
+		return err
+	}
+	return db.RunGC()
+}
+
+// Size returns the bytes used on disk by the LSM tree and by the value log
+func (db *BadgerDB) Size() (lsm int64, vlog int64) {
+	return db.bDB.Size()
+}
+
+// Backup streams a consistent copy of the whole database to w, while the database
+// stays open for reads and writes.
+func (db *BadgerDB) Backup(w io.Writer) error {
+	_, err := db.bDB.Backup(w, 0)
+	return err
+}
+
+// Load restores a backup made with Backup into the database
+func (db *BadgerDB) Load(r io.Reader) error {
+	db.dbLock.Lock()
+	defer db.dbLock.Unlock()
+
+	return db.bDB.Load(r)
+}
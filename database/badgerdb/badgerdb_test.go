@@ -0,0 +1,258 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package badgerdb_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	. "github.com/FactomProject/factomd/database/badgerdb"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+type TestData struct {
+	Str string
+}
+
+func (t *TestData) New() interfaces.BinaryMarshallableAndCopyable {
+	return new(TestData)
+}
+
+func (t *TestData) MarshalBinary() ([]byte, error) {
+	return []byte(t.Str), nil
+}
+
+func (t *TestData) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	t.Str = string(data)
+	return nil, nil
+}
+
+func (t *TestData) UnmarshalBinary(data []byte) (err error) {
+	_, err = t.UnmarshalBinaryData(data)
+	return
+}
+
+var _ interfaces.BinaryMarshallable = (*TestData)(nil)
+
+var dbFilename string = "badgerTest.db"
+
+func CleanupTest(t *testing.T, b interfaces.IDatabase) {
+	err := b.Close()
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+	err = os.RemoveAll(dbFilename)
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestPutGetDelete(t *testing.T) {
+	m, err := NewBadgerDB(dbFilename, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer CleanupTest(t, m)
+
+	key := []byte("key")
+	bucket := []byte("bucket")
+
+	test := new(TestData)
+	test.Str = "testtest"
+
+	err = m.Put(bucket, key, test)
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+
+	resp, err := m.Get(bucket, key, new(TestData))
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+	if resp == nil || resp.(*TestData).Str != test.Str {
+		t.Errorf("data mismatch")
+	}
+
+	err = m.Delete(bucket, key)
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+
+	resp, err = m.Get(bucket, key, new(TestData))
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+	if resp != nil {
+		t.Errorf("resp is not nil while it should be")
+	}
+}
+
+// Buckets that are prefixes of one another must not see each others' keys
+func TestBucketsDoNotOverlap(t *testing.T) {
+	m, err := NewBadgerDB(dbFilename, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer CleanupTest(t, m)
+
+	buckets := [][]byte{[]byte("a"), []byte("a;"), []byte("ab"), {0xff}, {0xff, 0xff}}
+	for i, b := range buckets {
+		err = m.Put(b, []byte(fmt.Sprintf("key%d", i)), &TestData{Str: fmt.Sprintf("%d", i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i, b := range buckets {
+		keys, err := m.ListAllKeys(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(keys) != 1 || string(keys[0]) != fmt.Sprintf("key%d", i) {
+			t.Errorf("Bucket %x has keys %q", b, keys)
+		}
+	}
+
+	all, err := m.ListAllBuckets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != len(buckets) {
+		t.Fatalf("Listed %d buckets, expected %d", len(all), len(buckets))
+	}
+	for _, b := range buckets {
+		found := false
+		for _, a := range all {
+			if bytes.Equal(a, b) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Bucket %x not listed", b)
+		}
+	}
+
+	err = m.Clear([]byte("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, _ := m.ListAllKeys([]byte("a;"))
+	if len(keys) != 1 {
+		t.Error("Clearing a bucket removed keys of another")
+	}
+}
+
+func TestBigBatch(t *testing.T) {
+	m, err := NewBadgerDB(dbFilename, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer CleanupTest(t, m)
+
+	// Big enough to go over a single badger transaction
+	bucket := []byte("bucket")
+	value := &TestData{Str: string(make([]byte, 1000))}
+	batch := []interfaces.Record{}
+	for i := 0; i < 200000; i++ {
+		batch = append(batch, interfaces.Record{Bucket: bucket, Key: []byte(fmt.Sprintf("%08d", i)), Data: value})
+	}
+	err = m.PutInBatch(batch)
+	if err == nil {
+		t.Fatal("Put a batch too big for one transaction")
+	}
+	keys, err := m.ListAllKeys(bucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("A refused batch wrote %d keys", len(keys))
+	}
+
+	// Clear deletes more keys than fit in one transaction
+	for i := 0; i < len(batch); i += 1000 {
+		err = m.PutInBatch(batch[i : i+1000])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	keys, err = m.ListAllKeys(bucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != len(batch) {
+		t.Errorf("Got %d keys, expected %d", len(keys), len(batch))
+	}
+
+	err = m.Clear(bucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, _ = m.ListAllKeys(bucket)
+	if len(keys) != 0 {
+		t.Errorf("%d keys left after Clear", len(keys))
+	}
+}
+
+func TestBackupAndCompact(t *testing.T) {
+	m, err := OpenBadgerDB(dbFilename, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer CleanupTest(t, m)
+
+	dbo := databaseOverlay.NewOverlay(m)
+	testHelper.PopulateTestDatabaseOverlay(dbo)
+
+	err = m.Compact()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lsm, vlog := m.Size()
+	if lsm+vlog <= 0 {
+		t.Errorf("Database reports a size of %d + %d", lsm, vlog)
+	}
+
+	var backup bytes.Buffer
+	err = m.Backup(&backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restoredName := dbFilename + "-restored"
+	defer os.RemoveAll(restoredName)
+	restored, err := OpenBadgerDB(restoredName, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restored.Close()
+	err = restored.Load(&backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	head, err := databaseOverlay.NewOverlay(restored).FetchDBlockHead()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head == nil || head.GetDatabaseHeight() != uint32(testHelper.BlockCount-1) {
+		t.Errorf("Restored database has head %v", head)
+	}
+
+	buckets, err := m.ListAllBuckets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range buckets {
+		keys, _ := m.ListAllKeys(b)
+		restoredKeys, _ := restored.ListAllKeys(b)
+		if len(keys) != len(restoredKeys) {
+			t.Errorf("Bucket %x has %d keys, %d restored", b, len(keys), len(restoredKeys))
+		}
+	}
+}
@@ -5,6 +5,7 @@
 package boltdb
 
 import (
+	"bytes"
 	"fmt"
 	"sync"
 
@@ -37,6 +38,8 @@ type BoltDB struct {
 }
 
 var _ interfaces.IDatabase = (*BoltDB)(nil)
+var _ interfaces.IRangeDatabase = (*BoltDB)(nil)
+var _ interfaces.IViewDatabase = (*BoltDB)(nil)
 
 func NewBoltDB(bucketList [][]byte, filename string) *BoltDB {
 	db := new(BoltDB)
@@ -248,6 +251,111 @@ func (db *BoltDB) GetAll(bucket []byte, sample interfaces.BinaryMarshallableAndC
 	return answer, keys, nil
 }
 
+func (db *BoltDB) GetRange(bucket, start []byte, reverse bool, limit int, sample interfaces.BinaryMarshallableAndCopyable) ([]interfaces.BinaryMarshallableAndCopyable, [][]byte, error) {
+	db.Sem.Lock()
+	defer db.Sem.Unlock()
+
+	answer := []interfaces.BinaryMarshallableAndCopyable{}
+	keys := [][]byte{}
+	err := db.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		c := b.Cursor()
+
+		var k, v []byte
+		switch {
+		case start == nil && reverse:
+			k, v = c.Last()
+		case start == nil:
+			k, v = c.First()
+		case reverse:
+			k, v = c.Seek(start)
+			if k == nil {
+				k, v = c.Last()
+			} else if !bytes.Equal(k, start) {
+				k, v = c.Prev()
+			}
+		default:
+			k, v = c.Seek(start)
+		}
+
+		for k != nil && (limit == 0 || len(answer) < limit) {
+			tmp := sample.New()
+			err := tmp.UnmarshalBinary(v)
+			if err != nil {
+				return err
+			}
+			// Keys are only valid for the life of the transaction
+			keys = append(keys, append([]byte{}, k...))
+			answer = append(answer, tmp)
+
+			if reverse {
+				k, v = c.Prev()
+			} else {
+				k, v = c.Next()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return answer, keys, nil
+}
+
+// GetView opens a read transaction, which sees the database as it is now.  While it
+// is open, bolt can not grow its memory map, so a write that needs a bigger file waits
+// for the view to be released.
+func (db *BoltDB) GetView(split interfaces.BucketSplitter) (interfaces.IDatabaseView, error) {
+	db.Sem.RLock()
+	defer db.Sem.RUnlock()
+
+	tx, err := db.db.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	return &boltDBView{tx: tx}, nil
+}
+
+type boltDBView struct {
+	tx *bolt.Tx
+}
+
+func (v *boltDBView) Get(bucket []byte, key []byte, destination interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
+	b := v.tx.Bucket(bucket)
+	if b == nil {
+		return nil, nil
+	}
+	data := b.Get(key)
+	if data == nil {
+		return nil, nil
+	}
+
+	// The data belongs to bolt, and must not be kept past the transaction
+	_, err := destination.UnmarshalBinaryData(append([]byte{}, data...))
+	if err != nil {
+		return nil, err
+	}
+	return destination, nil
+}
+
+func (v *boltDBView) ForEach(f func(bucket, key, value []byte) error) error {
+	return v.tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+		return b.ForEach(func(k, data []byte) error {
+			if data == nil { // A nested bucket
+				return nil
+			}
+			return f(name, k, data)
+		})
+	})
+}
+
+func (v *boltDBView) Release() {
+	v.tx.Rollback()
+}
+
 // We have to make accommodation for many Init functions.  But what we really
 // want here is:
 //
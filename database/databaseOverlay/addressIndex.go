@@ -0,0 +1,308 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The address index keeps one bucket per address, ADDRESS_TRANSACTIONS + address.
+// Keys are height (4 bytes, big endian) + transaction ID + direction, so a bucket
+// lists the transactions of an address in block order.
+//
+// Factoid addresses are indexed from the inputs and outputs of factoid transactions.
+// Entry credit addresses are indexed from the entry credit block: commits debit the
+// address, balance increases (the EC outputs of factoid transactions) credit it.
+// Commits are indexed by their signature hash, the ID the APIs give them.
+
+// AddressIndexHeightKey holds the next height the address index has to process
+var AddressIndexHeightKey = []byte("AddressIndexHeight")
+
+type AddressTransaction struct {
+	TxID     interfaces.IHash
+	DBHeight uint32
+	Input    bool
+	Amount   uint64
+}
+
+var _ interfaces.IAddressTransaction = (*AddressTransaction)(nil)
+
+func (e *AddressTransaction) GetTxID() interfaces.IHash {
+	return e.TxID
+}
+
+func (e *AddressTransaction) GetDBHeight() uint32 {
+	return e.DBHeight
+}
+
+func (e *AddressTransaction) IsInput() bool {
+	return e.Input
+}
+
+func (e *AddressTransaction) GetAmount() uint64 {
+	return e.Amount
+}
+
+func (e *AddressTransaction) New() interfaces.BinaryMarshallableAndCopyable {
+	return new(AddressTransaction)
+}
+
+func (e *AddressTransaction) MarshalBinary() ([]byte, error) {
+	buf := primitives.NewBuffer(nil)
+	if e.TxID == nil {
+		e.TxID = primitives.NewZeroHash()
+	}
+	err := buf.PushIHash(e.TxID)
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PushUInt32(e.DBHeight)
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PushBool(e.Input)
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PushUInt64(e.Amount)
+	if err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (e *AddressTransaction) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	buf := primitives.NewBuffer(data)
+	var err error
+	e.TxID, err = buf.PopIHash()
+	if err != nil {
+		return nil, err
+	}
+	e.DBHeight, err = buf.PopUInt32()
+	if err != nil {
+		return nil, err
+	}
+	e.Input, err = buf.PopBool()
+	if err != nil {
+		return nil, err
+	}
+	e.Amount, err = buf.PopUInt64()
+	if err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (e *AddressTransaction) UnmarshalBinary(data []byte) error {
+	_, err := e.UnmarshalBinaryData(data)
+	return err
+}
+
+func (e *AddressTransaction) key() []byte {
+	return AddressTransactionKey(e.DBHeight, e.TxID, e.Input)
+}
+
+// AddressTransactionKey is the key of a transaction in the bucket of an address in the
+// address index: height, transaction ID, inputs before outputs.  With a nil
+// transaction ID, it is the key every transaction of the height sorts after.
+func AddressTransactionKey(height uint32, txID interfaces.IHash, input bool) []byte {
+	key := make([]byte, 4, 4+constants.HASH_LENGTH+1)
+	binary.BigEndian.PutUint32(key, height)
+	if txID == nil {
+		return key
+	}
+	key = append(key, txID.Bytes()...)
+	if input {
+		key = append(key, 0)
+	} else {
+		key = append(key, 1)
+	}
+	return key
+}
+
+func addressIndexBucket(address []byte) []byte {
+	bucket := make([]byte, 0, len(ADDRESS_TRANSACTIONS)+len(address))
+	bucket = append(bucket, ADDRESS_TRANSACTIONS...)
+	return append(bucket, address...)
+}
+
+// addressIndexBuilder sums the amounts of an address appearing more than once on the
+// same side of a transaction, so every (address, transaction, direction) has one record
+type addressIndexBuilder struct {
+	records []interfaces.Record
+	seen    map[string]*AddressTransaction
+}
+
+func newAddressIndexBuilder() *addressIndexBuilder {
+	b := new(addressIndexBuilder)
+	b.seen = map[string]*AddressTransaction{}
+	return b
+}
+
+func (b *addressIndexBuilder) add(address []byte, txID interfaces.IHash, height uint32, input bool, amount uint64) {
+	at := new(AddressTransaction)
+	at.TxID = txID
+	at.DBHeight = height
+	at.Input = input
+	at.Amount = amount
+
+	bucket := addressIndexBucket(address)
+	key := at.key()
+	if prev, ok := b.seen[string(bucket)+string(key)]; ok {
+		prev.Amount += amount
+		return
+	}
+	b.seen[string(bucket)+string(key)] = at
+	b.records = append(b.records, interfaces.Record{bucket, key, at})
+}
+
+func (b *addressIndexBuilder) addFBlock(block interfaces.IFBlock) {
+	height := block.GetDatabaseHeight()
+	for _, tx := range block.GetTransactions() {
+		txID := tx.GetSigHash()
+		for _, in := range tx.GetInputs() {
+			b.add(in.GetAddress().Bytes(), txID, height, true, in.GetAmount())
+		}
+		for _, out := range tx.GetOutputs() {
+			b.add(out.GetAddress().Bytes(), txID, height, false, out.GetAmount())
+		}
+	}
+}
+
+func (b *addressIndexBuilder) addECBlock(block interfaces.IEntryCreditBlock) {
+	height := block.GetHeader().GetDBHeight()
+	for _, entry := range block.GetBody().GetEntries() {
+		switch entry.ECID() {
+		case constants.ECIDChainCommit:
+			cc := entry.(*entryCreditBlock.CommitChain)
+			b.add(cc.ECPubKey[:], entry.GetSigHash(), height, true, uint64(cc.Credits))
+		case constants.ECIDEntryCommit:
+			ce := entry.(*entryCreditBlock.CommitEntry)
+			b.add(ce.ECPubKey[:], entry.GetSigHash(), height, true, uint64(ce.Credits))
+		case constants.ECIDBalanceIncrease:
+			ib := entry.(*entryCreditBlock.IncreaseBalance)
+			b.add(ib.ECPubKey[:], ib.TXID, height, false, ib.NumEC)
+		}
+	}
+}
+
+func addressIndexHeightRecord(next uint32) interfaces.Record {
+	buf := primitives.NewBuffer(nil)
+	buf.PushUInt32(next)
+	bs := new(primitives.ByteSlice)
+	bs.Bytes = buf.DeepCopyBytes()
+	return interfaces.Record{KEY_VALUE_STORE, AddressIndexHeightKey, bs}
+}
+
+func (db *Overlay) SetAddressIndex(enabled bool) {
+	db.AddressIndex = enabled
+}
+
+func (db *Overlay) IsAddressIndexEnabled() bool {
+	return db.AddressIndex
+}
+
+func (db *Overlay) SaveAddressIndexFromFBlockMultiBatch(block interfaces.IFBlock) {
+	if !db.AddressIndex || block == nil {
+		return
+	}
+	b := newAddressIndexBuilder()
+	b.addFBlock(block)
+	db.PutInMultiBatch(b.records)
+}
+
+func (db *Overlay) SaveAddressIndexFromECBlockMultiBatch(block interfaces.IEntryCreditBlock) {
+	if !db.AddressIndex || block == nil {
+		return
+	}
+	b := newAddressIndexBuilder()
+	b.addECBlock(block)
+	db.PutInMultiBatch(b.records)
+	// The EC block is saved with the factoid block of the same height, so this marks
+	// the whole height as indexed
+	db.PutInMultiBatch([]interfaces.Record{addressIndexHeightRecord(block.GetHeader().GetDBHeight() + 1)})
+}
+
+// FetchAddressIndexHeight returns the next height the address index has to process
+func (db *Overlay) FetchAddressIndexHeight() (uint32, error) {
+	bs := new(primitives.ByteSlice)
+	loaded, err := db.FetchKeyValueStore(AddressIndexHeightKey, bs)
+	if err != nil {
+		return 0, err
+	}
+	if loaded == nil {
+		return 0, nil
+	}
+	buf := primitives.NewBuffer(bs.Bytes)
+	return buf.PopUInt32()
+}
+
+// BackfillAddressIndex indexes the blocks saved while the address index was disabled,
+// from the last indexed height up to the directory block head.  It saves its progress
+// after every height, so it can be interrupted and resumed.  It must not run while
+// blocks are being saved.
+func (db *Overlay) BackfillAddressIndex() error {
+	next, err := db.FetchAddressIndexHeight()
+	if err != nil {
+		return err
+	}
+	head, err := db.FetchDBlockHead()
+	if err != nil {
+		return err
+	}
+	if head == nil {
+		return nil
+	}
+
+	for height := next; height <= head.GetDatabaseHeight(); height++ {
+		b := newAddressIndexBuilder()
+
+		fblock, err := db.FetchFBlockByHeight(height)
+		if err != nil {
+			return err
+		}
+		if fblock != nil {
+			b.addFBlock(fblock)
+		}
+		ecblock, err := db.FetchECBlockByHeight(height)
+		if err != nil {
+			return err
+		}
+		if ecblock != nil {
+			b.addECBlock(ecblock)
+		}
+		if fblock == nil && ecblock == nil {
+			return fmt.Errorf("Missing factoid and entry credit blocks at height %d", height)
+		}
+
+		err = db.DB.PutInBatch(append(b.records, addressIndexHeightRecord(height+1)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FetchAddressTransactions returns up to limit transactions that touched the given
+// factoid or entry credit address, in block order or in reverse.  They start at the
+// address index key start, see interfaces.IRangeDatabase, and a limit of 0 reads them
+// all.
+func (db *Overlay) FetchAddressTransactions(address interfaces.IHash, start []byte, reverse bool, limit int) ([]interfaces.IAddressTransaction, error) {
+	list, _, err := db.GetRange(addressIndexBucket(address.Bytes()), start, reverse, limit, new(AddressTransaction))
+	if err != nil {
+		return nil, err
+	}
+	answer := make([]interfaces.IAddressTransaction, len(list))
+	for i, v := range list {
+		answer[i] = v.(interfaces.IAddressTransaction)
+	}
+	return answer, nil
+}
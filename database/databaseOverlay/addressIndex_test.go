@@ -0,0 +1,177 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	. "github.com/FactomProject/factomd/testHelper"
+)
+
+func TestAddressTransactionMarshal(t *testing.T) {
+	at := new(databaseOverlay.AddressTransaction)
+	at.TxID = CreateFullTestBlockSet()[0].FBlock.GetTransactions()[0].GetSigHash()
+	at.DBHeight = 1234
+	at.Input = true
+	at.Amount = 5678
+
+	b, err := at.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	at2 := new(databaseOverlay.AddressTransaction)
+	err = at2.UnmarshalBinary(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !at2.TxID.IsSameAs(at.TxID) || at2.DBHeight != at.DBHeight || at2.Input != at.Input || at2.Amount != at.Amount {
+		t.Errorf("Got %v, expected %v", at2, at)
+	}
+}
+
+func TestAddressIndex(t *testing.T) {
+	blocks := CreateFullTestBlockSet()
+
+	live := CreateEmptyTestDatabaseOverlay()
+	live.SetAddressIndex(true)
+	PopulateTestDatabaseOverlay(live)
+
+	next, err := live.FetchAddressIndexHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != uint32(len(blocks)) {
+		t.Errorf("Index height is %d, expected %d", next, len(blocks))
+	}
+
+	backfilled := CreateAndPopulateTestDatabaseOverlay()
+	all, err := backfilled.FetchAddressTransactions(blocks[0].FBlock.GetTransactions()[0].GetOutputs()[0].GetAddress(), nil, false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 0 {
+		t.Error("Addresses were indexed with the index disabled")
+	}
+	err = backfilled.BackfillAddressIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, block := range blocks {
+		height := block.FBlock.GetDatabaseHeight()
+		for _, tx := range block.FBlock.GetTransactions() {
+			check := func(adr interfaces.ITransAddress, input bool) {
+				for _, dbo := range []interfaces.DBOverlay{live, backfilled} {
+					list, err := dbo.FetchAddressTransactions(adr.GetAddress(), nil, false, 0)
+					if err != nil {
+						t.Fatal(err)
+					}
+					found := false
+					for _, v := range list {
+						if v.GetTxID().IsSameAs(tx.GetSigHash()) && v.IsInput() == input {
+							found = true
+							if v.GetDBHeight() != height {
+								t.Errorf("Got height %d, expected %d", v.GetDBHeight(), height)
+							}
+							if v.GetAmount() < adr.GetAmount() {
+								t.Errorf("Got amount %d, expected at least %d", v.GetAmount(), adr.GetAmount())
+							}
+						}
+					}
+					if !found {
+						t.Errorf("Transaction %v not indexed for address %v", tx.GetSigHash(), adr.GetAddress())
+					}
+					for i := 1; i < len(list); i++ {
+						if list[i].GetDBHeight() < list[i-1].GetDBHeight() {
+							t.Error("Transactions are not in block order")
+						}
+					}
+				}
+			}
+			for _, in := range tx.GetInputs() {
+				check(in, true)
+			}
+			for _, out := range tx.GetOutputs() {
+				check(out, false)
+			}
+		}
+
+		// Commits are indexed by their signature hash
+		for _, entry := range block.ECBlock.GetBody().GetEntries() {
+			var ecPubKey []byte
+			switch e := entry.(type) {
+			case *entryCreditBlock.CommitChain:
+				ecPubKey = e.ECPubKey[:]
+			case *entryCreditBlock.CommitEntry:
+				ecPubKey = e.ECPubKey[:]
+			default:
+				continue
+			}
+			for _, dbo := range []interfaces.DBOverlay{live, backfilled} {
+				list, err := dbo.FetchAddressTransactions(primitives.NewHash(ecPubKey), nil, false, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				found := false
+				for _, v := range list {
+					if v.GetTxID().IsSameAs(entry.GetSigHash()) && v.IsInput() && v.GetDBHeight() == height {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("Commit %v not indexed by its signature hash", entry.GetSigHash())
+				}
+			}
+		}
+	}
+
+	// Reading part of the history of an address with a few transactions
+	var address interfaces.IHash
+	for _, block := range blocks {
+		for _, tx := range block.FBlock.GetTransactions() {
+			for _, out := range tx.GetOutputs() {
+				all, err = live.FetchAddressTransactions(out.GetAddress(), nil, false, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if address == nil && len(all) >= 3 {
+					address = out.GetAddress()
+				}
+			}
+		}
+	}
+	if address == nil {
+		t.Fatal("No test address has 3 transactions")
+	}
+	all, err = live.FetchAddressTransactions(address, nil, false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last, err := live.FetchAddressTransactions(address, nil, true, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(last) != 1 || !last[0].GetTxID().IsSameAs(all[len(all)-1].GetTxID()) {
+		t.Errorf("Got %v, expected the last transaction", last)
+	}
+	key := databaseOverlay.AddressTransactionKey(all[1].GetDBHeight(), all[1].GetTxID(), all[1].IsInput())
+	page, err := live.FetchAddressTransactions(address, key, false, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || !page[0].GetTxID().IsSameAs(all[1].GetTxID()) {
+		t.Errorf("Got %v, expected the second transaction", page)
+	}
+
+	// Running it again has nothing left to do
+	err = backfilled.BackfillAddressIndex()
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -5,8 +5,11 @@
 package databaseOverlay
 
 import (
-	//"fmt"
+	"bytes"
+	"encoding/binary"
+	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/FactomProject/factomd/anchor"
 	"github.com/FactomProject/factomd/common/directoryBlock/dbInfo"
@@ -32,6 +35,230 @@ func init() {
 	}
 }
 
+// SetAnchorSigningKeys replaces the keys anchor records must be signed with, for
+// networks that are not anchored with the mainnet keys
+func SetAnchorSigningKeys(keys []string) error {
+	pubKeys := []interfaces.Verifier{}
+	for _, v := range keys {
+		v = strings.TrimSpace(v)
+		if len(v) != 64 {
+			return fmt.Errorf("Invalid anchor signing key %q", v)
+		}
+		pubKey := new(primitives.PublicKey)
+		err := pubKey.UnmarshalText([]byte(v))
+		if err != nil {
+			return fmt.Errorf("Invalid anchor signing key %q: %v", v, err)
+		}
+		pubKeys = append(pubKeys, pubKey)
+	}
+	if len(pubKeys) == 0 {
+		return fmt.Errorf("No anchor signing key")
+	}
+	AnchorSigKeys = keys
+	AnchorSigPublicKeys = pubKeys
+	return nil
+}
+
+// The anchor index maps a directory block to the anchor chain entries anchoring it.
+// Keys are the directory block height (4 bytes, big endian) and the anchored
+// network, values the entry hash.  Only records signed by an anchor signing key and
+// naming the saved directory block at their height are indexed; a block anchored
+// twice on the same network keeps the first of its records.
+
+const (
+	AnchorNetworkBitcoin  byte = 0
+	AnchorNetworkEthereum byte = 1
+)
+
+// AnchorIndexKeysKey holds the anchor signing keys the anchor index was built with
+var AnchorIndexKeysKey = []byte("AnchorIndexKeys")
+
+func anchorIndexKey(dbHeight uint32, network byte) []byte {
+	key := make([]byte, 5)
+	binary.BigEndian.PutUint32(key, dbHeight)
+	key[4] = network
+	return key
+}
+
+func anchorIndexRecords(entry interfaces.IEBEntry, ar *anchor.AnchorRecord) []interfaces.Record {
+	records := []interfaces.Record{}
+	if ar.Bitcoin != nil {
+		records = append(records, interfaces.Record{ANCHOR_RECORD, anchorIndexKey(ar.DBHeight, AnchorNetworkBitcoin), entry.GetHash()})
+	}
+	if ar.Ethereum != nil {
+		records = append(records, interfaces.Record{ANCHOR_RECORD, anchorIndexKey(ar.DBHeight, AnchorNetworkEthereum), entry.GetHash()})
+	}
+	return records
+}
+
+// newAnchorIndexRecords returns the index records of an anchor record, without the
+// networks the block is already indexed for, in the database or in pending.  It
+// returns nothing if the record does not anchor the directory block saved at its
+// height.  The keys of the returned records are added to pending.
+func (dbo *Overlay) newAnchorIndexRecords(entry interfaces.IEBEntry, ar *anchor.AnchorRecord, pending map[string]bool) ([]interfaces.Record, error) {
+	keyMR, err := dbo.FetchDBKeyMRByHeight(ar.DBHeight)
+	if err != nil {
+		return nil, err
+	}
+	if keyMR == nil || !strings.EqualFold(keyMR.String(), ar.KeyMR) {
+		return nil, nil
+	}
+
+	records := []interfaces.Record{}
+	for _, r := range anchorIndexRecords(entry, ar) {
+		if pending[string(r.Key)] {
+			continue
+		}
+		exists, err := dbo.DB.DoesKeyExist(ANCHOR_RECORD, r.Key)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			continue
+		}
+		if pending != nil {
+			pending[string(r.Key)] = true
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+// anchorsBitcoin tells if records hold the Bitcoin anchor of a block
+func anchorsBitcoin(records []interfaces.Record) bool {
+	for _, r := range records {
+		if r.Key[4] == AnchorNetworkBitcoin {
+			return true
+		}
+	}
+	return false
+}
+
+// anchorSigningKeysString identifies the set of anchor signing keys
+func anchorSigningKeysString() string {
+	keys := []string{}
+	for _, v := range AnchorSigPublicKeys {
+		keys = append(keys, v.String())
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// validAnchorRecord returns the anchor record of an anchor chain entry, or nil if the
+// entry does not hold a record signed by an anchor signing key
+func validAnchorRecord(entry interfaces.IEBEntry) (*anchor.AnchorRecord, error) {
+	if entry.DatabasePrimaryIndex().String() == "24674e6bc3094eb773297de955ee095a05830e431da13a37382dcdc89d73c7d7" {
+		return nil, nil
+	}
+	ar, ok, err := anchor.UnmarshalAndValidateAnchorEntryAnyVersion(entry, AnchorSigPublicKeys)
+	if err != nil {
+		return nil, err
+	}
+	if ok == false {
+		return nil, nil
+	}
+	return ar, nil
+}
+
+// FetchAnchorEntries returns the anchor chain entries anchoring the directory block
+// at dbHeight, the Bitcoin anchor before the Ethereum one.  An entry anchoring the
+// block on both networks is returned once.
+func (dbo *Overlay) FetchAnchorEntries(dbHeight uint32) ([]interfaces.IEBEntry, error) {
+	answer := []interfaces.IEBEntry{}
+	var last interfaces.IHash
+	for _, network := range []byte{AnchorNetworkBitcoin, AnchorNetworkEthereum} {
+		h, err := dbo.DB.Get(ANCHOR_RECORD, anchorIndexKey(dbHeight, network), new(primitives.Hash))
+		if err != nil {
+			return nil, err
+		}
+		if h == nil {
+			continue
+		}
+		hash := h.(*primitives.Hash)
+		if last != nil && last.IsSameAs(hash) {
+			continue
+		}
+		last = hash
+		entry, err := dbo.FetchEntry(hash)
+		if err != nil {
+			return nil, err
+		}
+		if entry != nil {
+			answer = append(answer, entry)
+		}
+	}
+	return answer, nil
+}
+
+// RebuildAnchorIndex indexes the whole anchor chain, if the anchor index is missing
+// or was built with other anchor signing keys
+func (dbo *Overlay) RebuildAnchorIndex() error {
+	keys := anchorSigningKeysString()
+	bs := new(primitives.ByteSlice)
+	loaded, err := dbo.FetchKeyValueStore(AnchorIndexKeysKey, bs)
+	if err != nil {
+		return err
+	}
+	if loaded != nil && string(bs.Bytes) == keys {
+		return nil
+	}
+
+	old, err := dbo.DB.ListAllKeys(ANCHOR_RECORD)
+	if err != nil {
+		return err
+	}
+	for _, key := range old {
+		err = dbo.DB.Delete(ANCHOR_RECORD, key)
+		if err != nil {
+			return err
+		}
+	}
+
+	chainID, err := primitives.NewShaHashFromStr(AnchorBlockID)
+	if err != nil {
+		return err
+	}
+	// The chain is walked in order, so the first record of a block is kept as when
+	// the entries were saved
+	batch := []interfaces.Record{}
+	pending := map[string]bool{}
+	for sequence := uint32(0); ; sequence++ {
+		eblock, err := dbo.FetchEBlockBySequence(chainID, sequence)
+		if err != nil {
+			return err
+		}
+		if eblock == nil {
+			break
+		}
+		for _, h := range eblock.GetEntryHashes() {
+			if h.IsMinuteMarker() {
+				continue
+			}
+			entry, err := dbo.FetchEntry(h)
+			if err != nil {
+				return err
+			}
+			if entry == nil {
+				continue
+			}
+			ar, err := validAnchorRecord(entry)
+			if err != nil || ar == nil {
+				continue
+			}
+			records, err := dbo.newAnchorIndexRecords(entry, ar, pending)
+			if err != nil {
+				return err
+			}
+			batch = append(batch, records...)
+		}
+	}
+	err = dbo.DB.PutInBatch(batch)
+	if err != nil {
+		return err
+	}
+	return dbo.SaveKeyValueStore(&primitives.ByteSlice{Bytes: []byte(keys)}, AnchorIndexKeysKey)
+}
+
 func (dbo *Overlay) RebuildDirBlockInfo() error {
 	ars, err := dbo.FetchAllAnchorInfo()
 	if err != nil {
@@ -46,17 +273,22 @@ func (dbo *Overlay) RebuildDirBlockInfo() error {
 }
 
 func (dbo *Overlay) SaveAnchorInfoFromEntry(entry interfaces.IEBEntry) error {
-	if entry.DatabasePrimaryIndex().String() == "24674e6bc3094eb773297de955ee095a05830e431da13a37382dcdc89d73c7d7" {
+	ar, err := validAnchorRecord(entry)
+	if err != nil {
+		return err
+	}
+	if ar == nil {
 		return nil
 	}
-	ar, ok, err := anchor.UnmarshalAndValidateAnchorEntryAnyVersion(entry, AnchorSigPublicKeys)
+	records, err := dbo.newAnchorIndexRecords(entry, ar, nil)
 	if err != nil {
 		return err
 	}
-	if ok == false {
-		return nil
+	err = dbo.DB.PutInBatch(records)
+	if err != nil {
+		return err
 	}
-	if ar == nil {
+	if !anchorsBitcoin(records) {
 		return nil
 	}
 	dbi, err := AnchorRecordToDirBlockInfo(ar)
@@ -67,17 +299,26 @@ func (dbo *Overlay) SaveAnchorInfoFromEntry(entry interfaces.IEBEntry) error {
 }
 
 func (dbo *Overlay) SaveAnchorInfoFromEntryMultiBatch(entry interfaces.IEBEntry) error {
-	if entry.DatabasePrimaryIndex().String() == "24674e6bc3094eb773297de955ee095a05830e431da13a37382dcdc89d73c7d7" {
-		return nil
-	}
-	ar, ok, err := anchor.UnmarshalAndValidateAnchorEntryAnyVersion(entry, AnchorSigPublicKeys)
+	ar, err := validAnchorRecord(entry)
 	if err != nil {
 		return err
 	}
-	if ok == false {
+	if ar == nil {
 		return nil
 	}
-	if ar == nil {
+	// The block may already be indexed by a record the multi batch holds
+	pending := map[string]bool{}
+	for _, r := range dbo.MultiBatch {
+		if bytes.Equal(r.Bucket, ANCHOR_RECORD) {
+			pending[string(r.Key)] = true
+		}
+	}
+	records, err := dbo.newAnchorIndexRecords(entry, ar, pending)
+	if err != nil {
+		return err
+	}
+	dbo.PutInMultiBatch(records)
+	if !anchorsBitcoin(records) {
 		return nil
 	}
 	dbi, err := AnchorRecordToDirBlockInfo(ar)
@@ -119,6 +360,9 @@ func (dbo *Overlay) SaveAnchorInfoAsDirBlockInfo(ars []*anchor.AnchorRecord) err
 	sort.Sort(ByAnchorDBHeightAscending(ars))
 
 	for _, v := range ars {
+		if v.Bitcoin == nil {
+			continue
+		}
 		dbi, err := AnchorRecordToDirBlockInfo(v)
 		if err != nil {
 			return err
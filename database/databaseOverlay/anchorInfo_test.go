@@ -8,6 +8,9 @@ import (
 	"testing"
 
 	"github.com/FactomProject/factomd/anchor"
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/database/databaseOverlay"
 	"github.com/FactomProject/factomd/testHelper"
 )
 
@@ -20,6 +23,156 @@ func TestRebuildDirBlockInfo(t *testing.T) {
 	}
 }
 
+func TestAnchorIndex(t *testing.T) {
+	dbo := testHelper.CreateAndPopulateTestDatabaseOverlay()
+
+	check := func() {
+		// Block h is anchored in block h+1, so the last block is not anchored
+		for h := uint32(0); h < uint32(testHelper.BlockCount); h++ {
+			entries, err := dbo.FetchAnchorEntries(h)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := 1
+			if h == uint32(testHelper.BlockCount-1) {
+				expected = 0
+			}
+			if len(entries) != expected {
+				t.Errorf("Block %d has %d anchor entries, expected %d", h, len(entries), expected)
+			}
+			for _, entry := range entries {
+				ar, _, _ := anchor.UnmarshalAndValidateAnchorEntryAnyVersion(entry, AnchorSigPublicKeys)
+				if ar == nil || ar.DBHeight != h {
+					t.Errorf("Block %d has the anchor entry %v", h, ar)
+				}
+			}
+		}
+	}
+	check()
+	err := dbo.RebuildAnchorIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	check()
+
+	// An Ethereum anchor is indexed next to the Bitcoin one
+	keyMR, err := dbo.FetchDBKeyMRByHeight(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ar := new(anchor.AnchorRecord)
+	ar.AnchorRecordVer = 1
+	ar.DBHeight = 3
+	ar.KeyMR = keyMR.String()
+	ar.RecordHeight = 5
+	ar.Ethereum = new(anchor.EthereumStruct)
+	ar.Ethereum.TXID = "0x50ea0effc383542811a58704a6d6842ed6d76439a2d942d941896ad097c06a78"
+	ar.Ethereum.BlockHeight = 293003
+	entry := insertAnchorEntry(t, dbo, ar)
+	entries, err := dbo.FetchAnchorEntries(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || !entries[1].GetHash().IsSameAs(entry.GetHash()) {
+		t.Errorf("Block 3 has %d anchor entries, expected the Bitcoin and the Ethereum anchor", len(entries))
+	}
+
+	// A second Bitcoin anchor does not replace the first one
+	ar = new(anchor.AnchorRecord)
+	ar.AnchorRecordVer = 1
+	ar.DBHeight = 3
+	ar.KeyMR = keyMR.String()
+	ar.RecordHeight = 6
+	ar.Bitcoin = new(anchor.BitcoinStruct)
+	ar.Bitcoin.TXID = "e0c67e64cfdbf025b41eb235cc07b2e63fb3c62c8b877f319cac8ec3b5483223"
+	ar.Bitcoin.BlockHash = "00000000000000000fdc6526a60522d44731c4d30b36421c10bb21fbe97eb468"
+	ar.Bitcoin.BlockHeight = 372584
+	second := insertAnchorEntry(t, dbo, ar)
+	entries, err = dbo.FetchAnchorEntries(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].GetHash().IsSameAs(second.GetHash()) {
+		t.Error("The Bitcoin anchor of block 3 was replaced")
+	}
+
+	// A record naming another directory block is not indexed
+	ar = new(anchor.AnchorRecord)
+	ar.AnchorRecordVer = 1
+	ar.DBHeight = uint32(testHelper.BlockCount - 1)
+	ar.KeyMR = keyMR.String()
+	ar.RecordHeight = 6
+	ar.Ethereum = new(anchor.EthereumStruct)
+	ar.Ethereum.TXID = "0x50ea0effc383542811a58704a6d6842ed6d76439a2d942d941896ad097c06a78"
+	ar.Ethereum.BlockHeight = 293004
+	insertAnchorEntry(t, dbo, ar)
+	entries, err = dbo.FetchAnchorEntries(uint32(testHelper.BlockCount - 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Error("An anchor of another directory block was indexed")
+	}
+
+	// The rebuilt index only has the anchors of the entry blocks, and keeps the first
+	// Bitcoin anchor of block 3
+	err = dbo.SaveKeyValueStore(&primitives.ByteSlice{Bytes: []byte("other keys")}, AnchorIndexKeysKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dbo.RebuildAnchorIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err = dbo.FetchAnchorEntries(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].GetHash().IsSameAs(second.GetHash()) {
+		t.Error("The rebuilt index changed the Bitcoin anchor of block 3")
+	}
+}
+
+// insertAnchorEntry saves an anchor chain entry holding ar, signed by the test key
+func insertAnchorEntry(t *testing.T, dbo *Overlay, ar *anchor.AnchorRecord) *entryBlock.Entry {
+	data, sig, err := ar.MarshalAndSignV2(testHelper.NewPrimitivesPrivateKey(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := entryBlock.NewEntry()
+	entry.ChainID = testHelper.GetAnchorChainID()
+	entry.Content = primitives.ByteSlice{Bytes: data}
+	entry.ExtIDs = []primitives.ByteSlice{primitives.ByteSlice{Bytes: sig}}
+	err = dbo.InsertEntry(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entry
+}
+
+func TestSetAnchorSigningKeys(t *testing.T) {
+	keys := AnchorSigKeys
+	pubKeys := AnchorSigPublicKeys
+	defer func() {
+		AnchorSigKeys = keys
+		AnchorSigPublicKeys = pubKeys
+	}()
+
+	if err := SetAnchorSigningKeys([]string{"0426a8"}); err == nil {
+		t.Error("Set a short anchor signing key")
+	}
+	if err := SetAnchorSigningKeys(nil); err == nil {
+		t.Error("Set no anchor signing key")
+	}
+	err := SetAnchorSigningKeys([]string{" " + testHelper.NewPrimitivesPrivateKey(1).Pub.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(AnchorSigPublicKeys) != 1 || AnchorSigPublicKeys[0].String() != testHelper.NewPrimitivesPrivateKey(1).Pub.String() {
+		t.Errorf("Anchor signing keys are %v", AnchorSigPublicKeys)
+	}
+}
+
 func CreateAnchors() []*anchor.AnchorRecord {
 	answer := []*anchor.AnchorRecord{}
 
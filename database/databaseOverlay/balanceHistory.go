@@ -0,0 +1,310 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The balance history keeps one bucket per address, FACTOID_BALANCE_HISTORY or
+// ENTRYCREDIT_BALANCE_HISTORY + address.  Keys are heights (4 bytes, big endian), and
+// there is a record for every height the balance of the address changed at, with the
+// change and the balance after it.
+//
+// BALANCE_HISTORY_HEAD holds the last record of every address, keyed by the name of
+// its bucket, so saving a block does not read the whole history of the addresses it
+// touches.  Blocks have to be saved in order; saving the last block again is harmless,
+// and older blocks are skipped.
+//
+// The deltas are the same as the ones applied to the balances in FactoidState:
+// factoid balances change with the inputs and outputs of factoid transactions, entry
+// credit balances with the commits and balance increases of entry credit blocks.
+
+// BalanceHistoryHeightKey holds the next height the balance history has to process
+var BalanceHistoryHeightKey = []byte("BalanceHistoryHeight")
+
+type BalanceChange struct {
+	DBHeight uint32
+	Change   int64
+	Balance  int64
+}
+
+func (e *BalanceChange) New() interfaces.BinaryMarshallableAndCopyable {
+	return new(BalanceChange)
+}
+
+func (e *BalanceChange) MarshalBinary() ([]byte, error) {
+	buf := primitives.NewBuffer(nil)
+	err := buf.PushUInt32(e.DBHeight)
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PushInt64(e.Change)
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PushInt64(e.Balance)
+	if err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (e *BalanceChange) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	buf := primitives.NewBuffer(data)
+	var err error
+	e.DBHeight, err = buf.PopUInt32()
+	if err != nil {
+		return nil, err
+	}
+	e.Change, err = buf.PopInt64()
+	if err != nil {
+		return nil, err
+	}
+	e.Balance, err = buf.PopInt64()
+	if err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (e *BalanceChange) UnmarshalBinary(data []byte) error {
+	_, err := e.UnmarshalBinaryData(data)
+	return err
+}
+
+func balanceHistoryBucket(ec bool, address []byte) []byte {
+	prefix := FACTOID_BALANCE_HISTORY
+	if ec {
+		prefix = ENTRYCREDIT_BALANCE_HISTORY
+	}
+	bucket := make([]byte, 0, len(prefix)+len(address))
+	bucket = append(bucket, prefix...)
+	return append(bucket, address...)
+}
+
+// balanceDeltas sums the changes of the balances of one block, by address
+type balanceDeltas struct {
+	ec        bool
+	addresses [][]byte
+	deltas    map[string]int64
+}
+
+func newBalanceDeltas(ec bool) *balanceDeltas {
+	d := new(balanceDeltas)
+	d.ec = ec
+	d.deltas = map[string]int64{}
+	return d
+}
+
+func (d *balanceDeltas) add(address []byte, amount int64) {
+	if _, ok := d.deltas[string(address)]; !ok {
+		d.addresses = append(d.addresses, address)
+	}
+	d.deltas[string(address)] += amount
+}
+
+func fblockBalanceDeltas(block interfaces.IFBlock) *balanceDeltas {
+	d := newBalanceDeltas(false)
+	for _, tx := range block.GetTransactions() {
+		for _, in := range tx.GetInputs() {
+			d.add(in.GetAddress().Bytes(), -int64(in.GetAmount()))
+		}
+		for _, out := range tx.GetOutputs() {
+			d.add(out.GetAddress().Bytes(), int64(out.GetAmount()))
+		}
+	}
+	return d
+}
+
+func ecblockBalanceDeltas(block interfaces.IEntryCreditBlock) *balanceDeltas {
+	d := newBalanceDeltas(true)
+	for _, entry := range block.GetBody().GetEntries() {
+		switch entry.ECID() {
+		case constants.ECIDChainCommit:
+			cc := entry.(*entryCreditBlock.CommitChain)
+			d.add(cc.ECPubKey[:], -int64(cc.Credits))
+		case constants.ECIDEntryCommit:
+			ce := entry.(*entryCreditBlock.CommitEntry)
+			d.add(ce.ECPubKey[:], -int64(ce.Credits))
+		case constants.ECIDBalanceIncrease:
+			ib := entry.(*entryCreditBlock.IncreaseBalance)
+			d.add(ib.ECPubKey[:], int64(ib.NumEC))
+		}
+	}
+	return d
+}
+
+// balanceHistoryRecords returns the records of the balance changes of a block
+func (db *Overlay) balanceHistoryRecords(d *balanceDeltas, height uint32) ([]interfaces.Record, error) {
+	records := []interfaces.Record{}
+	for _, address := range d.addresses {
+		change := d.deltas[string(address)]
+		if change == 0 {
+			continue
+		}
+		bucket := balanceHistoryBucket(d.ec, address)
+
+		var balance int64
+		head, err := db.DB.Get(BALANCE_HISTORY_HEAD, bucket, new(BalanceChange))
+		if err != nil {
+			return nil, err
+		}
+		if head != nil {
+			last := head.(*BalanceChange)
+			if last.DBHeight > height {
+				continue
+			}
+			balance = last.Balance
+			if last.DBHeight == height {
+				balance -= last.Change
+			}
+		}
+
+		bc := new(BalanceChange)
+		bc.DBHeight = height
+		bc.Change = change
+		bc.Balance = balance + change
+		key := make([]byte, 4)
+		binary.BigEndian.PutUint32(key, height)
+		records = append(records, interfaces.Record{bucket, key, bc})
+		records = append(records, interfaces.Record{BALANCE_HISTORY_HEAD, bucket, bc})
+	}
+	return records, nil
+}
+
+func balanceHistoryHeightRecord(next uint32) interfaces.Record {
+	buf := primitives.NewBuffer(nil)
+	buf.PushUInt32(next)
+	bs := new(primitives.ByteSlice)
+	bs.Bytes = buf.DeepCopyBytes()
+	return interfaces.Record{KEY_VALUE_STORE, BalanceHistoryHeightKey, bs}
+}
+
+func (db *Overlay) SetBalanceHistory(enabled bool) {
+	db.BalanceHistory = enabled
+}
+
+func (db *Overlay) IsBalanceHistoryEnabled() bool {
+	return db.BalanceHistory
+}
+
+func (db *Overlay) SaveBalanceHistoryFromFBlockMultiBatch(block interfaces.IFBlock) error {
+	if !db.BalanceHistory || block == nil {
+		return nil
+	}
+	records, err := db.balanceHistoryRecords(fblockBalanceDeltas(block), block.GetDatabaseHeight())
+	if err != nil {
+		return err
+	}
+	db.PutInMultiBatch(records)
+	return nil
+}
+
+func (db *Overlay) SaveBalanceHistoryFromECBlockMultiBatch(block interfaces.IEntryCreditBlock) error {
+	if !db.BalanceHistory || block == nil {
+		return nil
+	}
+	height := block.GetHeader().GetDBHeight()
+	records, err := db.balanceHistoryRecords(ecblockBalanceDeltas(block), height)
+	if err != nil {
+		return err
+	}
+	db.PutInMultiBatch(records)
+	// The EC block is saved with the factoid block of the same height, so this marks
+	// the whole height as done
+	db.PutInMultiBatch([]interfaces.Record{balanceHistoryHeightRecord(height + 1)})
+	return nil
+}
+
+// FetchBalanceHistoryHeight returns the next height the balance history has to process
+func (db *Overlay) FetchBalanceHistoryHeight() (uint32, error) {
+	bs := new(primitives.ByteSlice)
+	loaded, err := db.FetchKeyValueStore(BalanceHistoryHeightKey, bs)
+	if err != nil {
+		return 0, err
+	}
+	if loaded == nil {
+		return 0, nil
+	}
+	buf := primitives.NewBuffer(bs.Bytes)
+	return buf.PopUInt32()
+}
+
+// BackfillBalanceHistory records the balance changes of the blocks saved while the
+// balance history was disabled, from the last processed height up to the directory
+// block head.  It saves its progress after every height, so it can be interrupted and
+// resumed.  It must not run while blocks are being saved.
+func (db *Overlay) BackfillBalanceHistory() error {
+	next, err := db.FetchBalanceHistoryHeight()
+	if err != nil {
+		return err
+	}
+	head, err := db.FetchDBlockHead()
+	if err != nil {
+		return err
+	}
+	if head == nil {
+		return nil
+	}
+
+	for height := next; height <= head.GetDatabaseHeight(); height++ {
+		records := []interfaces.Record{}
+
+		fblock, err := db.FetchFBlockByHeight(height)
+		if err != nil {
+			return err
+		}
+		if fblock != nil {
+			r, err := db.balanceHistoryRecords(fblockBalanceDeltas(fblock), height)
+			if err != nil {
+				return err
+			}
+			records = append(records, r...)
+		}
+		ecblock, err := db.FetchECBlockByHeight(height)
+		if err != nil {
+			return err
+		}
+		if ecblock != nil {
+			r, err := db.balanceHistoryRecords(ecblockBalanceDeltas(ecblock), height)
+			if err != nil {
+				return err
+			}
+			records = append(records, r...)
+		}
+		if fblock == nil && ecblock == nil {
+			return fmt.Errorf("Missing factoid and entry credit blocks at height %d", height)
+		}
+
+		err = db.DB.PutInBatch(append(records, balanceHistoryHeightRecord(height+1)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FetchBalanceAtHeight returns the balance of a factoid or entry credit address once
+// the blocks of the given height were applied
+func (db *Overlay) FetchBalanceAtHeight(ec bool, address interfaces.IHash, height uint32) (int64, error) {
+	// The last change at or before the height
+	key := make([]byte, 4)
+	binary.BigEndian.PutUint32(key, height)
+	list, _, err := db.GetRange(balanceHistoryBucket(ec, address.Bytes()), key, true, 1, new(BalanceChange))
+	if err != nil {
+		return 0, err
+	}
+	if len(list) == 0 {
+		return 0, nil
+	}
+	return list[0].(*BalanceChange).Balance, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	. "github.com/FactomProject/factomd/testHelper"
+)
+
+func TestBalanceChangeMarshal(t *testing.T) {
+	bc := new(databaseOverlay.BalanceChange)
+	bc.DBHeight = 1234
+	bc.Change = -5678
+	bc.Balance = 91011
+
+	b, err := bc.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bc2 := new(databaseOverlay.BalanceChange)
+	err = bc2.UnmarshalBinary(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *bc2 != *bc {
+		t.Errorf("Got %v, expected %v", bc2, bc)
+	}
+}
+
+// expectedBalances sums the balance changes of the test blocks, giving the balances of
+// every address after every height
+func expectedBalances(blocks []*BlockSet) (fct []map[[32]byte]int64, ec []map[[32]byte]int64) {
+	fctBal := map[[32]byte]int64{}
+	ecBal := map[[32]byte]int64{}
+	for _, block := range blocks {
+		for _, tx := range block.FBlock.GetTransactions() {
+			for _, in := range tx.GetInputs() {
+				fctBal[in.GetAddress().Fixed()] -= int64(in.GetAmount())
+			}
+			for _, out := range tx.GetOutputs() {
+				fctBal[out.GetAddress().Fixed()] += int64(out.GetAmount())
+			}
+		}
+		for _, entry := range block.ECBlock.GetEntries() {
+			switch entry.ECID() {
+			case constants.ECIDChainCommit:
+				cc := entry.(*entryCreditBlock.CommitChain)
+				ecBal[*cc.ECPubKey] -= int64(cc.Credits)
+			case constants.ECIDEntryCommit:
+				ce := entry.(*entryCreditBlock.CommitEntry)
+				ecBal[*ce.ECPubKey] -= int64(ce.Credits)
+			case constants.ECIDBalanceIncrease:
+				ib := entry.(*entryCreditBlock.IncreaseBalance)
+				ecBal[*ib.ECPubKey] += int64(ib.NumEC)
+			}
+		}
+
+		f := map[[32]byte]int64{}
+		for k, v := range fctBal {
+			f[k] = v
+		}
+		e := map[[32]byte]int64{}
+		for k, v := range ecBal {
+			e[k] = v
+		}
+		fct = append(fct, f)
+		ec = append(ec, e)
+	}
+	return
+}
+
+func TestBalanceHistory(t *testing.T) {
+	blocks := CreateFullTestBlockSet()
+	fct, ec := expectedBalances(blocks)
+	last := len(blocks) - 1
+	if len(fct[last]) == 0 || len(ec[last]) == 0 {
+		t.Fatal("The test blocks do not change any balance")
+	}
+
+	live := CreateEmptyTestDatabaseOverlay()
+	live.SetBalanceHistory(true)
+	PopulateTestDatabaseOverlay(live)
+
+	next, err := live.FetchBalanceHistoryHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != uint32(len(blocks)) {
+		t.Errorf("Balance history height is %d, expected %d", next, len(blocks))
+	}
+
+	backfilled := CreateAndPopulateTestDatabaseOverlay()
+	backfilled.SetBalanceHistory(true)
+	err = backfilled.BackfillBalanceHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Saving the last blocks again does not count them twice
+	live.StartMultiBatch()
+	err = live.ProcessECBlockMultiBatch(blocks[last].ECBlock, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = live.ProcessFBlockMultiBatch(blocks[last].FBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = live.ExecuteMultiBatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dbo := range []interfaces.DBOverlay{live, backfilled} {
+		for height := range blocks {
+			for _, isEC := range []bool{false, true} {
+				all, expected := fct[last], fct[height]
+				if isEC {
+					all, expected = ec[last], ec[height]
+				}
+				// Addresses first seen after the height have no balance yet
+				for k := range all {
+					balance, err := dbo.FetchBalanceAtHeight(isEC, primitives.NewHash(k[:]), uint32(height))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if balance != expected[k] {
+						t.Errorf("Balance of %x at height %d is %d, expected %d", k, height, balance, expected[k])
+					}
+				}
+			}
+		}
+	}
+
+	balance, err := live.FetchBalanceAtHeight(false, primitives.NewZeroHash(), uint32(last))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance != 0 {
+		t.Errorf("Unknown address has a balance of %d", balance)
+	}
+}
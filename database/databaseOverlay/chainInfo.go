@@ -0,0 +1,381 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The CHAIN_INFO bucket holds the statistics of every chain, keyed by chain ID.  They
+// are updated as entry blocks and entries are saved.  An entry block or an entry is
+// only counted the first time it is saved, so saving it again, or saving the entries
+// of a chain out of order while syncing, is harmless.  An entry repeated in later
+// entry blocks counts in the entry count of each, but its payload counts once.
+//
+// The payload of an entry is its external IDs and content.
+
+// ChainInfoHeightKey holds the next height the chain info records have to count
+var ChainInfoHeightKey = []byte("ChainInfoHeight")
+
+type ChainInfo struct {
+	ChainID        interfaces.IHash
+	CreationHeight uint32
+	// FirstEntryHash is the zero hash until the first entry block of the chain is saved
+	FirstEntryHash interfaces.IHash
+	EBlockCount    uint32
+	EntryCount     uint64
+	PayloadBytes   uint64
+}
+
+var _ interfaces.IChainInfo = (*ChainInfo)(nil)
+
+func NewChainInfo(chainID interfaces.IHash) *ChainInfo {
+	ci := new(ChainInfo)
+	ci.ChainID = chainID
+	ci.FirstEntryHash = primitives.NewZeroHash()
+	return ci
+}
+
+func (e *ChainInfo) GetChainID() interfaces.IHash {
+	return e.ChainID
+}
+
+func (e *ChainInfo) GetCreationHeight() uint32 {
+	return e.CreationHeight
+}
+
+func (e *ChainInfo) GetFirstEntryHash() interfaces.IHash {
+	return e.FirstEntryHash
+}
+
+func (e *ChainInfo) GetEBlockCount() uint32 {
+	return e.EBlockCount
+}
+
+func (e *ChainInfo) GetEntryCount() uint64 {
+	return e.EntryCount
+}
+
+func (e *ChainInfo) GetPayloadBytes() uint64 {
+	return e.PayloadBytes
+}
+
+func (e *ChainInfo) New() interfaces.BinaryMarshallableAndCopyable {
+	return new(ChainInfo)
+}
+
+func (e *ChainInfo) MarshalBinary() ([]byte, error) {
+	buf := primitives.NewBuffer(nil)
+	if e.ChainID == nil {
+		e.ChainID = primitives.NewZeroHash()
+	}
+	if e.FirstEntryHash == nil {
+		e.FirstEntryHash = primitives.NewZeroHash()
+	}
+	err := buf.PushIHash(e.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PushUInt32(e.CreationHeight)
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PushIHash(e.FirstEntryHash)
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PushUInt32(e.EBlockCount)
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PushUInt64(e.EntryCount)
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PushUInt64(e.PayloadBytes)
+	if err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (e *ChainInfo) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	buf := primitives.NewBuffer(data)
+	var err error
+	e.ChainID, err = buf.PopIHash()
+	if err != nil {
+		return nil, err
+	}
+	e.CreationHeight, err = buf.PopUInt32()
+	if err != nil {
+		return nil, err
+	}
+	e.FirstEntryHash, err = buf.PopIHash()
+	if err != nil {
+		return nil, err
+	}
+	e.EBlockCount, err = buf.PopUInt32()
+	if err != nil {
+		return nil, err
+	}
+	e.EntryCount, err = buf.PopUInt64()
+	if err != nil {
+		return nil, err
+	}
+	e.PayloadBytes, err = buf.PopUInt64()
+	if err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (e *ChainInfo) UnmarshalBinary(data []byte) error {
+	_, err := e.UnmarshalBinaryData(data)
+	return err
+}
+
+func (e *ChainInfo) addEBlock(eblock interfaces.IEntryBlock) {
+	hashes := []interfaces.IHash{}
+	for _, hash := range eblock.GetEntryHashes() {
+		if !hash.IsMinuteMarker() {
+			hashes = append(hashes, hash)
+		}
+	}
+	if eblock.GetHeader().GetEBSequence() == 0 && len(hashes) > 0 {
+		e.CreationHeight = eblock.GetDatabaseHeight()
+		e.FirstEntryHash = hashes[0]
+	}
+	e.EBlockCount++
+	e.EntryCount += uint64(len(hashes))
+}
+
+func (e *ChainInfo) addEntry(entry interfaces.IEBEntry) {
+	e.PayloadBytes += uint64(len(entry.GetContent()))
+	for _, extID := range entry.ExternalIDs() {
+		e.PayloadBytes += uint64(len(extID))
+	}
+}
+
+func (e *ChainInfo) record() interfaces.Record {
+	return interfaces.Record{CHAIN_INFO, e.ChainID.Bytes(), e}
+}
+
+func chainInfoHeightRecord(next uint32) interfaces.Record {
+	buf := primitives.NewBuffer(nil)
+	buf.PushUInt32(next)
+	bs := new(primitives.ByteSlice)
+	bs.Bytes = buf.DeepCopyBytes()
+	return interfaces.Record{KEY_VALUE_STORE, ChainInfoHeightKey, bs}
+}
+
+func (db *Overlay) SetChainInfo(enabled bool) {
+	db.ChainInfo = enabled
+}
+
+func (db *Overlay) IsChainInfoEnabled() bool {
+	return db.ChainInfo
+}
+
+func (db *Overlay) fetchChainInfo(chainID interfaces.IHash) (*ChainInfo, error) {
+	ci, err := db.DB.Get(CHAIN_INFO, chainID.Bytes(), new(ChainInfo))
+	if err != nil {
+		return nil, err
+	}
+	if ci == nil {
+		return NewChainInfo(chainID), nil
+	}
+	return ci.(*ChainInfo), nil
+}
+
+// pendingChainInfo returns the chain info of the current multi batch, which may not
+// be saved yet
+func (db *Overlay) pendingChainInfo(chainID interfaces.IHash) (*ChainInfo, error) {
+	if ci, ok := db.chainInfos[chainID.Fixed()]; ok {
+		return ci, nil
+	}
+	ci, err := db.fetchChainInfo(chainID)
+	if err != nil {
+		return nil, err
+	}
+	if db.chainInfos == nil {
+		db.chainInfos = map[[32]byte]*ChainInfo{}
+	}
+	db.chainInfos[chainID.Fixed()] = ci
+	return ci, nil
+}
+
+// isNewInMultiBatch tells if a block or an entry is saved for the first time by the
+// current multi batch
+func (db *Overlay) isNewInMultiBatch(bucket []byte, hash interfaces.IHash) (bool, error) {
+	if db.chainInfoSeen[hash.Fixed()] {
+		return false, nil
+	}
+	if db.chainInfoSeen == nil {
+		db.chainInfoSeen = map[[32]byte]bool{}
+	}
+	db.chainInfoSeen[hash.Fixed()] = true
+	exists, err := db.DB.DoesKeyExist(bucket, hash.Bytes())
+	return !exists, err
+}
+
+// SaveChainInfoFromEBlockMultiBatch counts an entry block in the statistics of its
+// chain.  It must be called before the entry block is put in the multi batch.  The
+// chain info is only changed in multi batches, so the changes of two batches can not
+// overwrite each other.
+func (db *Overlay) SaveChainInfoFromEBlockMultiBatch(block interfaces.DatabaseBlockWithEntries) error {
+	eblock, ok := block.(interfaces.IEntryBlock)
+	if !db.ChainInfo || !ok {
+		return nil
+	}
+	isNew, err := db.isNewInMultiBatch(ENTRYBLOCK, eblock.DatabasePrimaryIndex())
+	if err != nil || !isNew {
+		return err
+	}
+	ci, err := db.pendingChainInfo(eblock.GetChainID())
+	if err != nil {
+		return err
+	}
+	ci.addEBlock(eblock)
+	db.PutInMultiBatch([]interfaces.Record{ci.record()})
+	return nil
+}
+
+func (db *Overlay) SaveChainInfoFromEntryMultiBatch(entry interfaces.IEBEntry) error {
+	if !db.ChainInfo || entry == nil {
+		return nil
+	}
+	isNew, err := db.isNewInMultiBatch(ENTRY, entry.GetHash())
+	if err != nil || !isNew {
+		return err
+	}
+	ci, err := db.pendingChainInfo(entry.GetChainID())
+	if err != nil {
+		return err
+	}
+	ci.addEntry(entry)
+	db.PutInMultiBatch([]interfaces.Record{ci.record()})
+	return nil
+}
+
+// SaveChainInfoHeightMultiBatch marks the height of a directory block as counted
+func (db *Overlay) SaveChainInfoHeightMultiBatch(dblock interfaces.DatabaseBlockWithEntries) {
+	if !db.ChainInfo || dblock == nil {
+		return
+	}
+	db.PutInMultiBatch([]interfaces.Record{chainInfoHeightRecord(dblock.GetDatabaseHeight() + 1)})
+}
+
+// FetchChainInfoHeight returns the next height the chain info records have to count
+func (db *Overlay) FetchChainInfoHeight() (uint32, error) {
+	bs := new(primitives.ByteSlice)
+	loaded, err := db.FetchKeyValueStore(ChainInfoHeightKey, bs)
+	if err != nil {
+		return 0, err
+	}
+	if loaded == nil {
+		return 0, nil
+	}
+	buf := primitives.NewBuffer(bs.Bytes)
+	return buf.PopUInt32()
+}
+
+// BackfillChainInfo counts the entry blocks and entries saved while the chain info
+// records were not kept, from the last counted height up to the directory block head.
+// It saves its progress after every height, so it can be interrupted and resumed.  It
+// must not run while blocks are being saved.
+func (db *Overlay) BackfillChainInfo() error {
+	next, err := db.FetchChainInfoHeight()
+	if err != nil {
+		return err
+	}
+	head, err := db.FetchDBlockHead()
+	if err != nil {
+		return err
+	}
+	if head == nil {
+		return nil
+	}
+
+	for height := next; height <= head.GetDatabaseHeight(); height++ {
+		err = db.backfillChainInfoHeight(height)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillChainInfoHeight counts the entry blocks of one height, and the entries saved
+// so far.  Like a multi batch, it holds the BatchSemaphore, so entries saved at the same
+// time are not lost.
+func (db *Overlay) backfillChainInfoHeight(height uint32) error {
+	db.BatchSemaphore.Lock()
+	defer db.BatchSemaphore.Unlock()
+
+	dblock, err := db.FetchDBlockByHeight(height)
+	if err != nil {
+		return err
+	}
+	if dblock == nil {
+		return fmt.Errorf("Missing directory block at height %d", height)
+	}
+
+	records := []interfaces.Record{}
+	for _, dbEntry := range dblock.GetEBlockDBEntries() {
+		eblock, err := db.FetchEBlock(dbEntry.GetKeyMR())
+		if err != nil {
+			return err
+		}
+		if eblock == nil {
+			return fmt.Errorf("Missing entry block %x at height %d", dbEntry.GetKeyMR().Bytes(), height)
+		}
+		ci, err := db.fetchChainInfo(eblock.GetChainID())
+		if err != nil {
+			return err
+		}
+		ci.addEBlock(eblock)
+		counted := map[[32]byte]bool{}
+		for _, hash := range eblock.GetEntryHashes() {
+			if hash.IsMinuteMarker() || counted[hash.Fixed()] {
+				continue
+			}
+			counted[hash.Fixed()] = true
+			// A repeated entry was counted with the first entry block including it
+			first, err := db.FetchIncludedIn(hash)
+			if err != nil {
+				return err
+			}
+			if first != nil && !first.IsSameAs(eblock.DatabasePrimaryIndex()) {
+				continue
+			}
+			entry, err := db.FetchEntry(hash)
+			if err != nil {
+				return err
+			}
+			// Entries still being synced are counted when they are saved
+			if entry != nil {
+				ci.addEntry(entry)
+			}
+		}
+		records = append(records, ci.record())
+	}
+	return db.DB.PutInBatch(append(records, chainInfoHeightRecord(height+1)))
+}
+
+// FetchChainInfo returns the statistics of a chain, or nil if no entry block of the
+// chain was counted
+func (db *Overlay) FetchChainInfo(chainID interfaces.IHash) (interfaces.IChainInfo, error) {
+	ci, err := db.DB.Get(CHAIN_INFO, chainID.Bytes(), new(ChainInfo))
+	if err != nil {
+		return nil, err
+	}
+	if ci == nil || ci.(*ChainInfo).EBlockCount == 0 {
+		return nil, nil
+	}
+	return ci.(interfaces.IChainInfo), nil
+}
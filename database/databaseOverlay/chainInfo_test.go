@@ -0,0 +1,225 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/directoryBlock"
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	. "github.com/FactomProject/factomd/testHelper"
+)
+
+func TestChainInfoMarshal(t *testing.T) {
+	ci := databaseOverlay.NewChainInfo(GetChainID())
+	ci.CreationHeight = 12
+	ci.FirstEntryHash = primitives.Sha([]byte("entry"))
+	ci.EBlockCount = 34
+	ci.EntryCount = 56
+	ci.PayloadBytes = 78
+
+	b, err := ci.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ci2 := new(databaseOverlay.ChainInfo)
+	err = ci2.UnmarshalBinary(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ci2.ChainID.IsSameAs(ci.ChainID) || !ci2.FirstEntryHash.IsSameAs(ci.FirstEntryHash) ||
+		ci2.CreationHeight != ci.CreationHeight || ci2.EBlockCount != ci.EBlockCount ||
+		ci2.EntryCount != ci.EntryCount || ci2.PayloadBytes != ci.PayloadBytes {
+		t.Errorf("Got %v, expected %v", ci2, ci)
+	}
+}
+
+func TestChainInfo(t *testing.T) {
+	blocks := CreateFullTestBlockSet()
+
+	var payload uint64
+	for _, block := range blocks {
+		for _, entry := range block.Entries {
+			if !entry.GetChainID().IsSameAs(GetChainID()) {
+				continue
+			}
+			payload += uint64(len(entry.GetContent()))
+			for _, extID := range entry.ExternalIDs() {
+				payload += uint64(len(extID))
+			}
+		}
+	}
+
+	live := CreateEmptyTestDatabaseOverlay()
+	live.SetChainInfo(true)
+	PopulateTestDatabaseOverlay(live)
+
+	next, err := live.FetchChainInfoHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != uint32(len(blocks)) {
+		t.Errorf("Chain info height is %d, expected %d", next, len(blocks))
+	}
+
+	backfilled := CreateAndPopulateTestDatabaseOverlay()
+	info, err := backfilled.FetchChainInfo(GetChainID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info != nil {
+		t.Error("Chains were counted with the chain info disabled")
+	}
+	backfilled.SetChainInfo(true)
+	err = backfilled.BackfillChainInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Saving blocks and entries again does not count them twice
+	live.StartMultiBatch()
+	err = live.ProcessEBlockMultiBatch(blocks[1].EBlock, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = live.InsertEntryMultiBatch(blocks[1].Entries[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = live.ExecuteMultiBatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = live.InsertEntry(blocks[2].Entries[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := CreateFirstTestEntry()
+	for _, dbo := range []interfaces.DBOverlay{live, backfilled} {
+		info, err := dbo.FetchChainInfo(GetChainID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info == nil {
+			t.Fatal("Chain info not found")
+		}
+		if info.GetCreationHeight() != 0 || !info.GetFirstEntryHash().IsSameAs(first.GetHash()) {
+			t.Errorf("Wrong creation %d %v", info.GetCreationHeight(), info.GetFirstEntryHash())
+		}
+		if info.GetEBlockCount() != uint32(len(blocks)) || info.GetEntryCount() != uint64(len(blocks)) {
+			t.Errorf("Got %d entry blocks and %d entries, expected %d of each", info.GetEBlockCount(), info.GetEntryCount(), len(blocks))
+		}
+		if info.GetPayloadBytes() != payload {
+			t.Errorf("Got %d bytes of payload, expected %d", info.GetPayloadBytes(), payload)
+		}
+	}
+
+	info, err = live.FetchChainInfo(primitives.NewZeroHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info != nil {
+		t.Error("Found the info of a chain that does not exist")
+	}
+}
+
+func TestChainInfoRepeatedEntry(t *testing.T) {
+	// The first entry is written again in the second entry block, next to a new one
+	first := CreateFirstTestEntry()
+	second := CreateTestEntry(1)
+	eblock0, _ := CreateTestEntryBlock(nil)
+	eblock1 := entryBlock.NewEBlock()
+	keyMR, err := eblock0.KeyMR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	eblock1.Header.SetPrevKeyMR(keyMR)
+	eblock1.Header.SetChainID(GetChainID())
+	eblock1.Header.SetDBHeight(1)
+	eblock1.Header.SetEBSequence(1)
+	eblock1.AddEBEntry(first)
+	eblock1.AddEBEntry(second)
+
+	var dblock *directoryBlock.DirectoryBlock
+	dblocks := []*directoryBlock.DirectoryBlock{}
+	for _, eblock := range []*entryBlock.EBlock{eblock0, eblock1} {
+		dblock = CreateTestDirectoryBlock(dblock)
+		de := new(directoryBlock.DBEntry)
+		de.ChainID = eblock.GetChainID()
+		de.KeyMR = eblock.DatabasePrimaryIndex()
+		err = dblock.SetDBEntries([]interfaces.IDBEntry{de})
+		if err != nil {
+			t.Fatal(err)
+		}
+		dblocks = append(dblocks, dblock)
+	}
+
+	save := func(dbo *databaseOverlay.Overlay) {
+		for i, eblock := range []*entryBlock.EBlock{eblock0, eblock1} {
+			dbo.StartMultiBatch()
+			err := dbo.ProcessEBlockMultiBatch(eblock, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = dbo.InsertEntryMultiBatch(first)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if i == 1 {
+				err = dbo.InsertEntryMultiBatch(second)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			err = dbo.ProcessDBlockMultiBatch(dblocks[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = dbo.ExecuteMultiBatch()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	live := CreateEmptyTestDatabaseOverlay()
+	live.SetChainInfo(true)
+	save(live)
+
+	backfilled := CreateEmptyTestDatabaseOverlay()
+	save(backfilled)
+	backfilled.SetChainInfo(true)
+	err = backfilled.BackfillChainInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var payload uint64
+	for _, entry := range []*entryBlock.Entry{first, second} {
+		payload += uint64(len(entry.GetContent()))
+		for _, extID := range entry.ExternalIDs() {
+			payload += uint64(len(extID))
+		}
+	}
+	for _, dbo := range []*databaseOverlay.Overlay{live, backfilled} {
+		info, err := dbo.FetchChainInfo(GetChainID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info == nil {
+			t.Fatal("Chain info not found")
+		}
+		if info.GetEBlockCount() != 2 || info.GetEntryCount() != 3 {
+			t.Errorf("Got %d entry blocks and %d entries, expected 2 and 3", info.GetEBlockCount(), info.GetEntryCount())
+		}
+		if info.GetPayloadBytes() != payload {
+			t.Errorf("Got %d bytes of payload, expected %d", info.GetPayloadBytes(), payload)
+		}
+	}
+}
@@ -47,6 +47,8 @@ func (db *Overlay) ProcessDBlockMultiBatch(dblock interfaces.DatabaseBlockWithEn
 	if err != nil {
 		return err
 	}
+	db.SaveExtIDIndexHeightMultiBatch(dblock)
+	db.SaveChainInfoHeightMultiBatch(dblock)
 
 	return db.SaveIncludedInMultiFromBlockMultiBatch(dblock, true)
 }
@@ -11,43 +11,47 @@ import (
 )
 
 // ProcessEBlockBatche inserts the EBlock and update all it's ebentries in DB
+// The EBlock is saved in a multi batch of its own, so the chain info it changes is
+// saved with it, and no other batch changes the chain info in between.
 func (db *Overlay) ProcessEBlockBatch(eblock interfaces.DatabaseBlockWithEntries, checkForDuplicateEntries bool) error {
-	//Each chain has its own number bucket, otherwise we would have conflicts
-	numberBucket := append(ENTRYBLOCK_CHAIN_NUMBER, eblock.GetChainID().Bytes()...)
-	err := db.ProcessBlockBatch(ENTRYBLOCK, numberBucket, ENTRYBLOCK_SECONDARYINDEX, eblock)
-	if err != nil {
-		return err
-	}
-	return db.SaveIncludedInMultiFromBlock(eblock, checkForDuplicateEntries)
+	return db.inMultiBatch(func() error {
+		return db.ProcessEBlockMultiBatch(eblock, checkForDuplicateEntries)
+	})
 }
 
 func (db *Overlay) ProcessEBlockBatchWithoutHead(eblock interfaces.DatabaseBlockWithEntries, checkForDuplicateEntries bool) error {
-	//Each chain has its own number bucket, otherwise we would have conflicts
-	numberBucket := append(ENTRYBLOCK_CHAIN_NUMBER, eblock.GetChainID().Bytes()...)
-	err := db.ProcessBlockBatchWithoutHead(ENTRYBLOCK, numberBucket, ENTRYBLOCK_SECONDARYINDEX, eblock)
-	if err != nil {
-		return err
-	}
-	return db.SaveIncludedInMultiFromBlock(eblock, checkForDuplicateEntries)
+	return db.inMultiBatch(func() error {
+		return db.ProcessEBlockMultiBatchWithoutHead(eblock, checkForDuplicateEntries)
+	})
 }
 
 func (db *Overlay) ProcessEBlockMultiBatchWithoutHead(eblock interfaces.DatabaseBlockWithEntries, checkForDuplicateEntries bool) error {
+	err := db.SaveChainInfoFromEBlockMultiBatch(eblock)
+	if err != nil {
+		return err
+	}
 	//Each chain has its own number bucket, otherwise we would have conflicts
 	numberBucket := append(ENTRYBLOCK_CHAIN_NUMBER, eblock.GetChainID().Bytes()...)
-	err := db.ProcessBlockMultiBatchWithoutHead(ENTRYBLOCK, numberBucket, ENTRYBLOCK_SECONDARYINDEX, eblock)
+	err = db.ProcessBlockMultiBatchWithoutHead(ENTRYBLOCK, numberBucket, ENTRYBLOCK_SECONDARYINDEX, eblock)
 	if err != nil {
 		return err
 	}
+	db.noteExtIDIndexHeights(eblock)
 	return db.SaveIncludedInMultiFromBlockMultiBatch(eblock, checkForDuplicateEntries)
 }
 
 func (db *Overlay) ProcessEBlockMultiBatch(eblock interfaces.DatabaseBlockWithEntries, checkForDuplicateEntries bool) error {
+	err := db.SaveChainInfoFromEBlockMultiBatch(eblock)
+	if err != nil {
+		return err
+	}
 	//Each chain has its own number bucket, otherwise we would have conflicts
 	numberBucket := append(ENTRYBLOCK_CHAIN_NUMBER, eblock.GetChainID().Bytes()...)
-	err := db.ProcessBlockMultiBatch(ENTRYBLOCK, numberBucket, ENTRYBLOCK_SECONDARYINDEX, eblock)
+	err = db.ProcessBlockMultiBatch(ENTRYBLOCK, numberBucket, ENTRYBLOCK_SECONDARYINDEX, eblock)
 	if err != nil {
 		return err
 	}
+	db.noteExtIDIndexHeights(eblock)
 	return db.SaveIncludedInMultiFromBlockMultiBatch(eblock, checkForDuplicateEntries)
 }
 
@@ -91,6 +95,19 @@ func (db *Overlay) FetchEBKeyMRByHash(hash interfaces.IHash) (interfaces.IHash,
 	return db.FetchPrimaryIndexBySecondaryIndex(ENTRYBLOCK_SECONDARYINDEX, hash)
 }
 
+// FetchEBlockBySequence gets the entry block of a chain with the given sequence number
+func (db *Overlay) FetchEBlockBySequence(chainID interfaces.IHash, sequence uint32) (interfaces.IEntryBlock, error) {
+	bucket := append(ENTRYBLOCK_CHAIN_NUMBER, chainID.Bytes()...)
+	block, err := db.FetchBlockByHeight(bucket, ENTRYBLOCK, sequence, entryBlock.NewEBlock())
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, nil
+	}
+	return block.(interfaces.IEntryBlock), nil
+}
+
 // FetchAllEBlocksByChain gets all of the blocks by chain id
 func (db *Overlay) FetchAllEBlocksByChain(chainID interfaces.IHash) ([]interfaces.IEntryBlock, error) {
 	bucket := append(ENTRYBLOCK_CHAIN_NUMBER, chainID.Bytes()...)
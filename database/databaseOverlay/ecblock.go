@@ -49,6 +49,11 @@ func (db *Overlay) ProcessECBlockMultiBatch(block interfaces.IEntryCreditBlock,
 	if err != nil {
 		return err
 	}
+	db.SaveAddressIndexFromECBlockMultiBatch(block)
+	err = db.SaveBalanceHistoryFromECBlockMultiBatch(block)
+	if err != nil {
+		return err
+	}
 	return db.SavePaidForMultiFromBlockMultiBatch(block, checkForDuplicateEntries)
 }
 
@@ -0,0 +1,103 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/mapdb"
+	"github.com/FactomProject/factomd/database/securedb"
+	. "github.com/FactomProject/factomd/testHelper"
+)
+
+// The benchmarks run the hot paths of the overlay on a map database, plain and
+// encrypted, so the difference is the cost of the encryption.
+
+func benchmarkOverlays(b *testing.B, f func(b *testing.B, newOverlay func() *databaseOverlay.Overlay)) {
+	b.Run("Plain", func(b *testing.B) {
+		f(b, CreateEmptyTestDatabaseOverlay)
+	})
+	b.Run("Encrypted", func(b *testing.B) {
+		f(b, func() *databaseOverlay.Overlay {
+			dbase, err := securedb.NewEncryptedDBFromDB(new(mapdb.MapDB), "password")
+			if err != nil {
+				b.Fatal(err)
+			}
+			return databaseOverlay.NewOverlay(dbase)
+		})
+	})
+}
+
+func BenchmarkSaveBlocks(b *testing.B) {
+	benchmarkOverlays(b, func(b *testing.B, newOverlay func() *databaseOverlay.Overlay) {
+		dbos := make([]*databaseOverlay.Overlay, b.N)
+		for i := range dbos {
+			dbos[i] = newOverlay()
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			PopulateTestDatabaseOverlay(dbos[i])
+		}
+	})
+}
+
+func BenchmarkInsertEntry(b *testing.B) {
+	entries := CreateFullTestBlockSet()[BlockCount-1].Entries
+	benchmarkOverlays(b, func(b *testing.B, newOverlay func() *databaseOverlay.Overlay) {
+		dbo := newOverlay()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			err := dbo.InsertEntry(entries[i%len(entries)])
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkFetchEntry(b *testing.B) {
+	entries := CreateFullTestBlockSet()[BlockCount-1].Entries
+	benchmarkOverlays(b, func(b *testing.B, newOverlay func() *databaseOverlay.Overlay) {
+		dbo := newOverlay()
+		PopulateTestDatabaseOverlay(dbo)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			entry, err := dbo.FetchEntry(entries[i%len(entries)].GetHash())
+			if err != nil || entry == nil {
+				b.Fatal("Entry not found", err)
+			}
+		}
+	})
+}
+
+func BenchmarkFetchDBlockByHeight(b *testing.B) {
+	benchmarkOverlays(b, func(b *testing.B, newOverlay func() *databaseOverlay.Overlay) {
+		dbo := newOverlay()
+		PopulateTestDatabaseOverlay(dbo)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			dblock, err := dbo.FetchDBlockByHeight(uint32(i % BlockCount))
+			if err != nil || dblock == nil {
+				b.Fatal("Directory block not found", err)
+			}
+		}
+	})
+}
+
+func BenchmarkFetchEBlockHead(b *testing.B) {
+	chainID := GetChainID()
+	benchmarkOverlays(b, func(b *testing.B, newOverlay func() *databaseOverlay.Overlay) {
+		dbo := newOverlay()
+		PopulateTestDatabaseOverlay(dbo)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			eblock, err := dbo.FetchEBlockHead(chainID)
+			if err != nil || eblock == nil {
+				b.Fatal("Entry block not found", err)
+			}
+		}
+	})
+}
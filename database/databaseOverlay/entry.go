@@ -6,28 +6,15 @@ import (
 	"github.com/FactomProject/factomd/common/primitives"
 )
 
-// InsertEntry inserts an entry
+// InsertEntry inserts an entry, in a multi batch of its own so the chain info it
+// changes is saved with it
 func (db *Overlay) InsertEntry(entry interfaces.IEBEntry) error {
 	if entry == nil {
 		return nil
 	}
-
-	//Entries are saved in buckets represented by their chainID for easy batch loading
-	//They are also indexed in ENTRY bucket by their hash that points to their chainID
-	//So they can be loaded in two load operations without needing to know their chainID
-
-	batch := []interfaces.Record{}
-	batch = append(batch, interfaces.Record{entry.GetChainID().Bytes(), entry.DatabasePrimaryIndex().Bytes(), entry})
-	batch = append(batch, interfaces.Record{ENTRY, entry.DatabasePrimaryIndex().Bytes(), entry.GetChainIDHash()})
-
-	err := db.PutInBatch(batch)
-	if err != nil {
-		return err
-	}
-	if entry.GetChainID().String() == AnchorBlockID {
-		db.SaveAnchorInfoFromEntry(entry)
-	}
-	return nil
+	return db.inMultiBatch(func() error {
+		return db.InsertEntryMultiBatch(entry)
+	})
 }
 
 func (db *Overlay) InsertEntryMultiBatch(entry interfaces.IEBEntry) error {
@@ -39,11 +26,20 @@ func (db *Overlay) InsertEntryMultiBatch(entry interfaces.IEBEntry) error {
 	//They are also indexed in ENTRY bucket by their hash that points to their chainID
 	//So they can be loaded in two load operations without needing to know their chainID
 
+	err := db.SaveChainInfoFromEntryMultiBatch(entry)
+	if err != nil {
+		return err
+	}
+
 	batch := []interfaces.Record{}
 	batch = append(batch, interfaces.Record{entry.GetChainID().Bytes(), entry.DatabasePrimaryIndex().Bytes(), entry})
 	batch = append(batch, interfaces.Record{ENTRY, entry.DatabasePrimaryIndex().Bytes(), entry.GetChainIDHash()})
 
 	db.PutInMultiBatch(batch)
+	err = db.SaveExtIDIndexMultiBatch(entry)
+	if err != nil {
+		return err
+	}
 	if entry.GetChainID().String() == AnchorBlockID {
 		db.SaveAnchorInfoFromEntryMultiBatch(entry)
 	}
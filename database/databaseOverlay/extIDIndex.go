@@ -0,0 +1,343 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The external ID index keeps one bucket per chain and external ID value,
+// EXTID_INDEX + chain ID + sha256(external ID), so bucket names have the same length
+// however long the external ID is.  Keys are height (4 bytes, big endian) + entry
+// hash, so a bucket lists the entries of a chain with that external ID in block
+// order.  Every external ID of an entry is indexed, not only the first one.
+//
+// The EXTID_INDEX bucket itself lists the buckets of the index, so they can be
+// cleared when the index is rebuilt.
+//
+// Entries are indexed when they are inserted.  The height of an entry comes from
+// the entry block put in the same multi batch, or else from the entry block it is
+// included in in the database.
+
+// ExtIDIndexHeightKey holds the next height the external ID index has to process
+var ExtIDIndexHeightKey = []byte("ExtIDIndexHeight")
+
+type ExtIDIndexEntry struct {
+	EntryHash interfaces.IHash
+	DBHeight  uint32
+}
+
+var _ interfaces.IExtIDIndexEntry = (*ExtIDIndexEntry)(nil)
+
+func (e *ExtIDIndexEntry) GetEntryHash() interfaces.IHash {
+	return e.EntryHash
+}
+
+func (e *ExtIDIndexEntry) GetDBHeight() uint32 {
+	return e.DBHeight
+}
+
+func (e *ExtIDIndexEntry) New() interfaces.BinaryMarshallableAndCopyable {
+	return new(ExtIDIndexEntry)
+}
+
+func (e *ExtIDIndexEntry) MarshalBinary() ([]byte, error) {
+	buf := primitives.NewBuffer(nil)
+	if e.EntryHash == nil {
+		e.EntryHash = primitives.NewZeroHash()
+	}
+	err := buf.PushIHash(e.EntryHash)
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PushUInt32(e.DBHeight)
+	if err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (e *ExtIDIndexEntry) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	buf := primitives.NewBuffer(data)
+	var err error
+	e.EntryHash, err = buf.PopIHash()
+	if err != nil {
+		return nil, err
+	}
+	e.DBHeight, err = buf.PopUInt32()
+	if err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (e *ExtIDIndexEntry) UnmarshalBinary(data []byte) error {
+	_, err := e.UnmarshalBinaryData(data)
+	return err
+}
+
+func (e *ExtIDIndexEntry) key() []byte {
+	return ExtIDIndexKey(e.DBHeight, e.EntryHash)
+}
+
+// ExtIDIndexKey is the key of an entry in the buckets of the external ID index.  With
+// a nil entry hash, it is the key every entry of the height sorts after.
+func ExtIDIndexKey(height uint32, entryHash interfaces.IHash) []byte {
+	key := make([]byte, 4, 4+constants.HASH_LENGTH)
+	binary.BigEndian.PutUint32(key, height)
+	if entryHash == nil {
+		return key
+	}
+	return append(key, entryHash.Bytes()...)
+}
+
+// extIDIndexName is the part of the bucket name that follows EXTID_INDEX
+func extIDIndexName(chainID interfaces.IHash, extID []byte) []byte {
+	sum := sha256.Sum256(extID)
+	name := make([]byte, 0, 2*constants.HASH_LENGTH)
+	name = append(name, chainID.Bytes()...)
+	return append(name, sum[:]...)
+}
+
+func extIDIndexBucket(name []byte) []byte {
+	bucket := make([]byte, 0, len(EXTID_INDEX)+len(name))
+	bucket = append(bucket, EXTID_INDEX...)
+	return append(bucket, name...)
+}
+
+func extIDIndexRecords(entry interfaces.IEBEntry, height uint32) []interfaces.Record {
+	ie := new(ExtIDIndexEntry)
+	ie.EntryHash = entry.GetHash()
+	ie.DBHeight = height
+	key := ie.key()
+
+	records := []interfaces.Record{}
+	seen := map[string]bool{}
+	for _, extID := range entry.ExternalIDs() {
+		name := extIDIndexName(entry.GetChainID(), extID)
+		if seen[string(name)] {
+			continue
+		}
+		seen[string(name)] = true
+		records = append(records, interfaces.Record{extIDIndexBucket(name), key, ie})
+		records = append(records, interfaces.Record{EXTID_INDEX, name, entry.GetChainID()})
+	}
+	return records
+}
+
+func extIDIndexHeightRecord(next uint32) interfaces.Record {
+	buf := primitives.NewBuffer(nil)
+	buf.PushUInt32(next)
+	bs := new(primitives.ByteSlice)
+	bs.Bytes = buf.DeepCopyBytes()
+	return interfaces.Record{KEY_VALUE_STORE, ExtIDIndexHeightKey, bs}
+}
+
+func (db *Overlay) SetExtIDIndex(enabled bool) {
+	db.ExtIDIndex = enabled
+}
+
+func (db *Overlay) IsExtIDIndexEnabled() bool {
+	return db.ExtIDIndex
+}
+
+// noteExtIDIndexHeights remembers the height of the entries of an entry block put in
+// the current multi batch
+func (db *Overlay) noteExtIDIndexHeights(eblock interfaces.DatabaseBlockWithEntries) {
+	if !db.ExtIDIndex {
+		return
+	}
+	if db.extIDHeights == nil {
+		db.extIDHeights = map[[32]byte]uint32{}
+	}
+	for _, hash := range eblock.GetEntryHashes() {
+		if hash.IsMinuteMarker() {
+			continue
+		}
+		db.extIDHeights[hash.Fixed()] = eblock.GetDatabaseHeight()
+	}
+}
+
+// fetchEntryHeight returns the height of the entry block that includes an entry,
+// and false if that block is not in the database
+func (db *Overlay) fetchEntryHeight(hash interfaces.IHash) (uint32, bool, error) {
+	keyMR, err := db.FetchIncludedIn(hash)
+	if err != nil || keyMR == nil {
+		return 0, false, err
+	}
+	eblock, err := db.FetchEBlock(keyMR)
+	if err != nil || eblock == nil {
+		return 0, false, err
+	}
+	return eblock.GetDatabaseHeight(), true, nil
+}
+
+// SaveExtIDIndex indexes an entry.  Entries that are in no entry block yet are left
+// out; they are indexed when they are inserted with their block.
+func (db *Overlay) SaveExtIDIndex(entry interfaces.IEBEntry) error {
+	if !db.ExtIDIndex || entry == nil {
+		return nil
+	}
+	height, ok, err := db.fetchEntryHeight(entry.GetHash())
+	if err != nil || !ok {
+		return err
+	}
+	return db.DB.PutInBatch(extIDIndexRecords(entry, height))
+}
+
+func (db *Overlay) SaveExtIDIndexMultiBatch(entry interfaces.IEBEntry) error {
+	if !db.ExtIDIndex || entry == nil {
+		return nil
+	}
+	height, ok := db.extIDHeights[entry.GetHash().Fixed()]
+	if !ok {
+		var err error
+		height, ok, err = db.fetchEntryHeight(entry.GetHash())
+		if err != nil || !ok {
+			return err
+		}
+	}
+	db.PutInMultiBatch(extIDIndexRecords(entry, height))
+	return nil
+}
+
+// SaveExtIDIndexHeightMultiBatch marks the height of a directory block as indexed.
+// The entries of the height that are not saved yet are indexed when they are.
+func (db *Overlay) SaveExtIDIndexHeightMultiBatch(dblock interfaces.DatabaseBlockWithEntries) {
+	if !db.ExtIDIndex || dblock == nil {
+		return
+	}
+	db.PutInMultiBatch([]interfaces.Record{extIDIndexHeightRecord(dblock.GetDatabaseHeight() + 1)})
+}
+
+// FetchExtIDIndexHeight returns the next height the external ID index has to process
+func (db *Overlay) FetchExtIDIndexHeight() (uint32, error) {
+	bs := new(primitives.ByteSlice)
+	loaded, err := db.FetchKeyValueStore(ExtIDIndexHeightKey, bs)
+	if err != nil {
+		return 0, err
+	}
+	if loaded == nil {
+		return 0, nil
+	}
+	buf := primitives.NewBuffer(bs.Bytes)
+	return buf.PopUInt32()
+}
+
+// BackfillExtIDIndex indexes the entries saved while the external ID index was
+// disabled, from the last indexed height up to the directory block head.  It saves
+// its progress after every height, so it can be interrupted and resumed.  It must
+// not run while blocks are being saved.
+func (db *Overlay) BackfillExtIDIndex() error {
+	next, err := db.FetchExtIDIndexHeight()
+	if err != nil {
+		return err
+	}
+	head, err := db.FetchDBlockHead()
+	if err != nil {
+		return err
+	}
+	if head == nil {
+		return nil
+	}
+
+	for height := next; height <= head.GetDatabaseHeight(); height++ {
+		dblock, err := db.FetchDBlockByHeight(height)
+		if err != nil {
+			return err
+		}
+		if dblock == nil {
+			return fmt.Errorf("Missing directory block at height %d", height)
+		}
+
+		records := []interfaces.Record{}
+		for _, dbEntry := range dblock.GetEBlockDBEntries() {
+			eblock, err := db.FetchEBlock(dbEntry.GetKeyMR())
+			if err != nil {
+				return err
+			}
+			if eblock == nil {
+				return fmt.Errorf("Missing entry block %x at height %d", dbEntry.GetKeyMR().Bytes(), height)
+			}
+			for _, hash := range eblock.GetEntryHashes() {
+				if hash.IsMinuteMarker() {
+					continue
+				}
+				entry, err := db.FetchEntry(hash)
+				if err != nil {
+					return err
+				}
+				// Entries still being synced are indexed when they are saved
+				if entry == nil {
+					continue
+				}
+				records = append(records, extIDIndexRecords(entry, height)...)
+			}
+		}
+
+		err = db.DB.PutInBatch(append(records, extIDIndexHeightRecord(height+1)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RebuildExtIDIndex clears the external ID index and indexes every saved entry
+// again.  It must not run while blocks are being saved.
+func (db *Overlay) RebuildExtIDIndex() error {
+	names, err := db.DB.ListAllKeys(EXTID_INDEX)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		// On LevelDB the keys of the buckets of chain IDs starting with ';' are
+		// listed as well
+		if len(name) != 2*constants.HASH_LENGTH {
+			continue
+		}
+		err = db.DB.Clear(extIDIndexBucket(name))
+		if err != nil {
+			return err
+		}
+	}
+	err = db.DB.Clear(EXTID_INDEX)
+	if err != nil {
+		return err
+	}
+	err = db.DB.Delete(KEY_VALUE_STORE, ExtIDIndexHeightKey)
+	if err != nil {
+		return err
+	}
+	return db.BackfillExtIDIndex()
+}
+
+// FetchEntriesByExtID returns the entries of a chain that have the given external
+// ID, in block order
+func (db *Overlay) FetchEntriesByExtID(chainID interfaces.IHash, extID []byte) ([]interfaces.IExtIDIndexEntry, error) {
+	return db.FetchEntriesByExtIDRange(chainID, extID, nil, false, 0)
+}
+
+// FetchEntriesByExtIDRange returns up to limit entries of a chain that have the given
+// external ID, in block order, or in reverse.  The first one is the entry at the key
+// start, see ExtIDIndexKey, or the next one in that order.  A nil start is the first
+// or the last entry.  A limit of 0 returns all of them.
+func (db *Overlay) FetchEntriesByExtIDRange(chainID interfaces.IHash, extID []byte, start []byte, reverse bool, limit int) ([]interfaces.IExtIDIndexEntry, error) {
+	list, _, err := db.GetRange(extIDIndexBucket(extIDIndexName(chainID, extID)), start, reverse, limit, new(ExtIDIndexEntry))
+	if err != nil {
+		return nil, err
+	}
+	answer := make([]interfaces.IExtIDIndexEntry, len(list))
+	for i, v := range list {
+		answer[i] = v.(interfaces.IExtIDIndexEntry)
+	}
+	return answer, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	. "github.com/FactomProject/factomd/testHelper"
+)
+
+func TestExtIDIndexEntryMarshal(t *testing.T) {
+	ie := new(databaseOverlay.ExtIDIndexEntry)
+	ie.EntryHash = primitives.Sha([]byte("entry"))
+	ie.DBHeight = 1234
+
+	b, err := ie.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ie2 := new(databaseOverlay.ExtIDIndexEntry)
+	err = ie2.UnmarshalBinary(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ie2.EntryHash.IsSameAs(ie.EntryHash) || ie2.DBHeight != ie.DBHeight {
+		t.Errorf("Got %v, expected %v", ie2, ie)
+	}
+}
+
+func TestExtIDIndex(t *testing.T) {
+	blocks := CreateFullTestBlockSet()
+
+	live := CreateEmptyTestDatabaseOverlay()
+	live.SetExtIDIndex(true)
+	PopulateTestDatabaseOverlay(live)
+
+	next, err := live.FetchExtIDIndexHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != uint32(len(blocks)) {
+		t.Errorf("Index height is %d, expected %d", next, len(blocks))
+	}
+
+	backfilled := CreateAndPopulateTestDatabaseOverlay()
+	first := blocks[0].Entries[0]
+	all, err := backfilled.FetchEntriesByExtID(first.GetChainID(), first.ExternalIDs()[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 0 {
+		t.Error("Entries were indexed with the index disabled")
+	}
+	backfilled.SetExtIDIndex(true)
+	err = backfilled.BackfillExtIDIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rebuilt := CreateEmptyTestDatabaseOverlay()
+	rebuilt.SetExtIDIndex(true)
+	PopulateTestDatabaseOverlay(rebuilt)
+	err = rebuilt.RebuildExtIDIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, block := range blocks {
+		height := block.DBlock.GetDatabaseHeight()
+		for _, entry := range block.Entries {
+			for _, extID := range entry.ExternalIDs() {
+				for _, dbo := range []interfaces.DBOverlay{live, backfilled, rebuilt} {
+					list, err := dbo.FetchEntriesByExtID(entry.GetChainID(), extID)
+					if err != nil {
+						t.Fatal(err)
+					}
+					found := false
+					for _, v := range list {
+						if v.GetEntryHash().IsSameAs(entry.GetHash()) {
+							found = true
+							if v.GetDBHeight() != height {
+								t.Errorf("Got height %d, expected %d", v.GetDBHeight(), height)
+							}
+						}
+					}
+					if !found {
+						t.Errorf("Entry %v not indexed for external ID %q", entry.GetHash(), extID)
+					}
+					for i := 1; i < len(list); i++ {
+						if list[i].GetDBHeight() < list[i-1].GetDBHeight() {
+							t.Error("Entries are not in block order")
+						}
+					}
+				}
+			}
+		}
+	}
+
+	list, err := live.FetchEntriesByExtID(primitives.NewZeroHash(), first.ExternalIDs()[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 0 {
+		t.Error("Found entries of the wrong chain")
+	}
+}
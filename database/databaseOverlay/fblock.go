@@ -30,6 +30,13 @@ func (db *Overlay) ProcessFBlockMultiBatch(block interfaces.DatabaseBlockWithEnt
 	if err != nil {
 		return err
 	}
+	if fblock, ok := block.(interfaces.IFBlock); ok {
+		db.SaveAddressIndexFromFBlockMultiBatch(fblock)
+		err = db.SaveBalanceHistoryFromFBlockMultiBatch(fblock)
+		if err != nil {
+			return err
+		}
+	}
 	return db.SaveIncludedInMultiFromBlockMultiBatch(block, true)
 }
 
@@ -9,9 +9,11 @@
 package databaseOverlay
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
 	"os"
+	"sort"
 	"sync"
 
 	"github.com/FactomProject/factomd/common/constants"
@@ -69,6 +71,23 @@ var (
 	PAID_FOR = []byte("PaidFor")
 
 	KEY_VALUE_STORE = []byte("KeyValueStore")
+
+	//Transactions by factoid or entry credit address
+	ADDRESS_TRANSACTIONS = []byte("AddressTransactions")
+
+	//Anchor chain entries by the directory block they anchor
+	ANCHOR_RECORD = []byte("AnchorRecord")
+
+	//Entries by chain ID and external ID
+	EXTID_INDEX = []byte("ExtIDIndex")
+
+	//Statistics of every chain
+	CHAIN_INFO = []byte("ChainInfo")
+
+	//Balance changes by factoid or entry credit address and height
+	FACTOID_BALANCE_HISTORY     = []byte("FactoidBalanceHistory")
+	ENTRYCREDIT_BALANCE_HISTORY = []byte("EntryCreditBalanceHistory")
+	BALANCE_HISTORY_HEAD        = []byte("BalanceHistoryHead")
 )
 
 var ConstantNamesMap map[string]string
@@ -108,6 +127,13 @@ func init() {
 
 	ConstantNamesMap[string(PAID_FOR)] = "PaidFor"
 	ConstantNamesMap[string(KEY_VALUE_STORE)] = "KeyValueStore"
+	ConstantNamesMap[string(ADDRESS_TRANSACTIONS)] = "AddressTransactions"
+	ConstantNamesMap[string(ANCHOR_RECORD)] = "AnchorRecord"
+	ConstantNamesMap[string(EXTID_INDEX)] = "ExtIDIndex"
+	ConstantNamesMap[string(CHAIN_INFO)] = "ChainInfo"
+	ConstantNamesMap[string(FACTOID_BALANCE_HISTORY)] = "FactoidBalanceHistory"
+	ConstantNamesMap[string(ENTRYCREDIT_BALANCE_HISTORY)] = "EntryCreditBalanceHistory"
+	ConstantNamesMap[string(BALANCE_HISTORY_HEAD)] = "BalanceHistoryHead"
 
 	RegisterPrometheus()
 }
@@ -118,6 +144,22 @@ type Overlay struct {
 	ExportData     bool
 	ExportDataPath string
 
+	// AddressIndex enables the index from addresses to their transactions
+	AddressIndex bool
+	// ExtIDIndex enables the index from chain IDs and external IDs to entries
+	ExtIDIndex bool
+	// extIDHeights holds the heights of the entries of the entry blocks put in the
+	// current multi batch, for when the entries are put in the same batch
+	extIDHeights map[[32]byte]uint32
+	// ChainInfo enables the statistics of the chains
+	ChainInfo bool
+	// chainInfos and chainInfoSeen hold the chain statistics changed by the current
+	// multi batch, and the blocks and entries it counted
+	chainInfos    map[[32]byte]*ChainInfo
+	chainInfoSeen map[[32]byte]bool
+	// BalanceHistory enables the history of the balances of the addresses
+	BalanceHistory bool
+
 	BatchSemaphore sync.Mutex
 	MultiBatch     []interfaces.Record
 	BlockExtractor blockExtractor.BlockExtractor
@@ -125,6 +167,7 @@ type Overlay struct {
 
 var _ interfaces.IDatabase = (*Overlay)(nil)
 var _ interfaces.DBOverlay = (*Overlay)(nil)
+var _ interfaces.IRangeDatabase = (*Overlay)(nil)
 
 func (db *Overlay) ListAllBuckets() ([][]byte, error) {
 	return db.DB.ListAllBuckets()
@@ -146,13 +189,29 @@ func (db *Overlay) PutInMultiBatch(records []interfaces.Record) {
 }
 
 func (db *Overlay) ExecuteMultiBatch() error {
-	defer func() {
-		db.MultiBatch = nil
-		db.BatchSemaphore.Unlock()
-	}()
+	defer db.endMultiBatch()
 	return db.PutInBatch(db.MultiBatch)
 }
 
+func (db *Overlay) endMultiBatch() {
+	db.MultiBatch = nil
+	db.extIDHeights = nil
+	db.chainInfos = nil
+	db.chainInfoSeen = nil
+	db.BatchSemaphore.Unlock()
+}
+
+// inMultiBatch runs put in a multi batch of its own, and saves the batch if put succeeds
+func (db *Overlay) inMultiBatch(put func() error) error {
+	db.StartMultiBatch()
+	err := put()
+	if err != nil {
+		db.endMultiBatch()
+		return err
+	}
+	return db.ExecuteMultiBatch()
+}
+
 func (db *Overlay) PutInBatch(records []interfaces.Record) error {
 	return db.DB.PutInBatch(records)
 }
@@ -169,6 +228,39 @@ func (db *Overlay) GetAll(bucket []byte, sample interfaces.BinaryMarshallableAnd
 	return db.DB.GetAll(bucket, sample)
 }
 
+// GetRange reads part of a bucket, see interfaces.IRangeDatabase.  With a database
+// that can not read ranges, it loads the whole bucket.
+func (db *Overlay) GetRange(bucket, start []byte, reverse bool, limit int, sample interfaces.BinaryMarshallableAndCopyable) ([]interfaces.BinaryMarshallableAndCopyable, [][]byte, error) {
+	if r, ok := db.DB.(interfaces.IRangeDatabase); ok {
+		return r.GetRange(bucket, start, reverse, limit, sample)
+	}
+
+	all, keys, err := db.DB.GetAll(bucket, sample)
+	if err != nil {
+		return nil, nil, err
+	}
+	i, step := 0, 1
+	if reverse {
+		i, step = len(keys)-1, -1
+	}
+	if start != nil {
+		i = sort.Search(len(keys), func(j int) bool {
+			c := bytes.Compare(keys[j], start)
+			return c > 0 || (c == 0 && !reverse)
+		})
+		if reverse {
+			i--
+		}
+	}
+	answer := []interfaces.BinaryMarshallableAndCopyable{}
+	rangeKeys := [][]byte{}
+	for ; i >= 0 && i < len(keys) && (limit == 0 || len(answer) < limit); i += step {
+		answer = append(answer, all[i])
+		rangeKeys = append(rangeKeys, keys[i])
+	}
+	return answer, rangeKeys, nil
+}
+
 func (db *Overlay) Get(bucket, key []byte, destination interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
 	GetBucket(bucket)
 	return db.DB.Get(bucket, key, destination)
@@ -0,0 +1,105 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// Pruning deletes the content of entries but keeps their ENTRY index record, so
+// a pruned entry can be told apart from an entry we never had.
+
+var PrunedHeightKey = []byte("PrunedHeight")
+
+// PruneEBlockEntries deletes the content of the entries of an entry block, but for the
+// ones in keep.  The same entry can be written to a chain more than once, and all its
+// copies share the content, so the entries of the later blocks that are not pruned
+// have to be kept, see FetchEntryHashesByChainFrom.  The entry block itself is kept.
+func (db *Overlay) PruneEBlockEntries(eblock interfaces.IEntryBlock, keep map[[32]byte]bool) error {
+	chainID := eblock.GetChainID().Bytes()
+	for _, h := range eblock.GetEntryHashes() {
+		if h.IsMinuteMarker() || keep[h.Fixed()] {
+			continue
+		}
+		err := db.DB.Delete(chainID, h.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FetchEntryHashesByChainFrom returns the entries of the entry blocks of a chain at or
+// above a height, walking back from the chain head
+func (db *Overlay) FetchEntryHashesByChainFrom(chainID interfaces.IHash, height uint32) ([]interfaces.IHash, error) {
+	hashes := []interfaces.IHash{}
+	eblock, err := db.FetchEBlockHead(chainID)
+	if err != nil {
+		return nil, err
+	}
+	for eblock != nil && eblock.GetDatabaseHeight() >= height {
+		for _, h := range eblock.GetEntryHashes() {
+			if !h.IsMinuteMarker() {
+				hashes = append(hashes, h)
+			}
+		}
+		if eblock.GetHeader().GetEBSequence() == 0 {
+			break
+		}
+		prev := eblock.GetHeader().GetPrevKeyMR()
+		eblock, err = db.FetchEBlock(prev)
+		if err != nil {
+			return nil, err
+		}
+		if eblock == nil {
+			return nil, fmt.Errorf("Missing entry block %x of chain %x", prev.Bytes()[:3], chainID.Bytes()[:3])
+		}
+	}
+	return hashes, nil
+}
+
+// IsEntryPruned returns true if we had the entry, but its content was pruned
+func (db *Overlay) IsEntryPruned(hash interfaces.IHash) (bool, error) {
+	chainID, err := db.FetchPrimaryIndexBySecondaryIndex(ENTRY, hash)
+	if err != nil {
+		return false, err
+	}
+	if chainID == nil {
+		return false, nil
+	}
+	exists, err := db.DB.DoesKeyExist(chainID.Bytes(), hash.Bytes())
+	if err != nil {
+		return false, err
+	}
+	return !exists, nil
+}
+
+// SavePrunedHeight records that entries are pruned below the given height
+func (db *Overlay) SavePrunedHeight(height uint32) error {
+	buf := primitives.NewBuffer(nil)
+	buf.PushUInt32(height)
+	bs := new(primitives.ByteSlice)
+	bs.Bytes = buf.DeepCopyBytes()
+
+	return db.SaveKeyValueStore(bs, PrunedHeightKey)
+}
+
+// FetchPrunedHeight returns the height below which entries are pruned, 0 if the
+// database was never pruned
+func (db *Overlay) FetchPrunedHeight() (uint32, error) {
+	bs := new(primitives.ByteSlice)
+	loaded, err := db.FetchKeyValueStore(PrunedHeightKey, bs)
+	if err != nil {
+		return 0, err
+	}
+	if loaded == nil {
+		return 0, nil
+	}
+	buf := primitives.NewBuffer(bs.Bytes)
+	return buf.PopUInt32()
+}
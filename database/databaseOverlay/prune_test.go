@@ -0,0 +1,109 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/testHelper"
+)
+
+func TestPruneEBlockEntries(t *testing.T) {
+	blocks := CreateFullTestBlockSet()
+	dbo := CreateAndPopulateTestDatabaseOverlay()
+
+	pruned := blocks[1].EBlock
+	// As if the first entry was written again in a block that is kept
+	kept := pruned.GetEntryHashes()[0]
+	err := dbo.PruneEBlockEntries(pruned, map[[32]byte]bool{kept.Fixed(): true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, block := range blocks {
+		for _, h := range block.EBlock.GetEntryHashes() {
+			if h.IsMinuteMarker() {
+				continue
+			}
+			entry, err := dbo.FetchEntry(h)
+			if err != nil {
+				t.Fatal(err)
+			}
+			isPruned, err := dbo.IsEntryPruned(h)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if i == 1 && !h.IsSameAs(kept) {
+				if entry != nil || !isPruned {
+					t.Errorf("Entry %v at height %d was not pruned", h, i)
+				}
+			} else {
+				if entry == nil || isPruned {
+					t.Errorf("Entry %v at height %d was pruned", h, i)
+				}
+			}
+		}
+	}
+
+	isPruned, err := dbo.IsEntryPruned(primitives.NewZeroHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isPruned {
+		t.Error("An unknown entry is reported as pruned")
+	}
+}
+
+func TestFetchEntryHashesByChainFrom(t *testing.T) {
+	blocks := CreateFullTestBlockSet()
+	dbo := CreateAndPopulateTestDatabaseOverlay()
+
+	from := len(blocks) - 3
+	expected := map[[32]byte]bool{}
+	for _, block := range blocks[from:] {
+		for _, h := range block.EBlock.GetEntryHashes() {
+			if !h.IsMinuteMarker() {
+				expected[h.Fixed()] = true
+			}
+		}
+	}
+	hashes, err := dbo.FetchEntryHashesByChainFrom(blocks[0].EBlock.GetChainID(), uint32(from))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hashes) != len(expected) {
+		t.Errorf("Got %d entries, expected %d", len(hashes), len(expected))
+	}
+	for _, h := range hashes {
+		if !expected[h.Fixed()] {
+			t.Errorf("Entry %v is below height %d", h, from)
+		}
+	}
+}
+
+func TestPrunedHeight(t *testing.T) {
+	dbo := CreateEmptyTestDatabaseOverlay()
+
+	height, err := dbo.FetchPrunedHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if height != 0 {
+		t.Errorf("Pruned height of an empty database is %d", height)
+	}
+
+	err = dbo.SavePrunedHeight(1234)
+	if err != nil {
+		t.Fatal(err)
+	}
+	height, err = dbo.FetchPrunedHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if height != 1234 {
+		t.Errorf("Pruned height is %d, expected 1234", height)
+	}
+}
@@ -0,0 +1,594 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/directoryBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// A snapshot is a copy of every record in the database, taken at a directory block
+// boundary.  The archive is a gzip stream holding:
+//
+//	the magic, "FCTSNAP1"
+//	the manifest, as a 4 byte length and JSON
+//	the records, each a 4 byte length and data for the bucket, the key and the value
+//	a 4 byte 0xFFFFFFFF marking the end of the records
+//	the number of records (8 bytes) and the sha256 of everything before it
+//
+// The manifest lists the KeyMR of every directory block in the snapshot, so the
+// restored database can be checked block by block against it.
+
+var snapshotMagic = []byte("FCTSNAP1")
+
+const (
+	SnapshotVersion   = 1
+	snapshotEnd       = 0xFFFFFFFF
+	maxSnapshotRecord = 64 * 1024 * 1024
+	snapshotBatchSize = 1000
+	// The records of a batch are written in one transaction, which some databases
+	// limit in size
+	snapshotBatchBytes = 4 * 1024 * 1024
+)
+
+type SnapshotManifest struct {
+	Version   int      `json:"version"`
+	Network   string   `json:"network"`
+	Height    uint32   `json:"height"`
+	Timestamp int64    `json:"timestamp"`
+	KeyMRs    []string `json:"keymrs"` // directory block KeyMRs, from height 0 to Height
+}
+
+// WriteSnapshot writes every record of the database to w.  The records are read from
+// a view of the database, so blocks can go on being saved while it runs, and the
+// snapshot is at the directory block head of the view.
+func (db *Overlay) WriteSnapshot(w io.Writer, network string) (*SnapshotManifest, error) {
+	v, ok := db.DB.(interfaces.IViewDatabase)
+	if !ok {
+		return nil, fmt.Errorf("%T does not support snapshots", db.DB)
+	}
+	view, err := v.GetView(splitOverlayKey)
+	if err != nil {
+		return nil, err
+	}
+	defer view.Release()
+
+	m, err := NewOverlay(&viewDatabase{view: view}).snapshotManifest(network)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(w)
+	sw := &snapshotWriter{w: bufio.NewWriter(gz), hash: sha256.New()}
+	sw.write(snapshotMagic)
+	sw.writeField(manifest)
+
+	var count uint64
+	err = view.ForEach(func(bucket, key, value []byte) error {
+		sw.writeField(bucket)
+		sw.writeField(key)
+		sw.writeField(value)
+		count++
+		return sw.err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var end [4 + 8]byte
+	binary.BigEndian.PutUint32(end[:4], snapshotEnd)
+	binary.BigEndian.PutUint64(end[4:], count)
+	sw.write(end[:])
+	sum := sw.hash.Sum(nil)
+	sw.write(sum)
+	if sw.err != nil {
+		return nil, sw.err
+	}
+	err = sw.w.Flush()
+	if err != nil {
+		return nil, err
+	}
+	err = gz.Close()
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// snapshotManifest lists the directory blocks up to the head
+func (db *Overlay) snapshotManifest(network string) (*SnapshotManifest, error) {
+	head, err := db.FetchDBlockHead()
+	if err != nil {
+		return nil, err
+	}
+	if head == nil {
+		return nil, fmt.Errorf("The database is empty")
+	}
+
+	m := new(SnapshotManifest)
+	m.Version = SnapshotVersion
+	m.Network = network
+	m.Height = head.GetDatabaseHeight()
+	m.Timestamp = time.Now().Unix()
+	for h := uint32(0); h <= m.Height; h++ {
+		keyMR, err := db.FetchDBKeyMRByHeight(h)
+		if err != nil {
+			return nil, err
+		}
+		if keyMR == nil {
+			return nil, fmt.Errorf("Missing directory block %d", h)
+		}
+		m.KeyMRs = append(m.KeyMRs, keyMR.String())
+	}
+	return m, nil
+}
+
+// viewDatabase lets the fetch functions of an Overlay read a view.  Only Get is
+// implemented, the rest of the IDatabase is left nil.
+type viewDatabase struct {
+	interfaces.IDatabase
+	view interfaces.IDatabaseView
+}
+
+func (v *viewDatabase) Get(bucket, key []byte, destination interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
+	return v.view.Get(bucket, key, destination)
+}
+
+// overlayBucketFamily is a bucket name made of a constant prefix and a hash, with
+// keys of a fixed length
+type overlayBucketFamily struct {
+	prefix  []byte
+	nameLen int
+	keyLen  int
+}
+
+var overlayBucketFamilies = []overlayBucketFamily{
+	{ENTRYBLOCK_CHAIN_NUMBER, constants.HASH_LENGTH, 4},
+	{ADDRESS_TRANSACTIONS, constants.HASH_LENGTH, 4 + constants.HASH_LENGTH + 1},
+	{EXTID_INDEX, 2 * constants.HASH_LENGTH, 4 + constants.HASH_LENGTH},
+	{FACTOID_BALANCE_HISTORY, constants.HASH_LENGTH, 4},
+	{ENTRYCREDIT_BALANCE_HISTORY, constants.HASH_LENGTH, 4},
+}
+
+var overlayBuckets = [][]byte{
+	DIRECTORYBLOCK, DIRECTORYBLOCK_NUMBER, DIRECTORYBLOCK_SECONDARYINDEX,
+	ADMINBLOCK, ADMINBLOCK_NUMBER, ADMINBLOCK_SECONDARYINDEX,
+	FACTOIDBLOCK, FACTOIDBLOCK_NUMBER, FACTOIDBLOCK_SECONDARYINDEX,
+	ENTRYCREDITBLOCK, ENTRYCREDITBLOCK_NUMBER, ENTRYCREDITBLOCK_SECONDARYINDEX,
+	CHAIN_HEAD,
+	ENTRYBLOCK, ENTRYBLOCK_SECONDARYINDEX,
+	ENTRY,
+	DIRBLOCKINFO, DIRBLOCKINFO_UNCONFIRMED, DIRBLOCKINFO_NUMBER, DIRBLOCKINFO_SECONDARYINDEX,
+	INCLUDED_IN, PAID_FOR, KEY_VALUE_STORE, ADDRESS_TRANSACTIONS, ANCHOR_RECORD,
+	EXTID_INDEX, CHAIN_INFO, BALANCE_HISTORY_HEAD,
+}
+
+// splitOverlayKey is the BucketSplitter of the overlay buckets.  The buckets named
+// after a chain ID or an address can hold any byte, so they are told apart by the
+// length of their keys.
+func splitOverlayKey(stored []byte, sepLen int) int {
+	for _, f := range overlayBucketFamilies {
+		if len(stored) == len(f.prefix)+f.nameLen+sepLen+f.keyLen && bytes.HasPrefix(stored, f.prefix) {
+			return len(f.prefix) + f.nameLen
+		}
+	}
+	longest := -1
+	for _, b := range overlayBuckets {
+		if len(b) > longest && bytes.HasPrefix(stored, b) {
+			longest = len(b)
+		}
+	}
+	if longest >= 0 {
+		return longest
+	}
+	// The entries of a chain are in the bucket of its chain ID
+	if len(stored) == constants.HASH_LENGTH+sepLen+constants.HASH_LENGTH {
+		return constants.HASH_LENGTH
+	}
+	return -1
+}
+
+// snapshotWriter keeps the first error, so writes can be checked once per bucket
+type snapshotWriter struct {
+	w    *bufio.Writer
+	hash hash.Hash
+	err  error
+}
+
+func (sw *snapshotWriter) write(data []byte) {
+	if sw.err != nil {
+		return
+	}
+	sw.hash.Write(data)
+	_, sw.err = sw.w.Write(data)
+}
+
+func (sw *snapshotWriter) writeField(data []byte) {
+	var l [4]byte
+	binary.BigEndian.PutUint32(l[:], uint32(len(data)))
+	sw.write(l[:])
+	sw.write(data)
+}
+
+// ReadSnapshotManifest reads the manifest at the start of a snapshot
+func ReadSnapshotManifest(r io.Reader) (*SnapshotManifest, error) {
+	sr, err := newSnapshotReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return sr.readManifest()
+}
+
+// RestoreSnapshot loads a snapshot into an empty database.  The snapshot is read
+// twice: the first pass checks the checksum and the directory blocks against the
+// manifest without writing anything, the second writes the records.  The restored
+// blocks are verified against the manifest at the end.
+func (db *Overlay) RestoreSnapshot(r io.ReadSeeker) (*SnapshotManifest, error) {
+	head, err := db.FetchDBlockHead()
+	if err != nil {
+		return nil, err
+	}
+	if head != nil {
+		return nil, fmt.Errorf("The database already holds %d blocks, a snapshot can only be restored into an empty database", head.GetDatabaseHeight()+1)
+	}
+
+	sr, err := newSnapshotReader(r)
+	if err != nil {
+		return nil, err
+	}
+	m, err := sr.readManifest()
+	if err != nil {
+		return nil, err
+	}
+	check, err := newSnapshotCheck(m)
+	if err != nil {
+		return nil, err
+	}
+	err = sr.readRecords(check.add)
+	if err != nil {
+		return nil, err
+	}
+	err = check.finish()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+	sr, err = newSnapshotReader(r)
+	if err != nil {
+		return nil, err
+	}
+	_, err = sr.readManifest()
+	if err != nil {
+		return nil, err
+	}
+	batch := []interfaces.Record{}
+	batchBytes := 0
+	err = sr.readRecords(func(bucket, key, value []byte) error {
+		batch = append(batch, interfaces.Record{Bucket: bucket, Key: key, Data: &primitives.ByteSlice{Bytes: value}})
+		batchBytes += len(bucket) + len(key) + len(value)
+		if len(batch) < snapshotBatchSize && batchBytes < snapshotBatchBytes {
+			return nil
+		}
+		err := db.DB.PutInBatch(batch)
+		batch = []interfaces.Record{}
+		batchBytes = 0
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = db.DB.PutInBatch(batch)
+	if err != nil {
+		return nil, err
+	}
+	return m, db.VerifySnapshot(m)
+}
+
+// snapshotCheck checks the records of a snapshot against its manifest as they are
+// read, keeping the references between the blocks rather than the blocks
+type snapshotCheck struct {
+	m       *SnapshotManifest
+	keyMRs  [][]byte
+	head    []byte
+	numbers map[uint32][]byte
+	found   []bool
+	// refs are the admin, entry credit and factoid blocks of every height
+	refs [][3][]byte
+	// blocks holds the bucket and key of every admin, entry credit and factoid
+	// block, and secondary their secondary indexes
+	blocks    map[string]bool
+	secondary map[string][]byte
+}
+
+// snapshotBlockBuckets are the buckets and secondary indexes of the blocks a directory
+// block points to, in the order of its entries
+var snapshotBlockBuckets = [3][2][]byte{
+	{ADMINBLOCK, ADMINBLOCK_SECONDARYINDEX},
+	{ENTRYCREDITBLOCK, ENTRYCREDITBLOCK_SECONDARYINDEX},
+	{FACTOIDBLOCK, FACTOIDBLOCK_SECONDARYINDEX},
+}
+
+func newSnapshotCheck(m *SnapshotManifest) (*snapshotCheck, error) {
+	if len(m.KeyMRs) != int(m.Height)+1 {
+		return nil, fmt.Errorf("The manifest lists %d KeyMRs for height %d", len(m.KeyMRs), m.Height)
+	}
+	c := new(snapshotCheck)
+	c.m = m
+	for _, s := range m.KeyMRs {
+		keyMR, err := primitives.HexToHash(s)
+		if err != nil {
+			return nil, err
+		}
+		c.keyMRs = append(c.keyMRs, keyMR.Bytes())
+	}
+	c.numbers = map[uint32][]byte{}
+	c.found = make([]bool, len(c.keyMRs))
+	c.refs = make([][3][]byte, len(c.keyMRs))
+	c.blocks = map[string]bool{}
+	c.secondary = map[string][]byte{}
+	return c, nil
+}
+
+func (c *snapshotCheck) add(bucket, key, value []byte) error {
+	switch {
+	case bytes.Equal(bucket, CHAIN_HEAD):
+		if bytes.Equal(key, new(directoryBlock.DirectoryBlock).GetChainID().Bytes()) {
+			c.head = value
+		}
+	case bytes.Equal(bucket, DIRECTORYBLOCK_NUMBER):
+		if len(key) == 4 {
+			c.numbers[binary.BigEndian.Uint32(key)] = value
+		}
+	case bytes.Equal(bucket, DIRECTORYBLOCK):
+		dblock := new(directoryBlock.DirectoryBlock)
+		err := dblock.UnmarshalBinary(value)
+		if err != nil {
+			return fmt.Errorf("Directory block %x does not unmarshal: %v", key, err)
+		}
+		h := dblock.GetDatabaseHeight()
+		if int(h) >= len(c.keyMRs) || !bytes.Equal(dblock.GetKeyMR().Bytes(), c.keyMRs[h]) {
+			// Not one of the blocks of the manifest
+			return nil
+		}
+		if h > 0 && !bytes.Equal(dblock.GetHeader().GetPrevKeyMR().Bytes(), c.keyMRs[h-1]) {
+			return fmt.Errorf("Directory block %d does not link to block %d", h, h-1)
+		}
+		entries := dblock.GetDBEntries()
+		if len(entries) < 3 {
+			return fmt.Errorf("Directory block %d has %d entries", h, len(entries))
+		}
+		for i := range c.refs[h] {
+			c.refs[h][i] = entries[i].GetKeyMR().Bytes()
+		}
+		c.found[h] = true
+	default:
+		for _, b := range snapshotBlockBuckets {
+			if bytes.Equal(bucket, b[0]) {
+				c.blocks[string(bucket)+string(key)] = true
+			}
+			if bytes.Equal(bucket, b[1]) {
+				c.secondary[string(bucket)+string(key)] = value
+			}
+		}
+	}
+	return nil
+}
+
+// finish checks that every block of the manifest was read
+func (c *snapshotCheck) finish() error {
+	if !bytes.Equal(c.head, c.keyMRs[c.m.Height]) {
+		return fmt.Errorf("The directory block head is not at height %d", c.m.Height)
+	}
+	for h := range c.keyMRs {
+		if !bytes.Equal(c.numbers[uint32(h)], c.keyMRs[h]) {
+			return fmt.Errorf("Directory block %d is %x, the manifest has %s", h, c.numbers[uint32(h)], c.m.KeyMRs[h])
+		}
+		if !c.found[h] {
+			return fmt.Errorf("Missing directory block %d", h)
+		}
+		for i, b := range snapshotBlockBuckets {
+			ref := string(c.refs[h][i])
+			if c.blocks[string(b[0])+ref] {
+				continue
+			}
+			primary, ok := c.secondary[string(b[1])+ref]
+			if !ok || !c.blocks[string(b[0])+string(primary)] {
+				return fmt.Errorf("Missing %s at height %d", b[0], h)
+			}
+		}
+	}
+	return nil
+}
+
+// VerifySnapshot checks the directory blocks in the database, and the admin, entry
+// credit and factoid blocks they point to, against the manifest of a snapshot
+func (db *Overlay) VerifySnapshot(m *SnapshotManifest) error {
+	if len(m.KeyMRs) != int(m.Height)+1 {
+		return fmt.Errorf("The manifest lists %d KeyMRs for height %d", len(m.KeyMRs), m.Height)
+	}
+	head, err := db.FetchDBlockHead()
+	if err != nil {
+		return err
+	}
+	if head == nil || head.GetDatabaseHeight() != m.Height {
+		return fmt.Errorf("The directory block head is not at height %d", m.Height)
+	}
+
+	for h := uint32(0); h <= m.Height; h++ {
+		keyMR, err := db.FetchDBKeyMRByHeight(h)
+		if err != nil {
+			return err
+		}
+		if keyMR == nil || keyMR.String() != m.KeyMRs[h] {
+			return fmt.Errorf("Directory block %d is %v, the manifest has %s", h, keyMR, m.KeyMRs[h])
+		}
+		dblock, err := db.FetchDBlock(keyMR)
+		if err != nil {
+			return err
+		}
+		if dblock == nil {
+			return fmt.Errorf("Missing directory block %d", h)
+		}
+		if dblock.GetKeyMR().String() != m.KeyMRs[h] || dblock.GetDatabaseHeight() != h {
+			return fmt.Errorf("Directory block %d does not match its KeyMR", h)
+		}
+		if h > 0 && dblock.GetHeader().GetPrevKeyMR().String() != m.KeyMRs[h-1] {
+			return fmt.Errorf("Directory block %d does not link to block %d", h, h-1)
+		}
+
+		entries := dblock.GetDBEntries()
+		if len(entries) < 3 {
+			return fmt.Errorf("Directory block %d has %d entries", h, len(entries))
+		}
+		ablock, err := db.FetchABlock(entries[0].GetKeyMR())
+		if err != nil || ablock == nil {
+			return fmt.Errorf("Missing admin block at height %d: %v", h, err)
+		}
+		ecblock, err := db.FetchECBlock(entries[1].GetKeyMR())
+		if err != nil || ecblock == nil {
+			return fmt.Errorf("Missing entry credit block at height %d: %v", h, err)
+		}
+		fblock, err := db.FetchFBlock(entries[2].GetKeyMR())
+		if err != nil || fblock == nil {
+			return fmt.Errorf("Missing factoid block at height %d: %v", h, err)
+		}
+	}
+	return nil
+}
+
+var errSnapshotEnd = fmt.Errorf("End of the snapshot records")
+
+type snapshotReader struct {
+	r    io.Reader // hashes what is read
+	hash hash.Hash
+}
+
+func newSnapshotReader(r io.Reader) (*snapshotReader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	sr := &snapshotReader{hash: sha256.New()}
+	sr.r = io.TeeReader(bufio.NewReader(gz), sr.hash)
+	return sr, nil
+}
+
+func (sr *snapshotReader) readManifest() (*SnapshotManifest, error) {
+	magic := make([]byte, len(snapshotMagic))
+	_, err := io.ReadFull(sr.r, magic)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(magic, snapshotMagic) {
+		return nil, fmt.Errorf("Not a factomd snapshot")
+	}
+	data, err := sr.readField()
+	if err != nil {
+		return nil, err
+	}
+	m := new(SnapshotManifest)
+	err = json.Unmarshal(data, m)
+	if err != nil {
+		return nil, err
+	}
+	if m.Version != SnapshotVersion {
+		return nil, fmt.Errorf("Unsupported snapshot version %d", m.Version)
+	}
+	return m, nil
+}
+
+func (sr *snapshotReader) readField() ([]byte, error) {
+	var l [4]byte
+	_, err := io.ReadFull(sr.r, l[:])
+	if err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(l[:])
+	if size == snapshotEnd {
+		return nil, errSnapshotEnd
+	}
+	if size > maxSnapshotRecord {
+		return nil, fmt.Errorf("Snapshot record of %d bytes is over the limit", size)
+	}
+	data := make([]byte, size)
+	_, err = io.ReadFull(sr.r, data)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readRecords calls f with every record up to the end of the records, then checks
+// the trailer
+func (sr *snapshotReader) readRecords(f func(bucket, key, value []byte) error) error {
+	var count uint64
+	for {
+		bucket, err := sr.readField()
+		if err == errSnapshotEnd {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		key, err := sr.readField()
+		if err != nil {
+			return err
+		}
+		value, err := sr.readField()
+		if err != nil {
+			return err
+		}
+		err = f(bucket, key, value)
+		if err != nil {
+			return err
+		}
+		count++
+	}
+	return sr.readTrailer(count)
+}
+
+// readTrailer checks the record count and the checksum at the end of the snapshot
+func (sr *snapshotReader) readTrailer(count uint64) error {
+	var c [8]byte
+	_, err := io.ReadFull(sr.r, c[:])
+	if err != nil {
+		return err
+	}
+	if binary.BigEndian.Uint64(c[:]) != count {
+		return fmt.Errorf("Read %d records, the snapshot has %d", count, binary.BigEndian.Uint64(c[:]))
+	}
+
+	expected := sr.hash.Sum(nil)
+	sum := make([]byte, len(expected))
+	_, err = io.ReadFull(sr.r, sum)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(sum, expected) {
+		return fmt.Errorf("The snapshot checksum does not match, the file is corrupt")
+	}
+	return nil
+}
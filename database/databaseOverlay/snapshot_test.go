@@ -0,0 +1,185 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/leveldb"
+	. "github.com/FactomProject/factomd/testHelper"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	dbo := CreateAndPopulateTestDatabaseOverlay()
+
+	var buf bytes.Buffer
+	m, err := dbo.WriteSnapshot(&buf, "LOCAL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Height != uint32(BlockCount-1) || len(m.KeyMRs) != BlockCount {
+		t.Fatalf("Manifest has height %d and %d KeyMRs", m.Height, len(m.KeyMRs))
+	}
+	archive := buf.Bytes()
+
+	restored := CreateEmptyTestDatabaseOverlay()
+	m2, err := restored.RestoreSnapshot(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m2.Network != "LOCAL" || m2.Height != m.Height {
+		t.Errorf("Restored manifest %v, expected %v", m2, m)
+	}
+
+	buckets, err := dbo.ListAllBuckets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range buckets {
+		keys, _ := dbo.ListAllKeys(b)
+		restoredKeys, _ := restored.ListAllKeys(b)
+		if len(keys) != len(restoredKeys) {
+			t.Errorf("Bucket %s has %d keys, %d restored", b, len(keys), len(restoredKeys))
+		}
+	}
+
+	// Restoring over a database that has blocks is refused
+	_, err = restored.RestoreSnapshot(bytes.NewReader(archive))
+	if err == nil {
+		t.Error("Restored a snapshot over an existing database")
+	}
+}
+
+func TestSnapshotCorrupt(t *testing.T) {
+	dbo := CreateAndPopulateTestDatabaseOverlay()
+
+	var buf bytes.Buffer
+	_, err := dbo.WriteSnapshot(&buf, "LOCAL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := buf.Bytes()
+
+	truncated := archive[:len(archive)/2]
+	restored := CreateEmptyTestDatabaseOverlay()
+	_, err = restored.RestoreSnapshot(bytes.NewReader(truncated))
+	if err == nil {
+		t.Error("Restored a truncated snapshot")
+	}
+	// Nothing is written before the snapshot is checked
+	buckets, err := restored.ListAllBuckets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets) != 0 {
+		t.Errorf("A truncated snapshot wrote %d buckets", len(buckets))
+	}
+
+	corrupt := append([]byte{}, archive...)
+	corrupt[len(corrupt)/2] ^= 0xff
+	restored = CreateEmptyTestDatabaseOverlay()
+	_, err = restored.RestoreSnapshot(bytes.NewReader(corrupt))
+	if err == nil {
+		t.Error("Restored a corrupt snapshot")
+	}
+	buckets, err = restored.ListAllBuckets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets) != 0 {
+		t.Errorf("A corrupt snapshot wrote %d buckets", len(buckets))
+	}
+}
+
+func TestSnapshotLevelDB(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ldb, err := leveldb.NewLevelDB(filepath.Join(dir, "source"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbo := databaseOverlay.NewOverlay(ldb)
+	defer dbo.Close()
+	PopulateTestDatabaseOverlay(dbo)
+
+	var buf bytes.Buffer
+	m, err := dbo.WriteSnapshot(&buf, "LOCAL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Height != uint32(BlockCount-1) {
+		t.Fatalf("Manifest has height %d", m.Height)
+	}
+	archive := buf.Bytes()
+
+	// Into a LevelDB, and into a map, where the buckets are kept apart from the keys
+	ldb2, err := leveldb.NewLevelDB(filepath.Join(dir, "restored"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restoredLDB := databaseOverlay.NewOverlay(ldb2)
+	defer restoredLDB.Close()
+	_, err = restoredLDB.RestoreSnapshot(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+	restoredMap := CreateEmptyTestDatabaseOverlay()
+	_, err = restoredMap.RestoreSnapshot(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buckets, err := restoredMap.ListAllBuckets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets) == 0 {
+		t.Fatal("Restored no buckets")
+	}
+	for _, b := range buckets {
+		keys, _ := dbo.ListAllKeys(b)
+		ldbKeys, _ := restoredLDB.ListAllKeys(b)
+		mapKeys, _ := restoredMap.ListAllKeys(b)
+		if len(keys) != len(mapKeys) || len(keys) != len(ldbKeys) {
+			t.Errorf("Bucket %x has %d keys, %d restored to LevelDB and %d to a map", b, len(keys), len(ldbKeys), len(mapKeys))
+		}
+	}
+}
+
+func TestVerifySnapshot(t *testing.T) {
+	dbo := CreateAndPopulateTestDatabaseOverlay()
+
+	var buf bytes.Buffer
+	m, err := dbo.WriteSnapshot(&buf, "LOCAL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dbo.VerifySnapshot(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m.KeyMRs[3] = m.KeyMRs[4]
+	err = dbo.VerifySnapshot(m)
+	if err == nil {
+		t.Error("Verified a manifest with a wrong KeyMR")
+	}
+
+	m.KeyMRs = m.KeyMRs[:len(m.KeyMRs)-1]
+	m.Height--
+	err = dbo.VerifySnapshot(m)
+	if err == nil {
+		t.Error("Verified a manifest below the database head")
+	}
+}
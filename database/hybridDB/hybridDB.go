@@ -1,6 +1,7 @@
 package hybridDB
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/FactomProject/factomd/common/interfaces"
@@ -17,6 +18,8 @@ type HybridDB struct {
 }
 
 var _ interfaces.IDatabase = (*HybridDB)(nil)
+var _ interfaces.IRangeDatabase = (*HybridDB)(nil)
+var _ interfaces.IViewDatabase = (*HybridDB)(nil)
 
 func (db *HybridDB) ListAllBuckets() ([][]byte, error) {
 	db.Sem.RLock()
@@ -158,6 +161,29 @@ func (db *HybridDB) GetAll(bucket []byte, sample interfaces.BinaryMarshallableAn
 	return db.persistentStorage.GetAll(bucket, sample)
 }
 
+func (db *HybridDB) GetRange(bucket, start []byte, reverse bool, limit int, sample interfaces.BinaryMarshallableAndCopyable) ([]interfaces.BinaryMarshallableAndCopyable, [][]byte, error) {
+	db.Sem.RLock()
+	defer db.Sem.RUnlock()
+
+	r, ok := db.persistentStorage.(interfaces.IRangeDatabase)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T can not read ranges", db.persistentStorage)
+	}
+	return r.GetRange(bucket, start, reverse, limit, sample)
+}
+
+// GetView is a view of the persistent storage, which holds everything
+func (db *HybridDB) GetView(split interfaces.BucketSplitter) (interfaces.IDatabaseView, error) {
+	db.Sem.RLock()
+	defer db.Sem.RUnlock()
+
+	v, ok := db.persistentStorage.(interfaces.IViewDatabase)
+	if !ok {
+		return nil, fmt.Errorf("%T can not open views", db.persistentStorage)
+	}
+	return v.GetView(split)
+}
+
 func (db *HybridDB) Clear(bucket []byte) error {
 	db.Sem.Lock()
 	defer db.Sem.Unlock()
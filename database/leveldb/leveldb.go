@@ -5,6 +5,7 @@
 package leveldb
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"strings"
@@ -28,6 +29,8 @@ type LevelDB struct {
 }
 
 var _ interfaces.IDatabase = (*LevelDB)(nil)
+var _ interfaces.IRangeDatabase = (*LevelDB)(nil)
+var _ interfaces.IViewDatabase = (*LevelDB)(nil)
 
 func (db *LevelDB) ListAllBuckets() ([][]byte, error) {
 	//TODO: fix Level to solve this issue
@@ -221,6 +224,44 @@ func (db *LevelDB) ListAllKeys(bucket []byte) (keys [][]byte, err error) {
 	return answer, nil
 }
 
+// ListRecordKeys lists up to max records of the database, from the raw key start on,
+// and returns the raw key to continue from, nil after the last record.  It lets tools
+// go through the whole database, as the buckets can not be listed.  Bucket names can
+// contain ';', so the bucket and the key of a record are only split at the first one,
+// but they always name the same record.
+func (db *LevelDB) ListRecordKeys(start []byte, max int) (buckets [][]byte, keys [][]byte, next []byte, err error) {
+	db.dbLock.RLock()
+	defer db.dbLock.RUnlock()
+
+	iter := db.lDB.NewIterator(&util.Range{Start: start, Limit: nil}, db.ro)
+
+	for iter.Next() {
+		k := iter.Key()
+		if len(keys) == max {
+			next = make([]byte, len(k))
+			copy(next, k)
+			break
+		}
+		i := bytes.IndexByte(k, ';')
+		if i < 0 {
+			continue
+		}
+		bucket := make([]byte, i)
+		copy(bucket, k[:i])
+		key := make([]byte, len(k)-i-1)
+		copy(key, k[i+1:])
+		buckets = append(buckets, bucket)
+		keys = append(keys, key)
+	}
+	iter.Release()
+	err = iter.Error()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return buckets, keys, next, nil
+}
+
 func (db *LevelDB) GetAll(bucket []byte, sample interfaces.BinaryMarshallableAndCopyable) ([]interfaces.BinaryMarshallableAndCopyable, [][]byte, error) {
 	db.dbLock.RLock()
 	defer db.dbLock.RUnlock()
@@ -258,6 +299,125 @@ func (db *LevelDB) GetAll(bucket []byte, sample interfaces.BinaryMarshallableAnd
 	return answer, keys, nil
 }
 
+func (db *LevelDB) GetRange(bucket, start []byte, reverse bool, limit int, sample interfaces.BinaryMarshallableAndCopyable) ([]interfaces.BinaryMarshallableAndCopyable, [][]byte, error) {
+	db.dbLock.RLock()
+	defer db.dbLock.RUnlock()
+
+	ldbKey := ExtendBucket(bucket)
+	iter := db.lDB.NewIterator(&util.Range{Start: ldbKey, Limit: addOneToByteArray(ldbKey)}, db.ro)
+	defer iter.Release()
+
+	var ok bool
+	switch {
+	case start == nil && reverse:
+		ok = iter.Last()
+	case start == nil:
+		ok = iter.First()
+	case reverse:
+		target := CombineBucketAndKey(bucket, start)
+		ok = iter.Seek(target)
+		if !ok {
+			ok = iter.Last()
+		} else if !bytes.Equal(iter.Key(), target) {
+			ok = iter.Prev()
+		}
+	default:
+		ok = iter.Seek(CombineBucketAndKey(bucket, start))
+	}
+
+	answer := []interfaces.BinaryMarshallableAndCopyable{}
+	keys := [][]byte{}
+	for ok && (limit == 0 || len(answer) < limit) {
+		v := iter.Value()
+		vCopy := make([]byte, len(v))
+		copy(vCopy, v)
+		tmp := sample.New()
+		err := tmp.UnmarshalBinary(vCopy)
+		if err != nil {
+			return nil, nil, err
+		}
+		k := make([]byte, len(iter.Key())-len(ldbKey))
+		copy(k, iter.Key()[len(ldbKey):])
+		keys = append(keys, k)
+		answer = append(answer, tmp)
+
+		if reverse {
+			ok = iter.Prev()
+		} else {
+			ok = iter.Next()
+		}
+	}
+	err := iter.Error()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return answer, keys, nil
+}
+
+// GetView opens a leveldb snapshot of the database.  Bucket names can contain ';', so
+// records are split where split says, or else at the first ';', which always names the
+// same record in this database.
+func (db *LevelDB) GetView(split interfaces.BucketSplitter) (interfaces.IDatabaseView, error) {
+	db.dbLock.RLock()
+	defer db.dbLock.RUnlock()
+
+	snap, err := db.lDB.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &levelDBView{snap: snap, ro: db.ro, split: split}, nil
+}
+
+type levelDBView struct {
+	snap  *leveldb.Snapshot
+	ro    *opt.ReadOptions
+	split interfaces.BucketSplitter
+}
+
+func (v *levelDBView) Get(bucket []byte, key []byte, destination interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
+	data, err := v.snap.Get(CombineBucketAndKey(bucket, key), v.ro)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	_, err = destination.UnmarshalBinaryData(data)
+	if err != nil {
+		return nil, err
+	}
+	return destination, nil
+}
+
+func (v *levelDBView) ForEach(f func(bucket, key, value []byte) error) error {
+	iter := v.snap.NewIterator(nil, v.ro)
+	defer iter.Release()
+
+	for iter.Next() {
+		k := iter.Key()
+		i := -1
+		if v.split != nil {
+			i = v.split(k, 1)
+		}
+		if i < 0 || i >= len(k) || k[i] != ';' {
+			i = bytes.IndexByte(k, ';')
+		}
+		if i < 0 {
+			continue
+		}
+		err := f(k[:i], k[i+1:], iter.Value())
+		if err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+func (v *levelDBView) Release() {
+	v.snap.Release()
+}
+
 func NewLevelDB(filename string, create bool) (interfaces.IDatabase, error) {
 	db := new(LevelDB)
 	var err error
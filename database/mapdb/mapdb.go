@@ -5,6 +5,7 @@
 package mapdb
 
 import (
+	"bytes"
 	"sort"
 	"sync"
 
@@ -18,6 +19,8 @@ type MapDB struct {
 }
 
 var _ interfaces.IDatabase = (*MapDB)(nil)
+var _ interfaces.IRangeDatabase = (*MapDB)(nil)
+var _ interfaces.IViewDatabase = (*MapDB)(nil)
 
 func (MapDB) Close() error {
 	return nil
@@ -228,6 +231,103 @@ func (db *MapDB) GetAll(bucket []byte, sample interfaces.BinaryMarshallableAndCo
 	return answer, keys, nil
 }
 
+func (db *MapDB) GetRange(bucket, start []byte, reverse bool, limit int, sample interfaces.BinaryMarshallableAndCopyable) ([]interfaces.BinaryMarshallableAndCopyable, [][]byte, error) {
+	keys, err := db.ListAllKeys(bucket)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	db.Sem.RLock()
+	defer db.Sem.RUnlock()
+
+	// The keys are sorted, so the range starts at the first key >= start, or going in
+	// reverse, just before the first key > start
+	i, step := 0, 1
+	if reverse {
+		i, step = len(keys)-1, -1
+	}
+	if start != nil {
+		i = sort.Search(len(keys), func(j int) bool {
+			if reverse {
+				return bytes.Compare(keys[j], start) > 0
+			}
+			return bytes.Compare(keys[j], start) >= 0
+		})
+		if reverse {
+			i--
+		}
+	}
+
+	answer := []interfaces.BinaryMarshallableAndCopyable{}
+	rangeKeys := [][]byte{}
+	for ; i >= 0 && i < len(keys) && (limit == 0 || len(answer) < limit); i += step {
+		v, ok := db.Cache[string(bucket)][string(keys[i])]
+		if !ok {
+			continue // Deleted since the keys were listed
+		}
+		tmp := sample.New()
+		err := tmp.UnmarshalBinary(v)
+		if err != nil {
+			return nil, nil, err
+		}
+		rangeKeys = append(rangeKeys, keys[i])
+		answer = append(answer, tmp)
+	}
+	return answer, rangeKeys, nil
+}
+
+// GetView copies the maps of the database.  The data itself is never changed in
+// place, so it is shared.
+func (db *MapDB) GetView(split interfaces.BucketSplitter) (interfaces.IDatabaseView, error) {
+	db.Sem.RLock()
+	defer db.Sem.RUnlock()
+
+	v := new(mapDBView)
+	v.cache = make(map[string]map[string][]byte, len(db.Cache))
+	for bucket, m := range db.Cache {
+		c := make(map[string][]byte, len(m))
+		for k, data := range m {
+			c[k] = data
+		}
+		v.cache[bucket] = c
+	}
+	return v, nil
+}
+
+type mapDBView struct {
+	cache map[string]map[string][]byte
+}
+
+func (v *mapDBView) Get(bucket []byte, key []byte, destination interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
+	data := v.cache[string(bucket)][string(key)]
+	if data == nil {
+		return nil, nil
+	}
+	_, err := destination.UnmarshalBinaryData(data)
+	if err != nil {
+		return nil, err
+	}
+	return destination, nil
+}
+
+func (v *mapDBView) ForEach(f func(bucket, key, value []byte) error) error {
+	for bucket, m := range v.cache {
+		for k, data := range m {
+			if data == nil {
+				continue
+			}
+			err := f([]byte(bucket), []byte(k), data)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v *mapDBView) Release() {
+}
+
 func (db *MapDB) Clear(bucket []byte) error {
 	db.Sem.Lock()
 	defer db.Sem.Unlock()
@@ -253,3 +253,52 @@ func TestGetAll(t *testing.T) {
 		}
 	}
 }
+
+func TestGetRange(t *testing.T) {
+	m := new(MapDB)
+
+	bucket := []byte("bucket")
+	for _, k := range []string{"b", "d", "f"} {
+		err := m.Put(bucket, []byte(k), &TestData{Str: k})
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	tests := []struct {
+		start   string
+		reverse bool
+		limit   int
+		expect  string
+	}{
+		{"", false, 0, "bdf"},
+		{"", true, 0, "fdb"},
+		{"c", false, 0, "df"},
+		{"d", false, 1, "d"},
+		{"d", true, 0, "db"},
+		{"e", true, 0, "db"},
+		{"g", true, 2, "fd"},
+		{"a", true, 0, ""},
+		{"g", false, 0, ""},
+	}
+	for _, test := range tests {
+		var start []byte
+		if test.start != "" {
+			start = []byte(test.start)
+		}
+		values, keys, err := m.GetRange(bucket, start, test.reverse, test.limit, new(TestData))
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		got := ""
+		for i := range values {
+			if values[i].(*TestData).Str != string(keys[i]) {
+				t.Errorf("Key %s has the value %s", keys[i], values[i].(*TestData).Str)
+			}
+			got += string(keys[i])
+		}
+		if got != test.expect {
+			t.Errorf("GetRange(%q, %v, %d) returned %q, expected %q", test.start, test.reverse, test.limit, got, test.expect)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package securedb
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// GetPassword reads a database password from its source:
+//
+//	env:NAME	the environment variable NAME
+//	file:PATH	the first line of the file PATH
+//	prompt		asked on the terminal, with the label
+func GetPassword(source string, label string) (string, error) {
+	var password string
+	switch {
+	case strings.HasPrefix(source, "env:"):
+		name := strings.TrimPrefix(source, "env:")
+		password = os.Getenv(name)
+		if password == "" {
+			return "", fmt.Errorf("The environment variable %s is not set", name)
+		}
+	case strings.HasPrefix(source, "file:"):
+		f, err := os.Open(strings.TrimPrefix(source, "file:"))
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		r := bufio.NewReader(f)
+		line, err := r.ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("Error reading the password file: %v", err)
+		}
+		password = strings.TrimRight(line, "\r\n")
+	case source == "prompt":
+		fmt.Fprintf(os.Stderr, "%s: ", label)
+		b, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		password = string(b)
+	default:
+		return "", fmt.Errorf("Invalid password source %q, expected env:NAME, file:PATH or prompt", source)
+	}
+
+	if password == "" {
+		return "", fmt.Errorf("The database password is empty")
+	}
+	return password, nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package securedb
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+var (
+	// keyRotation holds the metadata of the new key while a rotation is in progress
+	keyRotation = []byte("KeyRotation")
+
+	// RotationBatchSize is the number of records re-encrypted in one batch
+	RotationBatchSize = 1000
+)
+
+// recordLister is implemented by the databases that can not list their buckets, to go
+// through all their records
+type recordLister interface {
+	ListRecordKeys(start []byte, max int) (buckets [][]byte, keys [][]byte, next []byte, err error)
+}
+
+// forEachRecordPage calls f with the buckets and keys of all the records of the
+// database, a page at a time
+func forEachRecordPage(dbase interfaces.IDatabase, f func(buckets, keys [][]byte) error) error {
+	if l, ok := dbase.(recordLister); ok {
+		var start []byte
+		for {
+			buckets, keys, next, err := l.ListRecordKeys(start, RotationBatchSize)
+			if err != nil {
+				return err
+			}
+			err = f(buckets, keys)
+			if err != nil {
+				return err
+			}
+			if next == nil {
+				return nil
+			}
+			start = next
+		}
+	}
+
+	buckets, err := dbase.ListAllBuckets()
+	if err != nil {
+		return err
+	}
+	for _, bucket := range buckets {
+		keys, err := dbase.ListAllKeys(bucket)
+		if err != nil {
+			return err
+		}
+		for len(keys) > 0 {
+			n := RotationBatchSize
+			if n > len(keys) {
+				n = len(keys)
+			}
+			page := make([][]byte, n)
+			for i := range page {
+				page[i] = bucket
+			}
+			err = f(page, keys[:n])
+			if err != nil {
+				return err
+			}
+			keys = keys[n:]
+		}
+	}
+	return nil
+}
+
+// RotateKey re-encrypts every record of an encrypted database with the key of a new
+// password and a new salt, and returns the number of records it re-encrypted.  The
+// database must not be in use.
+//
+// The new key is saved in the metadata before any record is touched, and records
+// already encrypted with it are skipped, so an interrupted rotation is finished by
+// running it again with the same passwords.  Until then the database can not be opened.
+func RotateKey(dbase interfaces.IDatabase, oldPassword, newPassword string) (uint64, error) {
+	m := new(SecureDBMetaData)
+	v, err := dbase.Get(EncyptedMetaData, EncyptedMetaData, m)
+	if err != nil {
+		return 0, err
+	}
+	if v == nil {
+		return 0, fmt.Errorf("The database is not encrypted")
+	}
+	oldKey, err := checkChallenge(m, oldPassword)
+	if err != nil {
+		return 0, err
+	}
+
+	next := new(SecureDBMetaData)
+	v, err = dbase.Get(EncyptedMetaData, keyRotation, next)
+	if err != nil {
+		return 0, err
+	}
+	var newKey []byte
+	if v == nil {
+		next = newMetaData()
+		newKey, err = setChallenge(next, newPassword)
+		if err != nil {
+			return 0, err
+		}
+		err = dbase.Put(EncyptedMetaData, keyRotation, next)
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		newKey, err = checkChallenge(next, newPassword)
+		if err != nil {
+			return 0, fmt.Errorf("A key rotation to another password is in progress: %v", err)
+		}
+	}
+
+	var count uint64
+	err = forEachRecordPage(dbase, func(buckets, keys [][]byte) error {
+		records := []interfaces.Record{}
+		for i, key := range keys {
+			if bytes.Equal(buckets[i], EncyptedMetaData) {
+				continue
+			}
+			_, err := dbase.Get(buckets[i], key, NewEncryptedMarshaler(newKey, new(primitives.ByteSlice)))
+			if err == nil {
+				// Already rotated, or deleted since the keys were listed
+				continue
+			}
+			plain := new(primitives.ByteSlice)
+			v, err := dbase.Get(buckets[i], key, NewEncryptedMarshaler(oldKey, plain))
+			if err != nil {
+				return fmt.Errorf("Error decrypting %x in bucket %x: %v", key, buckets[i], err)
+			}
+			if v == nil {
+				continue
+			}
+			records = append(records, interfaces.Record{buckets[i], key, NewEncryptedMarshaler(newKey, plain)})
+		}
+		if len(records) == 0 {
+			return nil
+		}
+		count += uint64(len(records))
+		return dbase.PutInBatch(records)
+	})
+	if err != nil {
+		return count, err
+	}
+
+	err = dbase.Put(EncyptedMetaData, EncyptedMetaData, next)
+	if err != nil {
+		return count, err
+	}
+	return count, dbase.Delete(EncyptedMetaData, keyRotation)
+}
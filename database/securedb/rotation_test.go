@@ -0,0 +1,130 @@
+package securedb_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/leveldb"
+	"github.com/FactomProject/factomd/database/mapdb"
+	. "github.com/FactomProject/factomd/database/securedb"
+)
+
+func testRotateKey(t *testing.T, dbase interfaces.IDatabase) {
+	edb, err := NewEncryptedDBFromDB(dbase, "old password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The last bucket can not be told apart from a key in LevelDB
+	buckets := [][]byte{[]byte("One"), []byte("Two"), []byte("One;Two")}
+	for _, bucket := range buckets {
+		for i := 0; i < 25; i++ {
+			err = edb.Put(bucket, []byte(fmt.Sprintf("key %d", i)), &primitives.ByteSlice{Bytes: []byte(fmt.Sprintf("%s %d", bucket, i))})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	RotationBatchSize = 10
+	defer func() { RotationBatchSize = 1000 }()
+
+	// A record that can not be decrypted stops the rotation halfway
+	err = dbase.Put([]byte("Two"), []byte("key 12"), &primitives.ByteSlice{Bytes: []byte("not encrypted")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = RotateKey(dbase, "wrong password", "new password"); err == nil {
+		t.Error("Rotated the key with the wrong password")
+	}
+	if _, err = RotateKey(dbase, "old password", "new password"); err == nil {
+		t.Fatal("Rotated the key of a database with a record that is not encrypted")
+	}
+	if _, err = NewEncryptedDBFromDB(dbase, "old password"); err == nil {
+		t.Error("Opened a database in the middle of a key rotation")
+	}
+	if _, err = RotateKey(dbase, "old password", "other password"); err == nil {
+		t.Error("Finished a key rotation with another password")
+	}
+
+	err = edb.Put([]byte("Two"), []byte("key 12"), &primitives.ByteSlice{Bytes: []byte("Two 12")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err := RotateKey(dbase, "old password", "new password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count == 0 || count > uint64(25*len(buckets)) {
+		t.Errorf("Encrypted %d records again", count)
+	}
+
+	if _, err = NewEncryptedDBFromDB(dbase, "old password"); err == nil {
+		t.Error("Opened the database with the old password")
+	}
+	edb, err = NewEncryptedDBFromDB(dbase, "new password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, bucket := range buckets {
+		for i := 0; i < 25; i++ {
+			v, err := edb.Get(bucket, []byte(fmt.Sprintf("key %d", i)), new(primitives.ByteSlice))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v == nil || string(v.(*primitives.ByteSlice).Bytes) != fmt.Sprintf("%s %d", bucket, i) {
+				t.Errorf("Wrong value %v for %s %d", v, bucket, i)
+			}
+		}
+	}
+}
+
+func TestRotateKeyMap(t *testing.T) {
+	testRotateKey(t, new(mapdb.MapDB))
+}
+
+func TestRotateKeyLevel(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotatekey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	dbase, err := leveldb.NewLevelDB(dir+"/test.db", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbase.Close()
+	testRotateKey(t, dbase)
+}
+
+func TestGetPassword(t *testing.T) {
+	os.Setenv("SECUREDB_TEST_PASSWORD", "from env")
+	defer os.Unsetenv("SECUREDB_TEST_PASSWORD")
+	p, err := GetPassword("env:SECUREDB_TEST_PASSWORD", "")
+	if err != nil || p != "from env" {
+		t.Errorf("Got %q %v", p, err)
+	}
+	if _, err = GetPassword("env:SECUREDB_TEST_NOT_SET", ""); err == nil {
+		t.Error("Expected an error for an unset variable")
+	}
+
+	f, err := ioutil.TempFile("", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("from file\nsecond line\n")
+	f.Close()
+	p, err = GetPassword("file:"+f.Name(), "")
+	if err != nil || p != "from file" {
+		t.Errorf("Got %q %v", p, err)
+	}
+
+	if _, err = GetPassword("password", ""); err == nil {
+		t.Error("Expected an error for an invalid source")
+	}
+}
@@ -5,7 +5,10 @@
 package securedb
 
 import (
+	"bytes"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/subtle"
 	"fmt"
 
@@ -23,6 +26,10 @@ var (
 	challenge = []byte("Challenge")
 )
 
+var _ interfaces.IDatabase = (*EncryptedDB)(nil)
+var _ interfaces.IRangeDatabase = (*EncryptedDB)(nil)
+var _ interfaces.IViewDatabase = (*EncryptedDB)(nil)
+
 // EncryptedDB is a database with symmetric encryption to encrypt all writes, and decrypt all reads
 type EncryptedDB struct {
 	// Stores all encrypted data
@@ -52,8 +59,29 @@ func NewEncryptedDB(filename, dbtype, password string) (*EncryptedDB, error) {
 	return e, nil
 }
 
+// NewEncryptedDBFromDB encrypts an already opened database with the password
+func NewEncryptedDBFromDB(dbase interfaces.IDatabase, password string) (*EncryptedDB, error) {
+	e := new(EncryptedDB)
+	e.db = dbase
+
+	err := e.initSecureDB(password)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
 // InitSecureDB will init the Salt and metadata
 func (db *EncryptedDB) initSecureDB(password string) error {
+	inRotation, err := db.db.DoesKeyExist(EncyptedMetaData, keyRotation)
+	if err != nil {
+		return err
+	}
+	if inRotation {
+		return fmt.Errorf("A key rotation of this database was interrupted, it has to be run again to finish it")
+	}
+
 	m := new(SecureDBMetaData)
 	v, err := db.db.Get(EncyptedMetaData, EncyptedMetaData, m)
 	if err != nil {
@@ -62,28 +90,17 @@ func (db *EncryptedDB) initSecureDB(password string) error {
 
 	if v == nil {
 		// need to init new metadata
-		db.initNewMetaData()
+		db.metadata = newMetaData()
 	} else {
 		db.metadata = m
 	}
 
-	key, err := GetKey(password, db.metadata.Salt.Bytes)
-	if err != nil {
-		return err
-	}
-
-	db.encryptionkey = key
-
 	if len(db.metadata.Challenge.Bytes) == 0 {
 		// Create challenge
-		cipherText, err := Encrypt(challenge, db.encryptionkey)
+		db.encryptionkey, err = setChallenge(db.metadata, password)
 		if err != nil {
 			return err
 		}
-
-		var c primitives.ByteSlice
-		c.Bytes = cipherText
-		db.metadata.Challenge = c
 		err = db.db.Put(EncyptedMetaData, EncyptedMetaData, db.metadata)
 		if err != nil {
 			return err
@@ -91,28 +108,56 @@ func (db *EncryptedDB) initSecureDB(password string) error {
 
 	} else {
 		// Do challenge
-		plainText, err := Decrypt(db.metadata.Challenge.Bytes, db.encryptionkey)
+		db.encryptionkey, err = checkChallenge(db.metadata, password)
 		if err != nil {
 			return err
 		}
-
-		if subtle.ConstantTimeCompare(plainText, challenge) == 0 {
-			return fmt.Errorf("Wrong password given for this database")
-		}
 	}
 
 	return nil
 }
 
-func (db *EncryptedDB) initNewMetaData() {
-	db.metadata = NewSecureDBMetaData()
+func newMetaData() *SecureDBMetaData {
+	m := NewSecureDBMetaData()
 	salt := make([]byte, 30)
 	_, err := rand.Read(salt)
 	if err != nil {
 		panic(err)
 	}
 
-	db.metadata.Salt.Bytes = salt
+	m.Salt.Bytes = salt
+	return m
+}
+
+// setChallenge derives the key of the password and the salt of the metadata, and
+// sets the challenge that checks it
+func setChallenge(m *SecureDBMetaData, password string) ([]byte, error) {
+	key, err := GetKey(password, m.Salt.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	cipherText, err := Encrypt(challenge, key)
+	if err != nil {
+		return nil, err
+	}
+	m.Challenge.Bytes = cipherText
+	return key, nil
+}
+
+// checkChallenge derives the key of the password and the salt of the metadata, and
+// returns it if it solves the challenge
+func checkChallenge(m *SecureDBMetaData, password string) ([]byte, error) {
+	key, err := GetKey(password, m.Salt.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	plainText, err := Decrypt(m.Challenge.Bytes, key)
+	if err != nil || subtle.ConstantTimeCompare(plainText, challenge) == 0 {
+		return nil, fmt.Errorf("Wrong password given for this database")
+	}
+	return key, nil
 }
 
 /***************************************
@@ -197,6 +242,79 @@ func (db *EncryptedDB) GetAll(bucket []byte, sample interfaces.BinaryMarshallabl
 	return originalSamples, keys, err
 }
 
+// GetRange reads part of a bucket.  Only the values are encrypted, so the keys keep
+// their order.
+func (db *EncryptedDB) GetRange(bucket, start []byte, reverse bool, limit int, sample interfaces.BinaryMarshallableAndCopyable) ([]interfaces.BinaryMarshallableAndCopyable, [][]byte, error) {
+	r, ok := db.db.(interfaces.IRangeDatabase)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T can not read ranges", db.db)
+	}
+	s := NewEncryptedMarshaler(db.encryptionkey, sample.(interfaces.BinaryMarshallable))
+
+	ciphered, keys, err := r.GetRange(bucket, start, reverse, limit, s)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	originalSamples := make([]interfaces.BinaryMarshallableAndCopyable, len(ciphered))
+	for i, e := range ciphered {
+		originalSamples[i] = e.(*EncryptedMarshaler).Original.(interfaces.BinaryMarshallableAndCopyable)
+	}
+
+	return originalSamples, keys, nil
+}
+
+// GetView opens a view of the underlying database that decrypts the values it reads.
+// The metadata bucket is left out, it belongs to this database and its password.
+func (db *EncryptedDB) GetView(split interfaces.BucketSplitter) (interfaces.IDatabaseView, error) {
+	v, ok := db.db.(interfaces.IViewDatabase)
+	if !ok {
+		return nil, fmt.Errorf("%T can not open views", db.db)
+	}
+	view, err := v.GetView(split)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedDBView{view: view, encryptionkey: db.encryptionkey}, nil
+}
+
+type encryptedDBView struct {
+	view          interfaces.IDatabaseView
+	encryptionkey []byte
+}
+
+func (v *encryptedDBView) Get(bucket []byte, key []byte, destination interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
+	e := NewEncryptedMarshaler(v.encryptionkey, destination)
+	tmp, err := v.view.Get(bucket, key, e)
+	if err != nil {
+		return nil, err
+	}
+
+	if tmp == nil {
+		return nil, nil
+	}
+
+	return e.Original, nil
+}
+
+func (v *encryptedDBView) ForEach(f func(bucket, key, value []byte) error) error {
+	return v.view.ForEach(func(bucket, key, value []byte) error {
+		if bytes.Equal(bucket, EncyptedMetaData) {
+			return nil
+		}
+		e := NewEncryptedMarshaler(v.encryptionkey, new(primitives.ByteSlice))
+		err := e.UnmarshalBinary(value)
+		if err != nil {
+			return err
+		}
+		return f(bucket, key, e.Original.(*primitives.ByteSlice).Bytes)
+	})
+}
+
+func (v *encryptedDBView) Release() {
+	v.view.Release()
+}
+
 func (db *EncryptedDB) Init(filename string, dbtype string) {
 	var err error
 	switch dbtype {
@@ -214,6 +332,24 @@ func (db *EncryptedDB) Init(filename string, dbtype string) {
 	}
 }
 
+// Encrypt encrypts data with the key of the database, for data kept next to it
+func (db *EncryptedDB) Encrypt(data []byte) ([]byte, error) {
+	return Encrypt(data, db.encryptionkey)
+}
+
+// Decrypt decrypts data encrypted with Encrypt
+func (db *EncryptedDB) Decrypt(data []byte) ([]byte, error) {
+	return Decrypt(data, db.encryptionkey)
+}
+
+// HashKey returns a hash of a key made with the key of the database, for keys that
+// must not be stored in the clear.  Only the values of this database are encrypted.
+func (db *EncryptedDB) HashKey(key []byte) []byte {
+	mac := hmac.New(sha256.New, db.encryptionkey)
+	mac.Write(key)
+	return mac.Sum(nil)
+}
+
 func (db *EncryptedDB) DoesKeyExist(bucket, key []byte) (bool, error) {
 	return db.db.DoesKeyExist(bucket, key)
 }
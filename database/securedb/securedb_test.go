@@ -1,6 +1,7 @@
 package securedb_test
 
 import (
+	"bytes"
 	"os"
 	"testing"
 
@@ -25,3 +26,36 @@ func TestSecureDB(t *testing.T) {
 
 	os.Remove("test.db")
 }
+
+func TestSecureDBCipher(t *testing.T) {
+	s, err := NewEncryptedDB("", "Map", "rightPassword")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	data := []byte("FastBoot")
+	cipherText, err := s.Encrypt(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(cipherText, data) {
+		t.Error("The data is not encrypted")
+	}
+	plainText, err := s.Decrypt(cipherText)
+	if err != nil || !bytes.Equal(plainText, data) {
+		t.Errorf("Decrypted %q (%v), expected %q", plainText, err, data)
+	}
+
+	other, err := NewEncryptedDB("", "Map", "rightPassword")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	if _, err = other.Decrypt(cipherText); err == nil {
+		t.Error("Decrypted with the key of another database")
+	}
+	if bytes.Equal(s.HashKey(data), other.HashKey(data)) || !bytes.Equal(s.HashKey(data), s.HashKey(data)) {
+		t.Error("Keys are not hashed with the key of the database")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/FactomProject/factomd/common/eventLog"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
@@ -461,6 +462,7 @@ func Run(s *state.State) {
 			continue
 		}
 		msg.ElectionProcess(s, e)
+		s.LogMsgEvent(eventLog.Election, uint32(e.DBHeight), e.Minute, e.Electing, -1, msg.(interfaces.IMsg), "")
 
 		//if msg.(interfaces.IMsg).Type() != constants.INTERNALEOMSIG { // If it's not an EOM check the authority set
 		//	CheckAuthSetsMatch("election.Run", e, s)
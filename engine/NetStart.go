@@ -21,7 +21,9 @@ import (
 	"github.com/FactomProject/factomd/common/messages/electionMsgs"
 	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/FactomProject/factomd/controlPanel"
+	"github.com/FactomProject/factomd/database/badgerdb"
 	"github.com/FactomProject/factomd/database/leveldb"
+	"github.com/FactomProject/factomd/grpcapi"
 	"github.com/FactomProject/factomd/p2p"
 	"github.com/FactomProject/factomd/state"
 	"github.com/FactomProject/factomd/util"
@@ -136,6 +138,11 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 
 	s.FaultTimeout = 9999999 //todo: Old Fault Mechanism -- remove
 
+	if p.ReplayJournal != "" {
+		// A deterministic replay only follows the journal, and talks to nobody
+		p.Follower = true
+		p.EnableNet = false
+	}
 	if p.Follower {
 		p.Leader = false
 	}
@@ -146,7 +153,7 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 		panic("Not a leader or a follower")
 	}
 
-	if p.Journal != "" {
+	if p.Journal != "" || p.ReplayJournal != "" {
 		p.Cnt = 1
 	}
 
@@ -178,6 +185,7 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 
 	s.CheckChainHeads.CheckChainHeads = p.CheckChainHeads
 	s.CheckChainHeads.Fix = p.FixChainHeads
+	s.RestoreSnapshot = p.RestoreSnapshot
 
 	fmt.Println(">>>>>>>>>>>>>>>>")
 	fmt.Println(">>>>>>>>>>>>>>>> Net Sim Start!")
@@ -200,18 +208,23 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 		os.Exit(0)
 	})
 
-	if p.Journal != "" {
+	if p.Journal != "" || p.ReplayJournal != "" {
 		if s.DBType != "Map" {
 			fmt.Println("Journal is ALWAYS a Map database")
 			s.DBType = "Map"
 		}
 	}
 	if p.Follower {
-		s.NodeMode = "FULL"
+		if s.NodeMode != "PRUNED" {
+			s.NodeMode = "FULL"
+		}
 		leadID := primitives.Sha([]byte(s.Prefix + "FNode0"))
 		if s.IdentityChainID.IsSameAs(leadID) {
 			s.SetIdentityChainID(primitives.Sha([]byte(time.Now().String()))) // Make sure this node is NOT a leader
 		}
+		if p.ReplayJournal != "" {
+			s.SetIdentityChainID(primitives.Sha([]byte(s.Prefix + "Replay"))) // The same identity, never an authority, for every replay
+		}
 	}
 
 	s.KeepMismatch = p.KeepMismatch
@@ -270,6 +283,7 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 	os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "net spec", pnet))
 	os.Stderr.WriteString(fmt.Sprintf("%20s %d\n", "Msgs droped", p.DropRate))
 	os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "journal", p.Journal))
+	os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "replay", p.ReplayJournal))
 	os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "database", p.Db))
 	os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "database for clones", p.CloneDB))
 	os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "peers", p.Peers))
@@ -373,6 +387,15 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 			networkPort = fmt.Sprintf("%d", p.NetworkPortOverride)
 		}
 
+		var nodeKey *primitives.PrivateKey
+		if s.P2PEncryption {
+			var err error
+			nodeKey, err = p2p.LoadNodeKey(s.P2PKeyFile)
+			if err != nil {
+				panic(fmt.Sprintf("Cannot load the p2p node key from %s: %v", s.P2PKeyFile, err))
+			}
+		}
+
 		ci := p2p.ControllerInit{
 			NodeName:                 nodeName,
 			Port:                     networkPort,
@@ -384,6 +407,8 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 			ConfigPeers:              configPeers,
 			CmdLinePeers:             p.Peers,
 			ConnectionMetricsChannel: connectionMetricsChannel,
+			Encrypt:                  s.P2PEncryption,
+			NodeKey:                  nodeKey,
 		}
 		p2pNetwork = new(p2p.Controller).Init(ci)
 		fnodes[0].State.NetworkController = p2pNetwork
@@ -529,15 +554,20 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 		fnodes[0].State.SetUseTorrent(false)
 	}
 
-	if p.Journal != "" {
+	if p.ReplayJournal != "" {
+		go RunReplay(s, p.ReplayJournal, p.ReplayExpected)
+	} else if p.Journal != "" {
 		go LoadJournal(s, p.Journal)
 		startServers(false)
 	} else {
 		startServers(true)
 	}
 
-	// Start the webserver
-	wsapi.Start(fnodes[0].State)
+	// Start the webserver.  A replay serves no API, nothing may change the State under it
+	if p.ReplayJournal == "" {
+		wsapi.Start(fnodes[0].State)
+		grpcapi.Start(fnodes[0].State)
+	}
 
 	// Start prometheus on port
 	launchPrometheus(9876)
@@ -545,6 +575,7 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 	state.RegisterPrometheus()
 	p2p.RegisterPrometheus()
 	leveldb.RegisterPrometheus()
+	badgerdb.RegisterPrometheus()
 	RegisterPrometheus()
 
 	go controlPanel.ServeControlPanel(fnodes[0].State.ControlPanelChannel, fnodes[0].State, connectionMetricsChannel, p2pNetwork, Build)
@@ -588,6 +619,9 @@ func startServers(load bool) {
 			go state.LoadDatabase(fnode.State)
 		}
 		go fnode.State.GoSyncEntries()
+		if fnode.State.NodeMode == "PRUNED" {
+			go fnode.State.GoPruneEntries()
+		}
 		go Timer(fnode.State)
 		go fnode.State.ValidatorLoop()
 		go elections.Run(fnode.State)
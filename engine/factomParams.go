@@ -35,7 +35,7 @@ func ParseCmdLine(args []string) *FactomParams {
 	journalingPtr := flag.Bool("journaling", false, "Write a journal of all messages received. Default is off.")
 	followerPtr := flag.Bool("follower", false, "If true, force node to be a follower.  Only used when replaying a journal.")
 	leaderPtr := flag.Bool("leader", true, "If true, force node to be a leader.  Only used when replaying a journal.")
-	dbPtr := flag.String("db", "", "Override the Database in the Config file and use this Database implementation. Options Map, LDB, or Bolt")
+	dbPtr := flag.String("db", "", "Override the Database in the Config file and use this Database implementation. Options Map, LDB, Bolt, Badger, EncryptedLDB, or EncryptedBolt")
 	cloneDBPtr := flag.String("clonedb", "", "Override the main node and use this database for the clones in a Network.")
 	networkNamePtr := flag.String("network", "", "Network to join: MAIN, TEST or LOCAL")
 	peersPtr := flag.String("peers", "", "Array of peer addresses. ")
@@ -96,6 +96,10 @@ func ParseCmdLine(args []string) *FactomParams {
 	flag.BoolVar(&p.FixChainHeads, "fixheads", true, "If --checkheads is enabled, then this will also correct any errors reported")
 	flag.StringVar(&p.ControlPanelSetting, "controlpanelsetting", "", "Can set to 'disabled', 'readonly', or 'readwrite' to overwrite config file")
 	flag.BoolVar(&p.WriteProcessedDBStates, "wrproc", true, "Write processed blocks to temporary debug file")
+	flag.StringVar(&p.EventLogPath, "eventlog", "", "Write the consensus events of every node to <name>.events in this directory")
+	flag.StringVar(&p.ReplayJournal, "replay", "", "Replay a journal deterministically as a follower, write the blocks built to <journal>.blocks, and exit")
+	flag.StringVar(&p.ReplayExpected, "replaycheck", "", "Stop the replay at the first block that differs from this list of heights, key MRs and balance hashes")
+	flag.StringVar(&p.RestoreSnapshot, "restore", "", "Restore the database from a snapshot file before booting.  The database must be empty")
 
 	flag.CommandLine.Parse(args)
 
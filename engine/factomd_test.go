@@ -1,6 +1,7 @@
 package engine_test
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
@@ -21,6 +22,7 @@ import (
 	"github.com/FactomProject/factomd/common/primitives/random"
 	. "github.com/FactomProject/factomd/engine"
 	"github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
 	"github.com/FactomProject/factomd/wsapi"
 )
 
@@ -1397,6 +1399,56 @@ func TestTestNetCoinBaseActivation(t *testing.T) {
 	}
 }
 
+func TestReplayJournal(t *testing.T) {
+	if ranSimTest {
+		return
+	}
+	ranSimTest = true
+
+	state0 := SetupSim("LF", "LOCAL", map[string]string{"--journaling": "true"}, t)
+	WaitBlocks(state0, 3)
+	WaitForAllNodes(state0)
+
+	t.Log("Shutting down the network")
+	for _, fn := range GetFnodes() {
+		fn.State.ShutdownChan <- 1
+	}
+	time.Sleep(time.Duration(state0.DirectoryBlockInSeconds) * time.Second)
+
+	// The replay of the journal of the follower has to build the blocks the follower saved
+	follower := GetFnodes()[1].State
+	saved := follower.GetHighestSavedBlk()
+	expected := []*state.ReplayCheckpoint{}
+	for ht := uint32(1); ht <= saved; ht++ {
+		d, err := follower.DB.FetchDBlockByHeight(ht)
+		if err != nil || d == nil {
+			t.Fatalf("The follower has no block at height %d", ht)
+		}
+		expected = append(expected, &state.ReplayCheckpoint{DBHeight: ht, KeyMR: d.GetKeyMR()})
+	}
+
+	s := testHelper.CreateEmptyTestState()
+	s.DirectoryBlockInSeconds = state0.DirectoryBlockInSeconds
+	s.SetIdentityChainID(primitives.Sha([]byte("Replay")))
+	s.ReplayCheck = state.NewReplayCheck(expected)
+
+	f, err := os.Open(follower.JournalFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := ReplayJournal(s, bufio.NewReader(f)); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.GetHighestSavedBlk() != saved {
+		t.Errorf("The replay saved height %d, the follower saved height %d", s.GetHighestSavedBlk(), saved)
+	}
+	if checked, total := s.ReplayCheck.Checked(); checked != total {
+		t.Errorf("The replay built %d of the %d blocks of the follower", checked, total)
+	}
+}
+
 // Cheap tests for developing binary search commits algorithm
 
 func TestPass(t *testing.T) {
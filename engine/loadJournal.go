@@ -6,8 +6,11 @@ package engine
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -15,6 +18,8 @@ import (
 	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages/msgsupport"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/state"
 )
 
 func LoadJournal(s interfaces.IState, journal string) {
@@ -54,32 +59,13 @@ func LoadJournalFromReader(s interfaces.IState, r *bufio.Reader) {
 			break
 		}
 
-		// Get the next word.  If not MsgHex:, then go to next line.
-		adv, word, err := bufio.ScanWords(line, true)
-		if string(word) != "MsgHex:" {
-			continue // Go to next line.
-		}
-		line = line[adv:] // Remove "MsgHex:" from the line.
-
-		// Remove spaces.
-		adv, data, err := bufio.ScanWords(line, true)
+		msg, _, err := parseJournalLine(line)
 		if err != nil {
 			fmt.Println(err)
 			return
 		}
-
-		// Decode the hex
-		binary, err := hex.DecodeString(string(data))
-		if err != nil {
-			fmt.Println(err)
-			return
-		}
-
-		// Unmarshal the message.
-		msg, err := msgsupport.UnmarshalMessage(binary)
-		if err != nil {
-			fmt.Println(err)
-			return
+		if msg == nil {
+			continue // Go to next line.
 		}
 
 		// Process the message.
@@ -99,3 +85,147 @@ func LoadJournalFromReader(s interfaces.IState, r *bufio.Reader) {
 		time.Sleep(time.Millisecond * 100)
 	}
 }
+
+// parseJournalLine returns the message of a line of a journal, and the time it was
+// processed at if the journal has it.  Journals hold one message per line, either as
+// the JSON written by State.JournalMessage, or as "MsgHex: <hex>".  The message is nil
+// for the other lines.
+func parseJournalLine(line []byte) (interfaces.IMsg, interfaces.Timestamp, error) {
+	var data string
+	var timestamp interfaces.Timestamp
+
+	line = bytes.TrimSpace(line)
+	if bytes.HasPrefix(line, []byte("{")) {
+		entry := new(struct {
+			Time   int64
+			MsgHex string
+		})
+		if err := json.Unmarshal(line, entry); err != nil {
+			return nil, nil, err
+		}
+		if entry.MsgHex == "" {
+			return nil, nil, nil // Written before the journal kept the binary messages
+		}
+		data = entry.MsgHex
+		if entry.Time > 0 {
+			timestamp = primitives.NewTimestampFromMilliseconds(uint64(entry.Time))
+		}
+	} else {
+		// Get the next word.  If not MsgHex:, then go to next line.
+		adv, word, _ := bufio.ScanWords(line, true)
+		if string(word) != "MsgHex:" {
+			return nil, nil, nil
+		}
+		line = line[adv:] // Remove "MsgHex:" from the line.
+
+		// Remove spaces.
+		_, word, err := bufio.ScanWords(line, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = string(word)
+	}
+
+	// Decode the hex
+	binary, err := hex.DecodeString(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Unmarshal the message.
+	msg, err := msgsupport.UnmarshalMessage(binary)
+	if err != nil {
+		return nil, nil, err
+	}
+	if timestamp == nil {
+		timestamp = msg.GetTimestamp()
+	}
+	return msg, timestamp, nil
+}
+
+// ReplayJournal replays a journal deterministically: the node processes one message at
+// a time, in the order of the journal, with a virtual clock set to the time the message
+// was recorded at.  The State must run as a follower, without its ValidatorLoop,
+// Timer, elections or network.  The replay stops at the first block that is not the
+// one expected by the ReplayCheck of the State, and returns the divergence.
+func ReplayJournal(s *state.State, r *bufio.Reader) error {
+	if s.ReplayCheck == nil {
+		s.ReplayCheck = state.NewReplayCheck(nil)
+	}
+	s.SetIsReplaying()
+	defer s.SetIsDoneReplaying()
+
+	for n := 1; ; n++ {
+		line, err := r.ReadBytes('\n')
+		if len(line) == 0 {
+			if err != nil && err != io.EOF {
+				return err
+			}
+			return nil
+		}
+
+		msg, timestamp, err := parseJournalLine(line)
+		if err != nil {
+			return fmt.Errorf("Journal line %d: %v", n, err)
+		}
+		if msg == nil {
+			continue
+		}
+		s.ReplayMessage(msg, timestamp)
+
+		if d := s.ReplayCheck.Divergence(); d != nil {
+			return fmt.Errorf("Journal line %d: %v", n, d)
+		}
+	}
+}
+
+// RunReplay replays a journal deterministically, stops at the first block that is not
+// in the expected list if there is one, writes the blocks the replay built next to the
+// journal, and exits.
+func RunReplay(s *state.State, journal string, expected string) {
+	list := []*state.ReplayCheckpoint{}
+	if expected != "" {
+		f, err := os.Open(expected)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Replay:", err)
+			os.Exit(1)
+		}
+		list, err = state.ReadReplayCheckpoints(f)
+		f.Close()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Replay:", expected, err)
+			os.Exit(1)
+		}
+	}
+	s.ReplayCheck = state.NewReplayCheck(list)
+
+	f, err := os.Open(journal)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Replay:", err)
+		os.Exit(1)
+	}
+	err = ReplayJournal(s, bufio.NewReaderSize(f, 4*1024))
+	f.Close()
+
+	blocks := s.ReplayCheck.Reached()
+	out, werr := os.Create(journal + ".blocks")
+	if werr == nil {
+		werr = state.WriteReplayCheckpoints(out, blocks)
+		out.Close()
+	}
+	if werr != nil {
+		fmt.Fprintln(os.Stderr, "Replay: could not write the blocks:", werr)
+	}
+
+	checked, total := s.ReplayCheck.Checked()
+	fmt.Printf("Replay built %d blocks, %d of %d expected blocks checked\n", len(blocks), checked, total)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Replay:", err)
+		os.Exit(1)
+	}
+	if checked < total {
+		fmt.Fprintln(os.Stderr, "Replay: the journal ended before the last expected block")
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
@@ -648,15 +648,9 @@ func SimControl(listenTo int, listenStdin bool) {
 					if len(b) == 1 || b[1] == 'h' {
 						f := fnodes[ListenTo]
 						fmt.Println("Holding:")
-						for k := range f.State.Holding {
-							v := f.State.Holding[k]
-							vf := v.Validate(f.State)
-							if v != nil {
-								repeat := f.State.Replay.IsHashUnique(constants.REVEAL_REPLAY, v.GetHash().Fixed())
-								os.Stderr.WriteString(fmt.Sprintf("%s v %d cnt %d notYet: %v\n", v.String(), vf, v.GetResendCnt(), repeat))
-							} else {
-								os.Stderr.WriteString("<nul>\n")
-							}
+						for _, h := range f.State.LoadHoldingList() {
+							os.Stderr.WriteString(fmt.Sprintf("%s %-20s vm %2d age %4ds cnt %d waiting for %s %s\n",
+								h.Hash[:10], h.Type, h.VM, h.Age, h.ResendCount, h.Reason, h.EntryHash))
 						}
 					} else if b[1] == 'c' {
 						f := fnodes[ListenTo]
@@ -1385,7 +1379,7 @@ func SimControl(listenTo int, listenStdin bool) {
 				os.Stderr.WriteString("ks            Print the ChainIDs, Public keys, and hash of Private Keys of all nodes\n")
 				os.Stderr.WriteString("kN.M          Show Entry Block and Chain Head.  N is the directory block, and M is the Entry in that block.\n")
 				os.Stderr.WriteString("                 So K3.6 gets the directory block at height 3, and prints the entry at index 6.\n")
-				os.Stderr.WriteString("y             Dump what is in the Holding Map.  Can crash, but oh well.\n")
+				os.Stderr.WriteString("y             Dump what is in the Holding Map, as of the last second.\n")
 				os.Stderr.WriteString("m             Show Messages as they are passed through the simulator.\n")
 				os.Stderr.WriteString("Tnnn          Set the block time to the given number of seconds.\n")
 				os.Stderr.WriteString("c             Trace the Consensus Process\n")
@@ -0,0 +1,162 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+//go:generate protoc --go_out=plugins=grpc:. factomd.proto
+
+package grpcapi
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/log"
+	"github.com/FactomProject/factomd/wsapi"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// The gRPC API shares the users, permissions and rate limits of the JSON-RPC API.  Each
+// call is checked as the v2 method it stands for, with the "authorization" metadata
+// taking the place of the Authorization header.
+var v2Methods = map[string]string{
+	"Heights":                  "heights",
+	"DirectoryBlockHead":       "directory-block-head",
+	"GetDirectoryBlock":        "directory-block",
+	"GetAdminBlock":            "admin-block",
+	"GetFactoidBlock":          "factoid-block",
+	"GetEntryCreditBlock":      "entrycredit-block",
+	"GetEntryBlock":            "entry-block",
+	"GetEntry":                 "entry",
+	"GetChainHead":             "chain-head",
+	"FactoidBalance":           "factoid-balance",
+	"EntryCreditBalance":       "entry-credit-balance",
+	"EntryCreditRate":          "entry-credit-rate",
+	"SubmitFactoidTransaction": "factoid-submit",
+	"CommitChain":              "commit-chain",
+	"CommitEntry":              "commit-entry",
+	"RevealEntry":              "reveal-entry",
+	"FactoidAck":               "factoid-ack",
+	"EntryAck":                 "entry-ack",
+	"SubscribeDirectoryBlocks": "subscribe",
+	"SubscribeEntryBlocks":     "subscribe",
+}
+
+// Start serves the gRPC API, if it is enabled.  It uses TLS if the JSON-RPC API does,
+// with the same key pair; wsapi.Start creates the pair if there is none.
+func Start(state interfaces.IState) {
+	enabled, port := state.GetGRPCInfo()
+	if !enabled {
+		return
+	}
+	RegisterPrometheus()
+
+	options := []grpc.ServerOption{
+		grpc.UnaryInterceptor(unaryInterceptor(state)),
+		grpc.StreamInterceptor(streamInterceptor(state)),
+	}
+	tlsIsEnabled, tlsPrivate, tlsPublic := state.GetTlsInfo()
+	if tlsIsEnabled {
+		creds, err := credentials.NewServerTLSFromFile(tlsPublic, tlsPrivate)
+		if err != nil {
+			panic(fmt.Sprintf("could not start encrypted gRPC server with error: %v", err))
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		panic(fmt.Sprintf("could not start gRPC server with error: %v", err))
+	}
+	server := grpc.NewServer(options...)
+	RegisterFactomdServer(server, NewServer(state))
+
+	log.Printfln("Starting gRPC server on port %d", port)
+	go server.Serve(listener)
+}
+
+// authorize checks the permission and the rate limit of a call
+func authorize(state interfaces.IState, ctx context.Context, fullMethod string) error {
+	name := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	method, ok := v2Methods[name]
+	if !ok {
+		return status.Errorf(codes.Unimplemented, "unknown method %s", fullMethod)
+	}
+
+	r := &http.Request{Header: make(http.Header)}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		r.Header["Authorization"] = md["authorization"]
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		r.RemoteAddr = p.Addr.String()
+	}
+
+	if jsonError := wsapi.CheckPermission(state, r, wsapi.APIV2, method); jsonError != nil {
+		return toStatus(jsonError)
+	}
+	if jsonError := wsapi.CheckRateLimit(state, r, method); jsonError != nil {
+		return toStatus(jsonError)
+	}
+	return nil
+}
+
+func unaryInterceptor(state interfaces.IState) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		n := time.Now()
+		defer func() {
+			GRPCCalls.WithLabelValues(info.FullMethod).Observe(float64(time.Since(n).Nanoseconds()))
+		}()
+
+		if err := authorize(state, ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamInterceptor(state interfaces.IState) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorize(state, stream.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		GRPCStreams.Inc()
+		defer GRPCStreams.Dec()
+		return handler(srv, stream)
+	}
+}
+
+// toStatus turns the errors of the JSON-RPC API into gRPC errors
+func toStatus(jsonError *primitives.JSONError) error {
+	code := codes.Internal
+	switch jsonError.Code {
+	case -32600:
+		code = codes.Unauthenticated
+	case -32601:
+		code = codes.Unimplemented
+	case -32602:
+		code = codes.InvalidArgument
+	case -32008, -32009, -32014:
+		code = codes.NotFound
+	case -32011:
+		code = codes.AlreadyExists
+	case -32016:
+		code = codes.ResourceExhausted
+	case -32017:
+		code = codes.PermissionDenied
+	}
+	message := jsonError.Message
+	if data, ok := jsonError.Data.(string); ok && data != "" {
+		message += ": " + data
+	}
+	return status.Error(code, message)
+}
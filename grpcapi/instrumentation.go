@@ -0,0 +1,31 @@
+package grpcapi
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	GRPCCalls = prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Name: "factomd_grpcapi_call_ns",
+		Help: "Time it takes to compelete a gRPC call",
+	}, []string{"method"})
+
+	GRPCStreams = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_grpcapi_streams",
+		Help: "Number of open gRPC subscription streams",
+	})
+)
+
+var registered = false
+
+// RegisterPrometheus registers the variables to be exposed. This can only be run once, hence the
+// boolean flag to prevent panics if launched more than once. This is called in Start
+func RegisterPrometheus() {
+	if registered {
+		return
+	}
+	registered = true
+
+	prometheus.MustRegister(GRPCCalls)
+	prometheus.MustRegister(GRPCStreams)
+}
@@ -0,0 +1,578 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package grpcapi
+
+import (
+	"encoding/hex"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/wsapi"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements FactomdServer with the same calls to the state as the handlers
+// of the v2 API
+type Server struct {
+	state interfaces.IState
+}
+
+var _ FactomdServer = (*Server)(nil)
+
+func NewServer(state interfaces.IState) *Server {
+	s := new(Server)
+	s.state = state
+	return s
+}
+
+func (s *Server) Heights(ctx context.Context, in *HeightsRequest) (*HeightsResponse, error) {
+	h := new(HeightsResponse)
+	h.DirectoryBlockHeight = s.state.GetHighestSavedBlk()
+	h.LeaderHeight = s.state.GetTrueLeaderHeight()
+	h.EntryBlockHeight = s.state.GetHighestSavedBlk()
+	h.EntryHeight = s.state.GetEntryDBHeightComplete()
+	return h, nil
+}
+
+func (s *Server) DirectoryBlockHead(ctx context.Context, in *DirectoryBlockHeadRequest) (*DirectoryBlock, error) {
+	block := s.state.GetDirectoryBlockByHeight(s.state.GetHighestSavedBlk())
+	if block == nil {
+		return nil, toStatus(wsapi.NewBlockNotFoundError())
+	}
+	return directoryBlock(block)
+}
+
+func (s *Server) GetDirectoryBlock(ctx context.Context, in *BlockRequest) (*DirectoryBlock, error) {
+	keymr, err := blockKeyMR(in)
+	if err != nil {
+		return nil, err
+	}
+
+	var block interfaces.IDirectoryBlock
+	if keymr != nil {
+		block, err = s.state.GetDB().FetchDBlock(keymr)
+	} else {
+		block, err = s.state.GetDB().FetchDBlockByHeight(in.Height)
+	}
+	if err != nil {
+		return nil, toStatus(wsapi.NewInternalDatabaseError())
+	}
+	if block == nil {
+		return nil, toStatus(wsapi.NewBlockNotFoundError())
+	}
+	return directoryBlock(block)
+}
+
+func (s *Server) GetAdminBlock(ctx context.Context, in *BlockRequest) (*RawBlock, error) {
+	keymr, err := blockKeyMR(in)
+	if err != nil {
+		return nil, err
+	}
+
+	var block interfaces.IAdminBlock
+	if keymr != nil {
+		block, err = s.state.GetDB().FetchABlock(keymr)
+	} else {
+		block, err = s.state.GetDB().FetchABlockByHeight(in.Height)
+	}
+	if err != nil {
+		return nil, toStatus(wsapi.NewInternalDatabaseError())
+	}
+	if block == nil {
+		return nil, toStatus(wsapi.NewBlockNotFoundError())
+	}
+	return rawBlock(block)
+}
+
+func (s *Server) GetFactoidBlock(ctx context.Context, in *BlockRequest) (*RawBlock, error) {
+	keymr, err := blockKeyMR(in)
+	if err != nil {
+		return nil, err
+	}
+
+	var block interfaces.IFBlock
+	if keymr != nil {
+		block, err = s.state.GetDB().FetchFBlock(keymr)
+	} else {
+		block, err = s.state.GetDB().FetchFBlockByHeight(in.Height)
+	}
+	if err != nil {
+		return nil, toStatus(wsapi.NewInternalDatabaseError())
+	}
+	if block == nil {
+		return nil, toStatus(wsapi.NewBlockNotFoundError())
+	}
+	return rawBlock(block)
+}
+
+func (s *Server) GetEntryCreditBlock(ctx context.Context, in *BlockRequest) (*RawBlock, error) {
+	keymr, err := blockKeyMR(in)
+	if err != nil {
+		return nil, err
+	}
+
+	var block interfaces.IEntryCreditBlock
+	if keymr != nil {
+		block, err = s.state.GetDB().FetchECBlock(keymr)
+	} else {
+		block, err = s.state.GetDB().FetchECBlockByHeight(in.Height)
+	}
+	if err != nil {
+		return nil, toStatus(wsapi.NewInternalDatabaseError())
+	}
+	if block == nil {
+		return nil, toStatus(wsapi.NewBlockNotFoundError())
+	}
+	return rawBlock(block)
+}
+
+func (s *Server) GetEntryBlock(ctx context.Context, in *BlockRequest) (*EntryBlock, error) {
+	keymr, err := blockKeyMR(in)
+	if err != nil {
+		return nil, err
+	}
+	if keymr == nil {
+		return nil, status.Error(codes.InvalidArgument, "Entry blocks can only be looked up by key_mr")
+	}
+	return s.entryBlock(keymr)
+}
+
+func (s *Server) GetEntry(ctx context.Context, in *EntryRequest) (*Entry, error) {
+	h, err := toHash(in.Hash, "hash")
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := s.state.FetchEntryByHash(h)
+	if err != nil {
+		return nil, toStatus(wsapi.NewInternalError())
+	}
+	if entry == nil {
+		dbase := s.state.GetDB()
+
+		entry, err = dbase.FetchEntry(h)
+		if err != nil {
+			return nil, toStatus(wsapi.NewInternalDatabaseError())
+		}
+		if entry == nil {
+			if pruned, _ := dbase.IsEntryPruned(h); pruned {
+				return nil, toStatus(wsapi.NewEntryPrunedError())
+			}
+			return nil, toStatus(wsapi.NewEntryNotFoundError())
+		}
+	}
+
+	e := new(Entry)
+	e.Hash = entry.GetHash().Bytes()
+	e.ChainId = entry.GetChainIDHash().Bytes()
+	e.ExtIds = entry.ExternalIDs()
+	e.Content = entry.GetContent()
+	return e, nil
+}
+
+func (s *Server) GetChainHead(ctx context.Context, in *ChainHeadRequest) (*ChainHead, error) {
+	h, err := toHash(in.ChainId, "chain_id")
+	if err != nil {
+		return nil, err
+	}
+
+	c := new(ChainHead)
+
+	// The chain may only be in the current or the previous process list
+	lh := s.state.GetLeaderHeight()
+	c.InProcessList = s.state.IsNewOrPendingEBlocks(lh, h) || s.state.IsNewOrPendingEBlocks(lh-1, h)
+
+	mr, err := s.state.GetDB().FetchHeadIndexByChainID(h)
+	if err != nil {
+		return nil, toStatus(wsapi.NewInternalDatabaseError())
+	}
+	if mr == nil {
+		if !c.InProcessList {
+			return nil, toStatus(wsapi.NewMissingChainHeadError())
+		}
+	} else {
+		c.KeyMr = mr.Bytes()
+	}
+	return c, nil
+}
+
+func (s *Server) FactoidBalance(ctx context.Context, in *BalanceRequest) (*Balance, error) {
+	adr, err := toAddress(in.Address, primitives.ValidateFUserStr)
+	if err != nil {
+		return nil, err
+	}
+	b := new(Balance)
+	b.Balance = s.state.GetFactoidState().GetFactoidBalance(factoid.NewAddress(adr).Fixed())
+	return b, nil
+}
+
+func (s *Server) EntryCreditBalance(ctx context.Context, in *BalanceRequest) (*Balance, error) {
+	adr, err := toAddress(in.Address, primitives.ValidateECUserStr)
+	if err != nil {
+		return nil, err
+	}
+	b := new(Balance)
+	b.Balance = s.state.GetFactoidState().GetECBalance(primitives.NewHash(adr).Fixed())
+	return b, nil
+}
+
+func (s *Server) EntryCreditRate(ctx context.Context, in *EntryCreditRateRequest) (*EntryCreditRate, error) {
+	r := new(EntryCreditRate)
+	r.Rate = s.state.GetPredictiveFER()
+	return r, nil
+}
+
+func (s *Server) SubmitFactoidTransaction(ctx context.Context, in *SubmitFactoidTransactionRequest) (*SubmitFactoidTransactionResponse, error) {
+	msg := new(messages.FactoidTransaction)
+	if _, err := msg.UnmarshalTransData(in.Transaction); err != nil {
+		return nil, toStatus(wsapi.NewUnableToDecodeTransactionError())
+	}
+
+	s.state.IncFCTSubmits()
+	s.state.APIQueue().Enqueue(msg)
+
+	r := new(SubmitFactoidTransactionResponse)
+	r.TxId = msg.Transaction.GetSigHash().Bytes()
+	return r, nil
+}
+
+func (s *Server) CommitChain(ctx context.Context, in *CommitRequest) (*CommitResponse, error) {
+	commit := entryCreditBlock.NewCommitChain()
+	if _, err := commit.UnmarshalBinaryData(in.Commit); err != nil || !commit.IsValid() {
+		return nil, toStatus(wsapi.NewInvalidCommitChainError())
+	}
+
+	msg := new(messages.CommitChainMsg)
+	msg.CommitChain = commit
+
+	// If this fails, a commit with greater payment already exists
+	if !s.state.IsHighestCommit(commit.GetEntryHash(), msg) {
+		return nil, status.Errorf(codes.AlreadyExists, "A commit with equal or greater payment already exists for %s", commit.GetEntryHash().String())
+	}
+
+	s.state.APIQueue().Enqueue(msg)
+	s.state.IncECCommits()
+
+	r := new(CommitResponse)
+	r.TxId = commit.GetSigHash().Bytes()
+	r.EntryHash = commit.GetEntryHash().Bytes()
+	r.ChainIdHash = commit.ChainIDHash.Bytes()
+	return r, nil
+}
+
+func (s *Server) CommitEntry(ctx context.Context, in *CommitRequest) (*CommitResponse, error) {
+	commit := entryCreditBlock.NewCommitEntry()
+	if _, err := commit.UnmarshalBinaryData(in.Commit); err != nil || !commit.IsValid() {
+		return nil, toStatus(wsapi.NewInvalidCommitEntryError())
+	}
+
+	msg := new(messages.CommitEntryMsg)
+	msg.CommitEntry = commit
+
+	// If this fails, a commit with greater payment already exists
+	if !s.state.IsHighestCommit(commit.GetEntryHash(), msg) {
+		return nil, status.Errorf(codes.AlreadyExists, "A commit with equal or greater payment already exists for %s", commit.GetEntryHash().String())
+	}
+
+	s.state.APIQueue().Enqueue(msg)
+	s.state.IncECommits()
+
+	r := new(CommitResponse)
+	r.TxId = commit.GetSigHash().Bytes()
+	r.EntryHash = commit.EntryHash.Bytes()
+	return r, nil
+}
+
+func (s *Server) RevealEntry(ctx context.Context, in *RevealRequest) (*RevealResponse, error) {
+	entry := entryBlock.NewEntry()
+	if _, err := entry.UnmarshalBinaryData(in.Entry); err != nil || !entry.IsValid() {
+		return nil, toStatus(wsapi.NewInvalidEntryError())
+	}
+
+	msg := new(messages.RevealEntryMsg)
+	msg.Entry = entry
+	msg.Timestamp = s.state.GetTimestamp()
+	s.state.APIQueue().Enqueue(msg)
+
+	r := new(RevealResponse)
+	r.EntryHash = entry.GetHash().Bytes()
+	r.ChainId = entry.ChainID.Bytes()
+	return r, nil
+}
+
+func (s *Server) FactoidAck(ctx context.Context, in *FactoidAckRequest) (*FactoidAck, error) {
+	txid, err := toHash(in.TxId, "tx_id")
+	if err != nil {
+		return nil, err
+	}
+
+	ackStatus, h, txTime, blockTime, err := s.state.GetACKStatus(txid)
+	if err != nil {
+		return nil, toStatus(wsapi.NewInternalError())
+	}
+
+	a := new(FactoidAck)
+	a.TxId = h.Bytes()
+	a.Status = toAckStatus(ackStatus)
+	if txTime != nil {
+		a.TransactionDate = txTime.GetTimeMilli()
+	}
+	if blockTime != nil {
+		a.BlockDate = blockTime.GetTimeMilli()
+	}
+	return a, nil
+}
+
+// EntryAck looks for the reveal of the entry, then for its commit, like the v2 ack
+// call given the hash of an entry
+func (s *Server) EntryAck(ctx context.Context, in *EntryAckRequest) (*EntryAck, error) {
+	hash, err := toHash(in.EntryHash, "entry_hash")
+	if err != nil {
+		return nil, err
+	}
+
+	a := new(EntryAck)
+	a.EntryHash = hash.Bytes()
+
+	revStatus, revBlktime, commit := s.state.GetEntryRevealAckByEntryHash(hash)
+	a.EntryStatus = toAckStatus(revStatus)
+	if revBlktime != nil {
+		a.BlockDate = revBlktime.GetTime().Unix()
+	}
+
+	// A revealed entry in a saved block was paid for; there is no need to look further
+	if revStatus == constants.AckStatusDBlockConfirmed {
+		txid, err := s.state.FetchPaidFor(hash)
+		if err == nil && txid != nil {
+			a.CommitTxId = txid.Bytes()
+			a.CommitStatus = AckStatus_DBLOCK_CONFIRMED
+		}
+		return a, nil
+	}
+
+	if commit != nil {
+		// The commit is in the holding queue
+		a.CommitStatus = AckStatus_NOT_CONFIRMED
+	} else {
+		var commitStatus int
+		commitStatus, commit = s.state.GetEntryCommitAckByEntryHash(hash)
+		a.CommitStatus = toAckStatus(commitStatus)
+	}
+
+	if commit != nil {
+		a.CommitDate = commit.GetTimestamp().GetTime().Unix()
+		switch c := commit.(type) {
+		case *messages.CommitEntryMsg:
+			a.CommitTxId = c.CommitEntry.GetSigHash().Bytes()
+		case *messages.CommitChainMsg:
+			a.CommitTxId = c.CommitChain.GetSigHash().Bytes()
+		}
+	}
+	return a, nil
+}
+
+// SubscribeDirectoryBlocks relays the directory blocks the websocket subscribers of
+// wsapi are told about
+func (s *Server) SubscribeDirectoryBlocks(in *SubscribeDirectoryBlocksRequest, stream Factomd_SubscribeDirectoryBlocksServer) error {
+	sub := wsapi.NewSubscriber(s.state.GetFactomNodeName())
+	defer sub.Close()
+	sub.Subscribe(wsapi.TopicNewDBlocks, nil)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case n, ok := <-sub.Notifications:
+			if !ok {
+				return nil
+			}
+			r, ok := n.Params.Result.(*wsapi.NewDBlockNotification)
+			if !ok {
+				continue
+			}
+			keymr, err := primitives.HexToHash(r.KeyMR)
+			if err != nil {
+				continue
+			}
+			block, err := s.state.GetDB().FetchDBlock(keymr)
+			if err != nil || block == nil {
+				return toStatus(wsapi.NewInternalDatabaseError())
+			}
+			d, err := directoryBlock(block)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(d); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// SubscribeEntryBlocks relays the entry blocks of a chain the websocket subscribers
+// of wsapi are told about
+func (s *Server) SubscribeEntryBlocks(in *SubscribeEntryBlocksRequest, stream Factomd_SubscribeEntryBlocksServer) error {
+	chainID, err := toHash(in.ChainId, "chain_id")
+	if err != nil {
+		return err
+	}
+
+	sub := wsapi.NewSubscriber(s.state.GetFactomNodeName())
+	defer sub.Close()
+	sub.Subscribe(wsapi.TopicNewEntries, chainID)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case n, ok := <-sub.Notifications:
+			if !ok {
+				return nil
+			}
+			r, ok := n.Params.Result.(*wsapi.NewEntriesNotification)
+			if !ok {
+				continue
+			}
+			keymr, err := primitives.HexToHash(r.EntryBlockKeyMR)
+			if err != nil {
+				continue
+			}
+			e, err := s.entryBlock(keymr)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(e); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Server) entryBlock(keymr interfaces.IHash) (*EntryBlock, error) {
+	dbase := s.state.GetDB()
+
+	block, err := dbase.FetchEBlock(keymr)
+	if err != nil {
+		return nil, toStatus(wsapi.NewInternalDatabaseError())
+	}
+	if block == nil {
+		return nil, toStatus(wsapi.NewBlockNotFoundError())
+	}
+
+	e := new(EntryBlock)
+	e.KeyMr = keymr.Bytes()
+	e.ChainId = block.GetHeader().GetChainID().Bytes()
+	e.PrevKeyMr = block.GetHeader().GetPrevKeyMR().Bytes()
+	e.Sequence = block.GetHeader().GetEBSequence()
+	e.Height = block.GetHeader().GetDBHeight()
+	if dblock, err := dbase.FetchDBlockByHeight(e.Height); err == nil && dblock != nil {
+		e.Timestamp = dblock.GetHeader().GetTimestamp().GetTimeSeconds()
+	}
+	e.Entries = entryAddresses(block, e.Timestamp)
+	return e, nil
+}
+
+// entryAddresses lists the entries of an entry block, each stamped with the end of the
+// minute it was included in, like wsapi.EBlockEntryAddrs
+func entryAddresses(block interfaces.IEntryBlock, blockTimestamp int64) []*EntryAddress {
+	var list, minute []*EntryAddress
+	for _, h := range block.GetBody().GetEBEntries() {
+		if h.IsMinuteMarker() {
+			t := blockTimestamp + 60*int64(h.ToMinute())
+			for _, e := range minute {
+				e.Timestamp = t
+				list = append(list, e)
+			}
+			minute = nil
+			continue
+		}
+		minute = append(minute, &EntryAddress{EntryHash: h.Bytes()})
+	}
+	return list
+}
+
+func directoryBlock(block interfaces.IDirectoryBlock) (*DirectoryBlock, error) {
+	raw, err := block.MarshalBinary()
+	if err != nil {
+		return nil, toStatus(wsapi.NewInternalError())
+	}
+
+	d := new(DirectoryBlock)
+	d.KeyMr = block.GetKeyMR().Bytes()
+	d.PrevKeyMr = block.GetHeader().GetPrevKeyMR().Bytes()
+	d.Height = block.GetHeader().GetDBHeight()
+	d.Timestamp = block.GetHeader().GetTimestamp().GetTimeSeconds()
+	for _, v := range block.GetDBEntries() {
+		d.EntryBlocks = append(d.EntryBlocks, &EntryBlockAddress{ChainId: v.GetChainID().Bytes(), KeyMr: v.GetKeyMR().Bytes()})
+	}
+	d.RawData = raw
+	return d, nil
+}
+
+// databaseBlock is what the admin, factoid and entry credit blocks have in common
+type databaseBlock interface {
+	MarshalBinary() ([]byte, error)
+	GetDatabaseHeight() uint32
+	DatabasePrimaryIndex() interfaces.IHash
+}
+
+func rawBlock(block databaseBlock) (*RawBlock, error) {
+	raw, err := block.MarshalBinary()
+	if err != nil {
+		return nil, toStatus(wsapi.NewInternalError())
+	}
+
+	b := new(RawBlock)
+	b.KeyMr = block.DatabasePrimaryIndex().Bytes()
+	b.Height = block.GetDatabaseHeight()
+	b.RawData = raw
+	return b, nil
+}
+
+// blockKeyMR returns nil if the block is asked for by height
+func blockKeyMR(in *BlockRequest) (interfaces.IHash, error) {
+	if len(in.KeyMr) == 0 {
+		return nil, nil
+	}
+	return toHash(in.KeyMr, "key_mr")
+}
+
+func toHash(b []byte, field string) (interfaces.IHash, error) {
+	if len(b) != constants.HASH_LENGTH {
+		return nil, status.Errorf(codes.InvalidArgument, "%s must be %d bytes", field, constants.HASH_LENGTH)
+	}
+	return primitives.NewHash(b), nil
+}
+
+// toAddress takes a human readable address, or the hex of the 32 bytes of an address
+func toAddress(address string, valid func(string) bool) ([]byte, error) {
+	if valid(address) {
+		return primitives.ConvertUserStrToAddress(address), nil
+	}
+	adr, err := hex.DecodeString(address)
+	if err != nil || len(adr) != constants.HASH_LENGTH {
+		return nil, toStatus(wsapi.NewInvalidAddressError())
+	}
+	return adr, nil
+}
+
+func toAckStatus(s int) AckStatus {
+	switch s {
+	case constants.AckStatusNotConfirmed:
+		return AckStatus_NOT_CONFIRMED
+	case constants.AckStatusACK, constants.AckStatus1Minute:
+		return AckStatus_TRANSACTION_ACK
+	case constants.AckStatusDBlockConfirmed:
+		return AckStatus_DBLOCK_CONFIRMED
+	}
+	return AckStatus_UNKNOWN
+}
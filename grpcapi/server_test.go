@@ -0,0 +1,91 @@
+package grpcapi_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/FactomProject/factomd/grpcapi"
+	"github.com/FactomProject/factomd/testHelper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func errorCode(err error) codes.Code {
+	s, _ := status.FromError(err)
+	return s.Code()
+}
+
+func TestServerBlocks(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
+	s := NewServer(state)
+	ctx := context.Background()
+
+	byHeight, err := s.GetDirectoryBlock(ctx, &BlockRequest{Height: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byKeyMR, err := s.GetDirectoryBlock(ctx, &BlockRequest{KeyMr: byHeight.KeyMr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if byKeyMR.Height != 1 || !bytes.Equal(byKeyMR.RawData, byHeight.RawData) {
+		t.Errorf("Got different blocks by height and by key merkle root")
+	}
+
+	if b, err := s.GetAdminBlock(ctx, &BlockRequest{Height: 1}); err != nil || b.Height != 1 {
+		t.Errorf("Admin block 1: %v %v", b, err)
+	}
+	if b, err := s.GetFactoidBlock(ctx, &BlockRequest{Height: 1}); err != nil || b.Height != 1 {
+		t.Errorf("Factoid block 1: %v %v", b, err)
+	}
+	if b, err := s.GetEntryCreditBlock(ctx, &BlockRequest{Height: 1}); err != nil || b.Height != 1 {
+		t.Errorf("Entry credit block 1: %v %v", b, err)
+	}
+
+	chainID := testHelper.GetChainID()
+	head, err := s.GetChainHead(ctx, &ChainHeadRequest{ChainId: chainID.Bytes()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	eblock, err := s.GetEntryBlock(ctx, &BlockRequest{KeyMr: head.KeyMr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(eblock.ChainId, chainID.Bytes()) || len(eblock.Entries) == 0 {
+		t.Fatalf("Wrong entry block %v", eblock)
+	}
+	entry, err := s.GetEntry(ctx, &EntryRequest{Hash: eblock.Entries[0].EntryHash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(entry.ChainId, chainID.Bytes()) {
+		t.Errorf("Wrong chain for entry %v", entry)
+	}
+
+	if _, err := s.GetDirectoryBlock(ctx, &BlockRequest{Height: 1000000}); errorCode(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for a missing block, got %v", err)
+	}
+	if _, err := s.GetDirectoryBlock(ctx, &BlockRequest{KeyMr: []byte{1, 2, 3}}); errorCode(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a short key merkle root, got %v", err)
+	}
+	if _, err := s.GetEntryBlock(ctx, &BlockRequest{Height: 1}); errorCode(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an entry block by height, got %v", err)
+	}
+}
+
+func TestServerBalances(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
+	s := NewServer(state)
+	ctx := context.Background()
+
+	if _, err := s.FactoidBalance(ctx, &BalanceRequest{Address: "FA2jK2HcLnRdS94dEcU27rF3meoJfpUcZPSinpb7AwQvPRY6RL1Q"}); err != nil {
+		t.Error(err)
+	}
+	if _, err := s.EntryCreditBalance(ctx, &BalanceRequest{Address: "not an address"}); errorCode(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a bad address, got %v", err)
+	}
+	if r, err := s.EntryCreditRate(ctx, &EntryCreditRateRequest{}); err != nil || r.Rate == 0 {
+		t.Errorf("Entry credit rate: %v %v", r, err)
+	}
+}
@@ -5,7 +5,6 @@
 package p2p
 
 import (
-	"encoding/gob"
 	"fmt"
 	"hash/crc32"
 	"io"
@@ -33,8 +32,8 @@ type Connection struct {
 	ReceiveChannel chan interface{}        // Receive means "from the network" Channel receives Parcels and ConnectionCommands
 	ReceiveParcel  chan *Parcel            // Parcels to be handled.
 	// and as "address" for sending messages to specific nodes.
-	encoder         *gob.Encoder      // Wire format is gobs in this version, may switch to binary
-	decoder         *gob.Decoder      // Wire format is gobs in this version, may switch to binary
+	encoder         *ParcelEncoder    // Sends gobs until the peer shows it reads the binary wire format
+	decoder         *ParcelDecoder    // Reads either wire format
 	peer            Peer              // the data structure representing the peer we are talking to. defined in peer.go
 	attempts        int               // reconnection attempts
 	TimeLastpacket  time.Time         // Time we last successfully received a packet or command.
@@ -106,6 +105,7 @@ type ConnectionMetrics struct {
 	PeerAddress      string    // Peer IP Address
 	PeerQuality      int32     // Quality of the connection.
 	PeerType         string    // Type of the peer (regular, special_config, ...)
+	PeerKey          string    // Node key the peer authenticated with, if the connection is encrypted
 	// Red: Below -50
 	// Yellow: -50 - 100
 	// Green: > 100
@@ -332,11 +332,28 @@ func (c *Connection) dial() bool {
 	address := c.peer.AddressPort()
 	// conn, err := net.Dial("tcp", c.peer.Address)
 	conn, err := net.DialTimeout("tcp", address, time.Second*10)
-	if nil == err {
-		c.conn = conn
-		return true
+	if nil != err {
+		return false
 	}
-	return false
+	if EncryptConnections {
+		secure, err := SecureHandshake(conn, NodeKey, true)
+		if nil != err {
+			c.logger.Warnf("Encrypted transport handshake failed: %v", err)
+			c.notes = fmt.Sprintf("Handshake failed: %v", err)
+			conn.Close()
+			return false
+		}
+		if "" != c.peer.PinnedKey && secure.PeerKey() != c.peer.PinnedKey {
+			c.logger.Warnf("Peer authenticated with key %s, but is pinned to %s", secure.PeerKey(), c.peer.PinnedKey)
+			c.notes = "Peer key does not match the pinned key"
+			conn.Close()
+			return false
+		}
+		c.peer.PublicKey = secure.PeerKey()
+		conn = secure
+	}
+	c.conn = conn
+	return true
 }
 
 // Called when we are online and connected to the peer.
@@ -344,8 +361,8 @@ func (c *Connection) goOnline() {
 	c.logger.Info("Connected to a remote peer")
 	p2pConnectionOnlineCall.Inc()
 	now := time.Now()
-	c.encoder = gob.NewEncoder(c.conn)
-	c.decoder = gob.NewDecoder(c.conn)
+	c.encoder = NewParcelEncoder(c.conn)
+	c.decoder = NewParcelDecoder(c.conn)
 	c.attempts = 0
 	c.timeLastPing = now
 	c.timeLastAttempt = now
@@ -513,6 +530,11 @@ func (c *Connection) processReceives() {
 				time.Sleep(500 * time.Millisecond)
 				continue
 			case nil: // successfully decoded
+				// A peer sending a version that reads binary parcels gets them from now on
+				if encoder := c.encoder; nil != encoder && ProtocolVersionBinary <= message.Header.Version && !encoder.IsBinary() {
+					c.logger.Debugf("Switching to the binary wire format, peer is at version %d", message.Header.Version)
+					encoder.UseBinary()
+				}
 				c.metrics.BytesReceived += message.Header.Length
 				c.metrics.MessagesReceived += 1
 				message.Header.PeerAddress = c.peer.Address
@@ -692,6 +714,7 @@ func (c *Connection) updateStats() {
 		c.metrics.PeerAddress = c.peer.Address
 		c.metrics.PeerQuality = c.peer.QualityScore
 		c.metrics.PeerType = c.peer.PeerTypeString()
+		c.metrics.PeerKey = c.peer.PublicKey
 		c.metrics.ConnectionState = connectionStateStrings[c.state]
 		c.metrics.ConnectionNotes = c.notes
 		c.logger.Debugf("updatePeer() SENDING ConnectionUpdateMetrics - Bytes Sent: %d Bytes Received: %d", c.metrics.BytesSent, c.metrics.BytesReceived)
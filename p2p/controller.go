@@ -11,6 +11,7 @@ package p2p
 // Other than Init and NetworkStart, all administration is done via the channel.
 
 import (
+	"encoding/hex"
 	"fmt"
 	"math/rand"
 	"net"
@@ -58,6 +59,7 @@ type Controller struct {
 	lastPeerRequest      time.Time        // Last time we asked peers about the peers they know about.
 	specialPeers         map[string]*Peer // special peers (from config file and from the command line params) by peer address
 	partsAssembler       *PartsAssembler  // a data structure that assembles full messages from received message parts
+	handshakes           chan struct{}    // one token per incoming encrypted transport handshake in progress
 
 	// logging
 	logger *log.Entry
@@ -76,6 +78,9 @@ type ControllerInit struct {
 	ConnectionMetricsChannel chan interface{} // Channel on which we put the connection metrics map, periodically.
 	LogPath                  string           // Path for logs
 	LogLevel                 string           // Logging level
+
+	Encrypt bool                   // flag to run the encrypted, authenticated transport on every connection
+	NodeKey *primitives.PrivateKey // our node key for the encrypted transport, a new one is made if nil
 }
 
 // CommandDialPeer is used to instruct the Controller to dial a peer address
@@ -90,6 +95,13 @@ type CommandAddPeer struct {
 	conn net.Conn
 }
 
+// CommandAddSecurePeer is used to instruct the Controller to check and add an incoming
+// connection whose encrypted transport handshake is done.
+type CommandAddSecurePeer struct {
+	conn   *SecureConn
+	logger *log.Entry
+}
+
 // CommandShutdown is used to instruct the Controller to takve various actions.
 type CommandShutdown struct {
 	_ uint8
@@ -182,6 +194,19 @@ func (c *Controller) Init(ci ControllerInit) *Controller {
 	CurrentNetwork = ci.Network
 	OnlySpecialPeers = ci.Exclusive || ci.ExclusiveIn
 	AllowUnknownIncomingPeers = !ci.ExclusiveIn
+	EncryptConnections = ci.Encrypt
+	NodeKey = ci.NodeKey
+	if EncryptConnections && nil == NodeKey {
+		NodeKey = new(primitives.PrivateKey)
+		if err := NodeKey.GenerateKey(); err != nil {
+			panic(err)
+		}
+		c.logger.Warn("No node key given, using a new one. Peers can't pin it across restarts")
+	}
+	if EncryptConnections {
+		c.logger.Infof("Encrypting connections, our node key is %s", NodeKey.PublicKeyString())
+	}
+	c.handshakes = make(chan struct{}, MaxIncomingHandshakes)
 	c.initSpecialPeers(ci)
 	c.lastDiscoveryRequest = time.Now() // Discovery does its own on startup.
 	c.lastConnectionMetricsUpdate = time.Now()
@@ -322,6 +347,24 @@ func (c *Controller) acceptLoop(listener net.Listener) {
 
 		connLogger := c.logger.WithField("remote_address", conn.RemoteAddr())
 
+		if EncryptConnections {
+			// Special peers may be pinned by key, so we can only tell who it is after the handshake
+			if ok, reason := c.canStartHandshake(conn); !ok {
+				connLogger.Infof("Rejecting new connection request: %s", reason)
+				_ = conn.Close()
+				continue
+			}
+			select {
+			case c.handshakes <- struct{}{}:
+			default:
+				connLogger.Infof("Rejecting new connection request: too many handshakes in progress")
+				_ = conn.Close()
+				continue
+			}
+			go c.acceptSecure(conn, connLogger)
+			continue
+		}
+
 		if ok, reason := c.canConnectTo(conn); !ok {
 			connLogger.Infof("Rejecting new connection request: %s", reason)
 			_ = conn.Close()
@@ -333,6 +376,36 @@ func (c *Controller) acceptLoop(listener net.Listener) {
 	}
 }
 
+// acceptSecure runs the encrypted transport handshake on a new incoming connection,
+// then sends it to the controller goroutine, which decides whether to accept it.
+func (c *Controller) acceptSecure(conn net.Conn, connLogger *log.Entry) {
+	secure, err := SecureHandshake(conn, NodeKey, false)
+	<-c.handshakes
+	if err != nil {
+		connLogger.Infof("Rejecting new connection request: handshake failed: %v", err)
+		_ = conn.Close()
+		return
+	}
+	connLogger = connLogger.WithField("peer_key", secure.PeerKey())
+	BlockFreeChannelSend(c.commandChannel, CommandAddSecurePeer{conn: secure, logger: connLogger})
+}
+
+// canStartHandshake checks a new incoming connection before the encrypted transport
+// handshake, so unwanted peers cost no handshake.  Handshakes in progress count as
+// incoming connections.  A peer pinned by key is only known after the handshake, so
+// while there are some, canConnectTo checks the connection again then.
+func (c *Controller) canStartHandshake(conn net.Conn) (bool, string) {
+	if c.connections.incomingCount+len(c.handshakes) >= MaxNumberIncomingConnections {
+		return false, "too many incoming connections"
+	}
+
+	if !AllowUnknownIncomingPeers && !c.isSpecialPeer(conn) && !c.hasPinnedPeers() {
+		return false, "not a special peer and unknown incoming connections are not allowed"
+	}
+
+	return true, ""
+}
+
 func (c *Controller) canConnectTo(conn net.Conn) (bool, string) {
 	if c.connections.incomingCount >= MaxNumberIncomingConnections {
 		return false, "too many incoming connections"
@@ -345,8 +418,18 @@ func (c *Controller) canConnectTo(conn net.Conn) (bool, string) {
 	return true, ""
 }
 
+// isSpecialPeer checks the connection against the special peers.  Peers pinned
+// to a key are matched by the key they authenticated with, wherever they connect
+// from; the others by IP address.
 func (c *Controller) isSpecialPeer(conn net.Conn) bool {
+	secure, isSecure := conn.(*SecureConn)
 	for _, peer := range c.specialPeers {
+		if "" != peer.PinnedKey {
+			if isSecure && secure.PeerKey() == peer.PinnedKey {
+				return true
+			}
+			continue
+		}
 		if peer.IsSamePeerAs(conn.RemoteAddr()) {
 			return true
 		}
@@ -354,6 +437,15 @@ func (c *Controller) isSpecialPeer(conn net.Conn) bool {
 	return false
 }
 
+func (c *Controller) hasPinnedPeers() bool {
+	for _, peer := range c.specialPeers {
+		if "" != peer.PinnedKey {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Controller) initSpecialPeers(ci ControllerInit) {
 	c.specialPeers = make(map[string]*Peer)
 	configPeers := c.parseSpecialPeers(ci.ConfigPeers, SpecialPeerConfig)
@@ -375,11 +467,25 @@ func (c *Controller) parseSpecialPeers(peersString string, peerType uint8) []*Pe
 	peerAddresses := strings.FieldsFunc(peersString, parseFunc)
 	peers := make([]*Peer, 0, len(peerAddresses))
 	for _, peerAddress := range peerAddresses {
+		// A peer can be pinned to its node key with 127.0.0.1:8999@<hex key>
+		var pinnedKey string
+		if i := strings.Index(peerAddress, "@"); i >= 0 {
+			peerAddress, pinnedKey = peerAddress[:i], strings.ToLower(peerAddress[i+1:])
+			if key, err := hex.DecodeString(pinnedKey); err != nil || len(key) != 32 {
+				c.logger.Errorf("%s has an invalid node key, use format: 127.0.0.1:8999@<64 hex characters>", peerAddress)
+				continue
+			}
+			if !EncryptConnections {
+				// The key is only known from the encrypted transport handshake
+				c.logger.Warnf("%s is pinned to a node key, but connections are not encrypted, so it is never trusted as a special peer", peerAddress)
+			}
+		}
 		address, port, err := net.SplitHostPort(peerAddress)
 		if err != nil {
 			c.logger.Errorf("%s is not a valid peer (%v), use format: 127.0.0.1:8999", peersString, err)
 		} else {
 			peer := new(Peer).Init(address, port, 0, peerType, 0)
+			peer.PinnedKey = pinnedKey
 			peer.Source["Local-Configuration"] = time.Now()
 			peers = append(peers, peer)
 		}
@@ -525,13 +631,16 @@ func (c *Controller) handleCommand(command interface{}) {
 	case CommandAddPeer: // parameter is a Connection. This message is sent by the accept loop which is in a different goroutine
 
 		parameters := command.(CommandAddPeer)
-		conn := parameters.conn // net.Conn
-		addPort := strings.Split(conn.RemoteAddr().String(), ":")
-		// Port initially stored will be the connection port (not the listen port), but peer will update it on first message.
-		peer := new(Peer).Init(addPort[0], addPort[1], 0, RegularPeer, 0)
-		peer.Source["Accept()"] = time.Now()
-		connection := new(Connection).InitWithConn(conn, *peer)
-		c.handleNewConnection(connection)
+		c.addIncomingPeer(parameters.conn)
+	case CommandAddSecurePeer: // sent by acceptSecure once the handshake is done
+		parameters := command.(CommandAddSecurePeer)
+		if ok, reason := c.canConnectTo(parameters.conn); !ok {
+			parameters.logger.Infof("Rejecting new connection request: %s", reason)
+			_ = parameters.conn.Close()
+			return
+		}
+		c.addIncomingPeer(parameters.conn)
+		parameters.logger.Infof("Accepting new incoming encrypted connection")
 	case CommandShutdown:
 		c.shutdown()
 	case CommandAdjustPeerQuality:
@@ -553,6 +662,19 @@ func (c *Controller) handleCommand(command interface{}) {
 	}
 }
 
+// addIncomingPeer adds a connection accepted by acceptLoop
+func (c *Controller) addIncomingPeer(conn net.Conn) {
+	addPort := strings.Split(conn.RemoteAddr().String(), ":")
+	// Port initially stored will be the connection port (not the listen port), but peer will update it on first message.
+	peer := new(Peer).Init(addPort[0], addPort[1], 0, RegularPeer, 0)
+	peer.Source["Accept()"] = time.Now()
+	if secure, ok := conn.(*SecureConn); ok {
+		peer.PublicKey = secure.PeerKey()
+	}
+	connection := new(Connection).InitWithConn(conn, *peer)
+	c.handleNewConnection(connection)
+}
+
 func (c *Controller) handleNewConnection(connection *Connection) {
 	oldConnection, alreadyConnected := c.connections.GetByHash(connection.peer.Hash)
 	if alreadyConnected {
@@ -642,6 +764,7 @@ func (c *Controller) updateMetrics() {
 					PeerAddress:      metrics.PeerAddress,
 					PeerQuality:      metrics.PeerQuality,
 					PeerType:         metrics.PeerType,
+					PeerKey:          metrics.PeerKey,
 					ConnectionState:  metrics.ConnectionState,
 					ConnectionNotes:  metrics.ConnectionNotes,
 				}
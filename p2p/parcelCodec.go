@@ -0,0 +1,256 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// The binary wire format, used between peers running ProtocolVersionBinary or later.
+//
+// Every parcel is framed as:
+//
+//	magic        1 byte  - BinaryParcelMagic
+//	header size  2 bytes - size of the header that follows
+//	payload size 4 bytes - size of the payload that follows the header
+//	header       fixed fields in the order of ParcelHeader, then the strings
+//	             TargetPeer, PeerAddress, PeerPort, AppHash, AppType, each
+//	             prefixed with a 2 byte length
+//	payload
+//
+// All integers are big endian.  A gob stream never starts a message with the magic
+// byte (gob lengths begin with 0x01-0x7F or 0xF8-0xFF), so a reader can tell the
+// two formats apart one parcel at a time.  Connections start out sending gobs, and
+// switch to binary once the peer shows it understands it.
+//
+// The methods are deliberately not named MarshalBinary/UnmarshalBinary, as gob would
+// pick those up and change the gob encoding older peers expect.
+
+// BinaryParcelMagic is the first byte of every binary encoded parcel
+const BinaryParcelMagic byte = 0xB1
+
+// MaxParcelHeaderString is the longest string a binary parcel header can carry
+const MaxParcelHeaderString = 1024
+
+const (
+	binaryFrameSize         = 7  // magic + header size + payload size
+	binaryHeaderFixedSize   = 28 // Network through NodeID
+	binaryHeaderStringCount = 5
+	maxBinaryParcelHeader   = binaryHeaderFixedSize + binaryHeaderStringCount*(2+MaxParcelHeaderString)
+)
+
+// EncodeBinary encodes the parcel in the binary wire format, frame included
+func (p *Parcel) EncodeBinary() ([]byte, error) {
+	if len(p.Payload) > MaxPayloadSize {
+		return nil, fmt.Errorf("Payload of %d bytes exceeds the maximum of %d", len(p.Payload), MaxPayloadSize)
+	}
+	header, err := p.Header.EncodeBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, binaryFrameSize, binaryFrameSize+len(header)+len(p.Payload))
+	data[0] = BinaryParcelMagic
+	binary.BigEndian.PutUint16(data[1:3], uint16(len(header)))
+	binary.BigEndian.PutUint32(data[3:7], uint32(len(p.Payload)))
+	data = append(data, header...)
+	data = append(data, p.Payload...)
+	return data, nil
+}
+
+// DecodeBinary decodes a parcel encoded by EncodeBinary.  The data must hold
+// exactly one parcel.
+func (p *Parcel) DecodeBinary(data []byte) error {
+	if len(data) < binaryFrameSize {
+		return fmt.Errorf("Parcel too short: %d bytes", len(data))
+	}
+	headerSize, payloadSize, err := readBinaryFrame(data[:binaryFrameSize])
+	if err != nil {
+		return err
+	}
+	if len(data) != binaryFrameSize+headerSize+payloadSize {
+		return fmt.Errorf("Parcel is %d bytes, frame says %d", len(data), binaryFrameSize+headerSize+payloadSize)
+	}
+	err = p.Header.DecodeBinary(data[binaryFrameSize : binaryFrameSize+headerSize])
+	if err != nil {
+		return err
+	}
+	p.Payload = append([]byte{}, data[binaryFrameSize+headerSize:]...)
+	return nil
+}
+
+// EncodeBinary encodes the header, without the frame
+func (h *ParcelHeader) EncodeBinary() ([]byte, error) {
+	strs := []string{h.TargetPeer, h.PeerAddress, h.PeerPort, h.AppHash, h.AppType}
+	size := binaryHeaderFixedSize
+	for _, s := range strs {
+		if len(s) > MaxParcelHeaderString {
+			return nil, fmt.Errorf("Header string of %d bytes exceeds the maximum of %d", len(s), MaxParcelHeaderString)
+		}
+		size += 2 + len(s)
+	}
+
+	data := make([]byte, binaryHeaderFixedSize, size)
+	binary.BigEndian.PutUint32(data[0:4], uint32(h.Network))
+	binary.BigEndian.PutUint16(data[4:6], h.Version)
+	binary.BigEndian.PutUint16(data[6:8], uint16(h.Type))
+	binary.BigEndian.PutUint32(data[8:12], h.Length)
+	binary.BigEndian.PutUint32(data[12:16], h.Crc32)
+	binary.BigEndian.PutUint16(data[16:18], h.PartNo)
+	binary.BigEndian.PutUint16(data[18:20], h.PartsTotal)
+	binary.BigEndian.PutUint64(data[20:28], h.NodeID)
+	for _, s := range strs {
+		var l [2]byte
+		binary.BigEndian.PutUint16(l[:], uint16(len(s)))
+		data = append(data, l[:]...)
+		data = append(data, s...)
+	}
+	return data, nil
+}
+
+// DecodeBinary decodes a header encoded by EncodeBinary
+func (h *ParcelHeader) DecodeBinary(data []byte) error {
+	if len(data) < binaryHeaderFixedSize {
+		return fmt.Errorf("Parcel header too short: %d bytes", len(data))
+	}
+	h.Network = NetworkID(binary.BigEndian.Uint32(data[0:4]))
+	h.Version = binary.BigEndian.Uint16(data[4:6])
+	h.Type = ParcelCommandType(binary.BigEndian.Uint16(data[6:8]))
+	h.Length = binary.BigEndian.Uint32(data[8:12])
+	h.Crc32 = binary.BigEndian.Uint32(data[12:16])
+	h.PartNo = binary.BigEndian.Uint16(data[16:18])
+	h.PartsTotal = binary.BigEndian.Uint16(data[18:20])
+	h.NodeID = binary.BigEndian.Uint64(data[20:28])
+
+	data = data[binaryHeaderFixedSize:]
+	var strs [binaryHeaderStringCount]string
+	for i := range strs {
+		if len(data) < 2 {
+			return fmt.Errorf("Parcel header truncated")
+		}
+		l := int(binary.BigEndian.Uint16(data[0:2]))
+		if l > MaxParcelHeaderString {
+			return fmt.Errorf("Header string of %d bytes exceeds the maximum of %d", l, MaxParcelHeaderString)
+		}
+		if len(data) < 2+l {
+			return fmt.Errorf("Parcel header truncated")
+		}
+		strs[i] = string(data[2 : 2+l])
+		data = data[2+l:]
+	}
+	if len(data) != 0 {
+		return fmt.Errorf("Parcel header has %d extra bytes", len(data))
+	}
+	h.TargetPeer, h.PeerAddress, h.PeerPort, h.AppHash, h.AppType = strs[0], strs[1], strs[2], strs[3], strs[4]
+	return nil
+}
+
+// readBinaryFrame checks the frame and returns the header and payload sizes.  The
+// sizes are checked before anything is allocated, so a bad peer can't make us
+// allocate more than MaxPayloadSize.
+func readBinaryFrame(frame []byte) (int, int, error) {
+	if frame[0] != BinaryParcelMagic {
+		return 0, 0, fmt.Errorf("Not a binary parcel, first byte is %x", frame[0])
+	}
+	headerSize := int(binary.BigEndian.Uint16(frame[1:3]))
+	payloadSize := int64(binary.BigEndian.Uint32(frame[3:7]))
+	if headerSize < binaryHeaderFixedSize || headerSize > maxBinaryParcelHeader {
+		return 0, 0, fmt.Errorf("Bad parcel header size %d", headerSize)
+	}
+	if payloadSize > MaxPayloadSize {
+		return 0, 0, fmt.Errorf("Payload of %d bytes exceeds the maximum of %d", payloadSize, MaxPayloadSize)
+	}
+	return headerSize, int(payloadSize), nil
+}
+
+// ParcelEncoder writes parcels to a connection.  It writes gobs until UseBinary
+// is called, and the binary wire format from then on.
+type ParcelEncoder struct {
+	w      io.Writer
+	gob    *gob.Encoder
+	binary int32 // atomic, set once the peer is known to read binary
+}
+
+func NewParcelEncoder(w io.Writer) *ParcelEncoder {
+	e := new(ParcelEncoder)
+	e.w = w
+	e.gob = gob.NewEncoder(w)
+	return e
+}
+
+// UseBinary switches the encoder to the binary wire format.  There is no going back.
+func (e *ParcelEncoder) UseBinary() {
+	atomic.StoreInt32(&e.binary, 1)
+}
+
+// IsBinary is true once the encoder writes the binary wire format
+func (e *ParcelEncoder) IsBinary() bool {
+	return atomic.LoadInt32(&e.binary) == 1
+}
+
+func (e *ParcelEncoder) Encode(parcel Parcel) error {
+	if !e.IsBinary() {
+		return e.gob.Encode(parcel)
+	}
+	data, err := parcel.EncodeBinary()
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+// ParcelDecoder reads parcels from a connection, in either wire format.  The
+// format is picked for each parcel by peeking at its first byte.
+type ParcelDecoder struct {
+	r   *bufio.Reader
+	gob *gob.Decoder
+}
+
+func NewParcelDecoder(r io.Reader) *ParcelDecoder {
+	d := new(ParcelDecoder)
+	d.r = bufio.NewReader(r)
+	// bufio.Reader is an io.ByteReader, so gob reads exactly one message at a time
+	// from it, and leaves the rest for us to peek at.
+	d.gob = gob.NewDecoder(d.r)
+	return d
+}
+
+func (d *ParcelDecoder) Decode(parcel *Parcel) error {
+	first, err := d.r.Peek(1)
+	if err != nil {
+		return err
+	}
+	if first[0] != BinaryParcelMagic {
+		return d.gob.Decode(parcel)
+	}
+
+	var frame [binaryFrameSize]byte
+	_, err = io.ReadFull(d.r, frame[:])
+	if err != nil {
+		return err
+	}
+	headerSize, payloadSize, err := readBinaryFrame(frame[:])
+	if err != nil {
+		return err
+	}
+	header := make([]byte, headerSize)
+	_, err = io.ReadFull(d.r, header)
+	if err != nil {
+		return err
+	}
+	err = parcel.Header.DecodeBinary(header)
+	if err != nil {
+		return err
+	}
+	parcel.Payload = make([]byte, payloadSize)
+	_, err = io.ReadFull(d.r, parcel.Payload)
+	return err
+}
@@ -0,0 +1,240 @@
+package p2p_test
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"math/rand"
+	"testing"
+
+	. "github.com/FactomProject/factomd/p2p"
+)
+
+func randomString(r *rand.Rand) string {
+	b := make([]byte, r.Intn(64))
+	r.Read(b)
+	return string(b)
+}
+
+func randomParcel(r *rand.Rand) *Parcel {
+	payload := make([]byte, r.Intn(5000))
+	r.Read(payload)
+	p := NewParcel(NetworkID(r.Uint32()), payload)
+	p.Header.Version = uint16(r.Intn(20))
+	p.Header.Type = ParcelCommandType(r.Intn(8))
+	p.Header.TargetPeer = randomString(r)
+	p.Header.PartNo = uint16(r.Uint32())
+	p.Header.PartsTotal = uint16(r.Uint32())
+	p.Header.NodeID = uint64(r.Int63())
+	p.Header.PeerAddress = randomString(r)
+	p.Header.PeerPort = fmt.Sprintf("%d", r.Intn(65536))
+	p.Header.AppHash = randomString(r)
+	p.Header.AppType = randomString(r)
+	return p
+}
+
+func sameParcel(a, b *Parcel) bool {
+	return a.Header == b.Header && bytes.Equal(a.Payload, b.Payload)
+}
+
+func TestParcelIsNotBinaryMarshaler(t *testing.T) {
+	// gob would use these, and change the encoding older peers expect
+	if _, ok := interface{}(new(Parcel)).(encoding.BinaryMarshaler); ok {
+		t.Error("Parcel implements encoding.BinaryMarshaler")
+	}
+	if _, ok := interface{}(new(ParcelHeader)).(encoding.BinaryMarshaler); ok {
+		t.Error("ParcelHeader implements encoding.BinaryMarshaler")
+	}
+}
+
+func TestParcelBinaryRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		p := randomParcel(r)
+		data, err := p.EncodeBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data[0] != BinaryParcelMagic {
+			t.Fatalf("Binary parcel starts with %x", data[0])
+		}
+		p2 := new(Parcel)
+		err = p2.DecodeBinary(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sameParcel(p, p2) {
+			t.Fatalf("Round trip failed, got %v expected %v", p2, p)
+		}
+	}
+}
+
+// The binary format carries exactly what the gob format does
+func TestParcelBinaryMatchesGob(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	dec := gob.NewDecoder(&buf)
+	for i := 0; i < 1000; i++ {
+		p := randomParcel(r)
+		err := enc.Encode(*p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fromGob := new(Parcel)
+		err = dec.Decode(fromGob)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := p.EncodeBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		fromBinary := new(Parcel)
+		err = fromBinary.DecodeBinary(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sameParcel(fromGob, fromBinary) {
+			t.Fatalf("Gob gave %v, binary gave %v", fromGob, fromBinary)
+		}
+	}
+}
+
+// A connection starts with gobs and switches to binary part way through the stream
+func TestParcelMixedStream(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	var buf bytes.Buffer
+	enc := NewParcelEncoder(&buf)
+
+	var sent []*Parcel
+	for i := 0; i < 20; i++ {
+		if i == 10 {
+			enc.UseBinary()
+		}
+		p := randomParcel(r)
+		err := enc.Encode(*p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sent = append(sent, p)
+	}
+
+	dec := NewParcelDecoder(&buf)
+	for i, p := range sent {
+		var got Parcel
+		err := dec.Decode(&got)
+		if err != nil {
+			t.Fatalf("Parcel %d: %v", i, err)
+		}
+		if !sameParcel(p, &got) {
+			t.Fatalf("Parcel %d: got %v expected %v", i, got, p)
+		}
+	}
+	var got Parcel
+	if err := dec.Decode(&got); err != io.EOF {
+		t.Errorf("Expected EOF at the end of the stream, got %v", err)
+	}
+}
+
+// Peers that only speak gob are read as before
+func TestParcelDecoderReadsGobPeers(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	var sent []*Parcel
+	for i := 0; i < 10; i++ {
+		p := randomParcel(r)
+		p.Header.Version = ProtocolVersionMinimum
+		err := enc.Encode(*p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sent = append(sent, p)
+	}
+
+	dec := NewParcelDecoder(&buf)
+	for i, p := range sent {
+		var got Parcel
+		err := dec.Decode(&got)
+		if err != nil {
+			t.Fatalf("Parcel %d: %v", i, err)
+		}
+		if !sameParcel(p, &got) {
+			t.Fatalf("Parcel %d: got %v expected %v", i, got, p)
+		}
+	}
+}
+
+func TestParcelBinaryLimits(t *testing.T) {
+	p := NewParcel(MainNet, []byte("hello"))
+	p.Header.AppHash = string(make([]byte, MaxParcelHeaderString+1))
+	if _, err := p.EncodeBinary(); err == nil {
+		t.Error("Encoded a header string over the limit")
+	}
+
+	// A frame announcing a payload over the limit is refused before it is read
+	frame := []byte{BinaryParcelMagic, 0, 0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint16(frame[1:3], 40)
+	binary.BigEndian.PutUint32(frame[3:7], MaxPayloadSize+1)
+	var got Parcel
+	if err := NewParcelDecoder(bytes.NewReader(frame)).Decode(&got); err == nil {
+		t.Error("Decoded a payload over the limit")
+	}
+}
+
+// Garbage, truncated and corrupted parcels must give errors, never panics
+func TestParcelBinaryFuzz(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for i := 0; i < 5000; i++ {
+		var data []byte
+		switch i % 3 {
+		case 0: // random bytes, with and without a valid start
+			data = make([]byte, r.Intn(200))
+			r.Read(data)
+			if len(data) > 0 && r.Intn(2) == 0 {
+				data[0] = BinaryParcelMagic
+			}
+		case 1: // truncated
+			full, _ := randomParcel(r).EncodeBinary()
+			data = full[:r.Intn(len(full))]
+		case 2: // flipped bytes
+			data, _ = randomParcel(r).EncodeBinary()
+			for j := 0; j < 1+r.Intn(4); j++ {
+				data[r.Intn(len(data))] ^= byte(1 + r.Intn(255))
+			}
+		}
+		fuzzParcel(t, data)
+	}
+}
+
+func fuzzParcel(t *testing.T, data []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("Panic decoding %x: %v", data, r)
+		}
+	}()
+
+	p := new(Parcel)
+	if err := p.DecodeBinary(data); err == nil {
+		// Whatever decodes has to encode back to the same bytes
+		again, err := p.EncodeBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(again, data) {
+			t.Fatalf("Decoded %x, but it encodes to %x", data, again)
+		}
+	}
+
+	dec := NewParcelDecoder(bytes.NewReader(data))
+	for i := 0; i < 10; i++ {
+		if err := dec.Decode(new(Parcel)); err != nil {
+			break
+		}
+	}
+}
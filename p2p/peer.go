@@ -30,6 +30,10 @@ type Peer struct {
 	LastContact  time.Time            // Keep track of how long ago we talked to the peer.
 	Source       map[string]time.Time // source where we heard from the peer.
 
+	// encrypted transport
+	PublicKey string `json:"-"` // hex node key the peer authenticated with
+	PinnedKey string `json:"-"` // for special peers, the hex node key the peer must authenticate with
+
 	// logging
 	logger *log.Entry
 }
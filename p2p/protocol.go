@@ -69,6 +69,11 @@ var (
 	PeerRequestInterval                 = time.Second * 180
 	PeerDiscoveryInterval               = time.Hour * 4
 
+	// Encrypted transport
+	EncryptConnections                           = false // run the encrypted transport handshake on every connection
+	NodeKey               *primitives.PrivateKey         // our ed25519 node key, used to authenticate the encrypted transport
+	MaxIncomingHandshakes = 16                           // incoming encrypted transport handshakes in progress at once
+
 	// Testing metrics
 	TotalMessagesReceived       uint64
 	TotalMessagesSent           uint64
@@ -81,9 +86,12 @@ var (
 
 const (
 	// ProtocolVersion is the latest version this package supports
-	ProtocolVersion uint16 = 9
+	ProtocolVersion uint16 = 10
 	// ProtocolVersionMinimum is the earliest version this package supports
 	ProtocolVersionMinimum uint16 = 9
+	// ProtocolVersionBinary is the first version that reads the binary wire format.
+	// Older peers are sent gobs.
+	ProtocolVersionBinary uint16 = 10
 )
 
 // NetworkIdentifier represents the P2P network we are participating in (eg: test, nmain, etc.)
@@ -0,0 +1,289 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The encrypted transport.  When EncryptConnections is on, every connection starts
+// with a handshake, before any parcel is sent:
+//
+//	1. Both sides send a hello: the magic, the network, and an ephemeral P-256 key.
+//	2. Both sides derive the session keys from the ECDH secret and the two hellos,
+//	   one AES-256-GCM key per direction.
+//	3. Over the encrypted session, both sides send their ed25519 node key and a
+//	   signature of the two hellos.  The signature ties the node key to this session,
+//	   so the peer key is authenticated and can't be replayed or relayed.
+//
+// After the handshake, data travels in records of a 4 byte length followed by the
+// sealed data.  The nonce is a per direction record counter.
+
+var secureLogger = packageLogger.WithField("subpack", "secure")
+
+// handshakeMagic starts every handshake, and doubles as the transport version
+var handshakeMagic = []byte("FCTSEC01")
+
+const (
+	HandshakeTimeout   = time.Second * 10
+	maxRecordPlaintext = 16 * 1024
+	maxRecordSize      = maxRecordPlaintext + 16 // GCM tag
+	helloSize          = 8 + 4 + 65              // magic + network + uncompressed P-256 point
+	authSize           = 32 + 64                 // ed25519 public key + signature
+)
+
+var authContext = []byte("factomd p2p auth")
+
+// SecureConn is a net.Conn encrypted and authenticated by the handshake
+type SecureConn struct {
+	net.Conn
+	peerKey []byte // the authenticated ed25519 key of the peer
+
+	writeLock sync.Mutex
+	send      cipher.AEAD
+	sendCount uint64
+
+	recv      cipher.AEAD
+	recvCount uint64
+	recvBuf   []byte // decrypted data not yet read
+}
+
+// PeerKey returns the hex encoded ed25519 key the peer authenticated with
+func (s *SecureConn) PeerKey() string {
+	return hex.EncodeToString(s.peerKey)
+}
+
+// SecureHandshake runs the handshake on conn, signing with our node key.  The side
+// that dialed is the initiator.  If the handshake fails the conn is left for the
+// caller to close.
+func SecureHandshake(conn net.Conn, key *primitives.PrivateKey, initiator bool) (*SecureConn, error) {
+	conn.SetDeadline(time.Now().Add(HandshakeTimeout))
+	defer conn.SetDeadline(time.Time{})
+
+	// 1. Exchange hellos
+	ephemeral, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	hello := make([]byte, 0, helloSize)
+	hello = append(hello, handshakeMagic...)
+	var network [4]byte
+	binary.BigEndian.PutUint32(network[:], uint32(CurrentNetwork))
+	hello = append(hello, network[:]...)
+	hello = append(hello, elliptic.Marshal(elliptic.P256(), ephemeral.X, ephemeral.Y)...)
+
+	_, err = conn.Write(hello)
+	if err != nil {
+		return nil, err
+	}
+	peerHello := make([]byte, helloSize)
+	_, err = io.ReadFull(conn, peerHello)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(peerHello[:8], handshakeMagic) {
+		return nil, fmt.Errorf("Peer does not speak the encrypted transport")
+	}
+	if !bytes.Equal(peerHello[8:12], network[:]) {
+		return nil, fmt.Errorf("Peer is on network %x", peerHello[8:12])
+	}
+	x, y := elliptic.Unmarshal(elliptic.P256(), peerHello[12:])
+	if x == nil {
+		return nil, fmt.Errorf("Peer sent an invalid ephemeral key")
+	}
+
+	// 2. Derive the session keys
+	transcript := sha256.New()
+	if initiator {
+		transcript.Write(hello)
+		transcript.Write(peerHello)
+	} else {
+		transcript.Write(peerHello)
+		transcript.Write(hello)
+	}
+	th := transcript.Sum(nil)
+
+	sx, _ := elliptic.P256().ScalarMult(x, y, ephemeral.D.Bytes())
+	secret := make([]byte, 32)
+	sxBytes := sx.Bytes()
+	copy(secret[32-len(sxBytes):], sxBytes)
+	keys := hkdfSHA256(secret, th, []byte("factomd p2p keys"), 64)
+	initiatorKey, responderKey := keys[:32], keys[32:]
+
+	s := new(SecureConn)
+	s.Conn = conn
+	if initiator {
+		s.send, err = newGCM(initiatorKey)
+		if err == nil {
+			s.recv, err = newGCM(responderKey)
+		}
+	} else {
+		s.send, err = newGCM(responderKey)
+		if err == nil {
+			s.recv, err = newGCM(initiatorKey)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Authenticate the node keys over the encrypted session
+	auth := make([]byte, 0, authSize)
+	auth = append(auth, key.Public()...)
+	auth = append(auth, primitives.Sign(key.Key[:], authMessage(th, initiator))...)
+	_, err = s.Write(auth)
+	if err != nil {
+		return nil, err
+	}
+	peerAuth := make([]byte, authSize)
+	_, err = io.ReadFull(s, peerAuth)
+	if err != nil {
+		return nil, err
+	}
+	err = primitives.VerifySignature(authMessage(th, !initiator), peerAuth[:32], peerAuth[32:])
+	if err != nil {
+		return nil, fmt.Errorf("Peer failed to authenticate: %v", err)
+	}
+	s.peerKey = peerAuth[:32]
+	return s, nil
+}
+
+// authMessage is what each side signs.  The role is included so a peer can't
+// reflect our own signature back at us.
+func authMessage(th []byte, initiator bool) []byte {
+	msg := append([]byte{}, authContext...)
+	if initiator {
+		msg = append(msg, 'I')
+	} else {
+		msg = append(msg, 'R')
+	}
+	return append(msg, th...)
+}
+
+func (s *SecureConn) Write(data []byte) (int, error) {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+
+	written := 0
+	for len(data) > 0 {
+		chunk := data
+		if len(chunk) > maxRecordPlaintext {
+			chunk = chunk[:maxRecordPlaintext]
+		}
+		record := make([]byte, 4, 4+len(chunk)+s.send.Overhead())
+		record = s.send.Seal(record, recordNonce(s.sendCount), chunk, nil)
+		s.sendCount++
+		binary.BigEndian.PutUint32(record[:4], uint32(len(record)-4))
+		_, err := s.Conn.Write(record)
+		if err != nil {
+			return written, err
+		}
+		written += len(chunk)
+		data = data[len(chunk):]
+	}
+	return written, nil
+}
+
+func (s *SecureConn) Read(data []byte) (int, error) {
+	for len(s.recvBuf) == 0 {
+		var size [4]byte
+		_, err := io.ReadFull(s.Conn, size[:])
+		if err != nil {
+			return 0, err
+		}
+		l := binary.BigEndian.Uint32(size[:])
+		if l < uint32(s.recv.Overhead()) || l > maxRecordSize {
+			return 0, fmt.Errorf("Bad encrypted record size %d", l)
+		}
+		record := make([]byte, l)
+		_, err = io.ReadFull(s.Conn, record)
+		if err != nil {
+			return 0, err
+		}
+		s.recvBuf, err = s.recv.Open(record[:0], recordNonce(s.recvCount), record, nil)
+		if err != nil {
+			secureLogger.WithField("remote_address", s.RemoteAddr()).Warn("Dropping connection with a forged or corrupt record")
+			return 0, err
+		}
+		s.recvCount++
+	}
+	n := copy(data, s.recvBuf)
+	s.recvBuf = s.recvBuf[n:]
+	return n, nil
+}
+
+func recordNonce(count uint64) []byte {
+	nonce := make([]byte, 12)
+	binary.BigEndian.PutUint64(nonce[4:], count)
+	return nonce
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// hkdfSHA256 is HKDF (RFC 5869) extract and expand, with SHA-256
+func hkdfSHA256(secret, salt, info []byte, length int) []byte {
+	extract := hmac.New(sha256.New, salt)
+	extract.Write(secret)
+	prk := extract.Sum(nil)
+
+	var out, prev []byte
+	for i := byte(1); len(out) < length; i++ {
+		expand := hmac.New(sha256.New, prk)
+		expand.Write(prev)
+		expand.Write(info)
+		expand.Write([]byte{i})
+		prev = expand.Sum(nil)
+		out = append(out, prev...)
+	}
+	return out[:length]
+}
+
+// LoadNodeKey reads the node key from a file holding the hex private key, and creates
+// the file with a new key if there is none.  The public half is what other nodes pin.
+func LoadNodeKey(path string) (*primitives.PrivateKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		key := new(primitives.PrivateKey)
+		err = key.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		err = ioutil.WriteFile(path, []byte(key.PrivateKeyString()+"\n"), 0600)
+		if err != nil {
+			return nil, err
+		}
+		secureLogger.Infof("Created a new node key in %s", path)
+		return key, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return primitives.NewPrivateKeyFromHex(strings.TrimSpace(string(data)))
+}
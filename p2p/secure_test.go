@@ -0,0 +1,181 @@
+package p2p_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/p2p"
+)
+
+// connectedPair returns both ends of a loopback TCP connection
+func connectedPair(t *testing.T) (net.Conn, net.Conn) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	accepted := make(chan net.Conn)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			t.Error(err)
+		}
+		accepted <- conn
+	}()
+	dialer, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dialer, <-accepted
+}
+
+type handshakeResult struct {
+	conn *SecureConn
+	err  error
+}
+
+func handshakePair(t *testing.T, initiatorKey, responderKey *primitives.PrivateKey) (*SecureConn, *SecureConn, error, error) {
+	a, b := connectedPair(t)
+	done := make(chan handshakeResult)
+	go func() {
+		conn, err := SecureHandshake(b, responderKey, false)
+		done <- handshakeResult{conn, err}
+	}()
+	initiator, err := SecureHandshake(a, initiatorKey, true)
+	if err != nil {
+		a.Close() // so the responder doesn't wait for the timeout
+	}
+	responder := <-done
+	return initiator, responder.conn, err, responder.err
+}
+
+func TestSecureHandshake(t *testing.T) {
+	initiatorKey := primitives.RandomPrivateKey()
+	responderKey := primitives.RandomPrivateKey()
+
+	initiator, responder, err1, err2 := handshakePair(t, initiatorKey, responderKey)
+	if err1 != nil || err2 != nil {
+		t.Fatal(err1, err2)
+	}
+	defer initiator.Close()
+	defer responder.Close()
+
+	if initiator.PeerKey() != responderKey.PublicKeyString() {
+		t.Errorf("Initiator sees key %s, expected %s", initiator.PeerKey(), responderKey.PublicKeyString())
+	}
+	if responder.PeerKey() != initiatorKey.PublicKeyString() {
+		t.Errorf("Responder sees key %s, expected %s", responder.PeerKey(), initiatorKey.PublicKeyString())
+	}
+
+	// Send more than a record's worth each way, with parcels on top as the connection does
+	payload := make([]byte, 100000)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+	parcel := NewParcel(MainNet, payload)
+	go func() {
+		enc := NewParcelEncoder(initiator)
+		enc.Encode(*parcel)
+		enc.UseBinary()
+		enc.Encode(*parcel)
+	}()
+	dec := NewParcelDecoder(responder)
+	for i := 0; i < 2; i++ {
+		var got Parcel
+		err := dec.Decode(&got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got.Payload, payload) {
+			t.Errorf("Parcel %d payload changed in transit", i)
+		}
+	}
+
+	go responder.Write([]byte("pong"))
+	pong := make([]byte, 4)
+	_, err := io.ReadFull(initiator, pong)
+	if err != nil || string(pong) != "pong" {
+		t.Errorf("Got %q, %v", pong, err)
+	}
+}
+
+func TestSecureConnIsEncrypted(t *testing.T) {
+	a, b := connectedPair(t)
+	defer a.Close()
+	defer b.Close()
+
+	// Tap the initiator's side of the wire
+	tap := &tapConn{Conn: a}
+	done := make(chan *SecureConn)
+	go func() {
+		conn, _ := SecureHandshake(b, primitives.RandomPrivateKey(), false)
+		done <- conn
+	}()
+	initiator, err := SecureHandshake(tap, primitives.RandomPrivateKey(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	responder := <-done
+
+	secret := []byte("a secret that should never be seen on the wire")
+	go initiator.Write(secret)
+	got := make([]byte, len(secret))
+	_, err = io.ReadFull(responder, got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(tap.written.Bytes(), secret) {
+		t.Error("Cleartext found on the wire")
+	}
+}
+
+type tapConn struct {
+	net.Conn
+	written bytes.Buffer
+}
+
+func (t *tapConn) Write(data []byte) (int, error) {
+	t.written.Write(data)
+	return t.Conn.Write(data)
+}
+
+func TestSecureHandshakeRejectsPlainPeers(t *testing.T) {
+	a, b := connectedPair(t)
+	defer a.Close()
+	defer b.Close()
+
+	// A peer that just starts sending gobs
+	go NewParcelEncoder(b).Encode(*NewParcel(MainNet, make([]byte, 200)))
+	_, err := SecureHandshake(a, primitives.RandomPrivateKey(), true)
+	if err == nil {
+		t.Error("Handshake succeeded with a peer that does not run it")
+	}
+}
+
+func TestLoadNodeKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nodekey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "p2pkey.txt")
+
+	created, err := LoadNodeKey(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadNodeKey(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.PublicKeyString() != loaded.PublicKeyString() {
+		t.Errorf("Loaded key %s, created %s", loaded.PublicKeyString(), created.PublicKeyString())
+	}
+}
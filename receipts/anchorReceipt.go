@@ -0,0 +1,136 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package receipts
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/FactomProject/factomd/anchor"
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// AnchorReceipt ties the directory block of a receipt to a Bitcoin or Ethereum
+// transaction.  It carries the whole anchor chain entry, so the signature of the
+// anchor record can be checked without access to a factomd node.
+type AnchorReceipt struct {
+	Entry    *JSON                  `json:"entry"`
+	Bitcoin  *anchor.BitcoinStruct  `json:"bitcoin,omitempty"`
+	Ethereum *anchor.EthereumStruct `json:"ethereum,omitempty"`
+}
+
+// CreateAnchorReceipts returns the anchor records of the directory block, from the
+// anchor index.  Only records with a valid signature that anchor the block's own
+// KeyMR are included.
+func CreateAnchorReceipts(dbo interfaces.DBOverlaySimple, dBlock interfaces.IDirectoryBlock) ([]*AnchorReceipt, error) {
+	keyMR := dBlock.DatabasePrimaryIndex().String()
+
+	entries, err := dbo.FetchAnchorEntries(dBlock.GetDatabaseHeight())
+	if err != nil {
+		return nil, err
+	}
+	var answer []*AnchorReceipt
+	for _, entry := range entries {
+		ar, valid, err := anchor.UnmarshalAndValidateAnchorEntryAnyVersion(entry, databaseOverlay.AnchorSigPublicKeys)
+		if err != nil || !valid || ar == nil {
+			continue
+		}
+		if ar.KeyMR != keyMR {
+			continue
+		}
+		a, err := NewAnchorReceipt(entry, ar)
+		if err != nil {
+			return nil, err
+		}
+		answer = append(answer, a)
+	}
+	return answer, nil
+}
+
+func NewAnchorReceipt(entry interfaces.IEBEntry, ar *anchor.AnchorRecord) (*AnchorReceipt, error) {
+	raw, err := entry.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	a := new(AnchorReceipt)
+	a.Entry = new(JSON)
+	a.Entry.Raw = hex.EncodeToString(raw)
+	a.Entry.EntryHash = entry.GetHash().String()
+	a.Bitcoin = ar.Bitcoin
+	a.Ethereum = ar.Ethereum
+	return a, nil
+}
+
+// Verify checks the anchor entry is in the anchor chain, is signed by one of the
+// anchor keys, and anchors the directory block KeyMR with the transaction the
+// receipt claims.
+func (a *AnchorReceipt) Verify(dBlockKeyMR interfaces.IHash, anchorKeys []interfaces.Verifier) error {
+	if a.Entry == nil || a.Entry.Raw == "" {
+		return fmt.Errorf("Anchor has no entry")
+	}
+	if a.Bitcoin == nil && a.Ethereum == nil {
+		return fmt.Errorf("Anchor has neither a Bitcoin nor an Ethereum transaction")
+	}
+	entry, err := decodeEntry(a.Entry)
+	if err != nil {
+		return err
+	}
+	if entry.GetChainID().String() != databaseOverlay.AnchorBlockID {
+		return fmt.Errorf("Anchor entry %s is not in the anchor chain", a.Entry.EntryHash)
+	}
+
+	ar, valid, err := anchor.UnmarshalAndValidateAnchorEntryAnyVersion(entry, anchorKeys)
+	if err != nil {
+		return err
+	}
+	if !valid || ar == nil {
+		return fmt.Errorf("Anchor entry %s is not signed by an anchor key", a.Entry.EntryHash)
+	}
+	if ar.KeyMR != dBlockKeyMR.String() {
+		return fmt.Errorf("Anchor entry %s anchors %s, not %s", a.Entry.EntryHash, ar.KeyMR, dBlockKeyMR.String())
+	}
+	if a.Bitcoin != nil && (ar.Bitcoin == nil || *ar.Bitcoin != *a.Bitcoin) {
+		return fmt.Errorf("Anchor entry %s does not record the Bitcoin transaction %s", a.Entry.EntryHash, a.Bitcoin.TXID)
+	}
+	if a.Ethereum != nil && (ar.Ethereum == nil || *ar.Ethereum != *a.Ethereum) {
+		return fmt.Errorf("Anchor entry %s does not record the Ethereum transaction %s", a.Entry.EntryHash, a.Ethereum.TXID)
+	}
+	return nil
+}
+
+// decodeEntry unmarshals the raw entry, and checks it hashes to the entry hash
+func decodeEntry(j *JSON) (interfaces.IEBEntry, error) {
+	raw, err := hex.DecodeString(j.Raw)
+	if err != nil {
+		return nil, err
+	}
+	entry := entryBlock.NewEntry()
+	err = entry.UnmarshalBinary(raw)
+	if err != nil {
+		return nil, err
+	}
+	if entry.GetHash().String() != j.EntryHash {
+		return nil, fmt.Errorf("Entry hashes to %s, not %s", entry.GetHash().String(), j.EntryHash)
+	}
+	return entry, nil
+}
+
+func (a *AnchorReceipt) IsSameAs(b *AnchorReceipt) bool {
+	if b == nil {
+		return false
+	}
+	if (a.Entry == nil) != (b.Entry == nil) || (a.Entry != nil && a.Entry.IsSameAs(b.Entry) == false) {
+		return false
+	}
+	if (a.Bitcoin == nil) != (b.Bitcoin == nil) || (a.Bitcoin != nil && *a.Bitcoin != *b.Bitcoin) {
+		return false
+	}
+	if (a.Ethereum == nil) != (b.Ethereum == nil) || (a.Ethereum != nil && *a.Ethereum != *b.Ethereum) {
+		return false
+	}
+	return true
+}
@@ -5,6 +5,7 @@
 package receipts
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 
@@ -20,6 +21,9 @@ type Receipt struct {
 	DirectoryBlockKeyMR    *primitives.Hash         `json:"directoryblockkeymr,omitempty"`
 	BitcoinTransactionHash *primitives.Hash         `json:"bitcointransactionhash,omitempty"`
 	BitcoinBlockHash       *primitives.Hash         `json:"bitcoinblockhash,omitempty"`
+
+	// The anchor records of the directory block, with the external transactions
+	Anchors []*AnchorReceipt `json:"anchors,omitempty"`
 }
 
 func (e *Receipt) TrimReceipt() {
@@ -83,7 +87,7 @@ func (e *Receipt) Validate() error {
 				right = node.Right
 			}
 		}
-		if left.IsSameAs(currentEntry) == false && left.IsSameAs(currentEntry) {
+		if left.IsSameAs(currentEntry) == false && right.IsSameAs(currentEntry) == false {
 			return fmt.Errorf("Entry %v not found in node %v/%v", currentEntry, i, len(e.MerkleBranch))
 		}
 		top := primitives.HashMerkleBranches(left, right)
@@ -202,6 +206,15 @@ func (e *Receipt) IsSameAs(r *Receipt) bool {
 		}
 	}
 
+	if len(e.Anchors) != len(r.Anchors) {
+		return false
+	}
+	for i := range e.Anchors {
+		if e.Anchors[i].IsSameAs(r.Anchors[i]) == false {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -291,6 +304,19 @@ func CreateReceipt(dbo interfaces.DBOverlaySimple, entryID interfaces.IHash) (*R
 	receipt.Entry = new(JSON)
 	receipt.Entry.EntryHash = entryID.String()
 
+	// The entry itself, so the receipt can be checked offline.  Pruned entries have none.
+	entry, err := dbo.FetchEntry(entryID)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil {
+		raw, err := entry.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		receipt.Entry.Raw = hex.EncodeToString(raw)
+	}
+
 	//EBlock
 
 	hash, err := dbo.FetchIncludedIn(entryID)
@@ -391,6 +417,11 @@ func CreateReceipt(dbo interfaces.DBOverlaySimple, entryID interfaces.IHash) (*R
 		receipt.BitcoinBlockHash = dbi.BTCBlockHash.(*primitives.Hash)
 	}
 
+	receipt.Anchors, err = CreateAnchorReceipts(dbo, dBlock)
+	if err != nil {
+		return nil, err
+	}
+
 	return receipt, nil
 }
 
@@ -0,0 +1,67 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package receipts
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// VerifyReceipt checks a receipt without a database: the entry hashes to its entry
+// hash, the merkle branch leads from the entry to the directory block KeyMR, and
+// every anchor is signed by one of anchorKeys and anchors that KeyMR.  A receipt
+// without anchors only proves the entry is in the directory block.
+func VerifyReceipt(r *Receipt, anchorKeys []interfaces.Verifier) error {
+	err := r.Validate()
+	if err != nil {
+		return err
+	}
+	if r.Entry.Raw != "" {
+		_, err = decodeEntry(r.Entry)
+		if err != nil {
+			return err
+		}
+	}
+	for i, a := range r.Anchors {
+		if a == nil {
+			return fmt.Errorf("Anchor %v/%v is empty", i, len(r.Anchors))
+		}
+		err = a.Verify(r.DirectoryBlockKeyMR, anchorKeys)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyReceiptJSON decodes and verifies a receipt.  It takes the receipt itself, or
+// the response of the receipt API call, with or without the JSON-RPC envelope.
+func VerifyReceiptJSON(data []byte, anchorKeys []interfaces.Verifier) (*Receipt, error) {
+	var envelope struct {
+		Receipt *Receipt `json:"receipt"`
+		Result  *struct {
+			Receipt *Receipt `json:"receipt"`
+		} `json:"result"`
+	}
+	err := json.Unmarshal(data, &envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := envelope.Receipt
+	if receipt == nil && envelope.Result != nil {
+		receipt = envelope.Result.Receipt
+	}
+	if receipt == nil {
+		receipt = new(Receipt)
+		err = json.Unmarshal(data, receipt)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return receipt, VerifyReceipt(receipt, anchorKeys)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/FactomProject/factomd/database/boltdb"
 	"github.com/FactomProject/factomd/database/mapdb"
 )
@@ -30,9 +31,13 @@ func (s *State) SetupCrossBootReplay(path string) {
 	}
 	// Map Database uses a map replay filter
 	if path != "Map" {
-		path = filepath.Join(s.BoltDBPath, s.Network, "crossreplay.db")
+		name := "crossreplay.db"
+		if s.dbCipher != nil {
+			name = "crossreplay_encrypted.db"
+		}
+		path = filepath.Join(s.BoltDBPath, s.Network, name)
 	}
-	s.CrossReplay = NewCrossReplayFilter(path)
+	s.CrossReplay = NewCrossReplayFilterWithCipher(path, s.dbCipher)
 	// This thread will terminate itself
 	go s.CrossReplay.Run()
 }
@@ -58,10 +63,20 @@ type CrossReplayFilter struct {
 	currentSaltCache map[[8]byte]bool
 	oldSaltCache     map[[8]byte]bool
 	db               interfaces.IDatabase
+	// cipher hides the salts of an encrypted database: they are saved encrypted, under
+	// a hash of the salt
+	cipher DataCipher
 }
 
 func NewCrossReplayFilter(path string) *CrossReplayFilter {
+	return NewCrossReplayFilterWithCipher(path, nil)
+}
+
+// NewCrossReplayFilterWithCipher opens a replay filter that saves the salts with the
+// cipher of the database, if it is not nil
+func NewCrossReplayFilterWithCipher(path string, cipher DataCipher) *CrossReplayFilter {
 	c := new(CrossReplayFilter)
+	c.cipher = cipher
 	if path == "" || strings.ToLower(path) == "map" {
 		c.db = new(mapdb.MapDB)
 	} else {
@@ -86,6 +101,20 @@ func NewCrossReplayFilter(path string) *CrossReplayFilter {
 
 // loadOldSalts loads the db into memory, and clears the db
 func (c *CrossReplayFilter) loadOldSalts() {
+	if c.cipher != nil {
+		values, _, _ := c.db.GetAll(saltBucket, new(primitives.ByteSlice))
+		for _, v := range values {
+			salt, err := c.cipher.Decrypt(v.(*primitives.ByteSlice).Bytes)
+			if err != nil || len(salt) != 8 {
+				continue
+			}
+			var s [8]byte
+			copy(s[:], salt)
+			c.oldSaltCache[s] = true
+		}
+		c.db.Clear(saltBucket)
+		return
+	}
 	keys, _ := c.db.ListAllKeys(saltBucket)
 	for _, k := range keys {
 		var s [8]byte
@@ -108,6 +137,14 @@ func (c *CrossReplayFilter) AddSalt(height uint32, salt [8]byte) error {
 		return nil
 	}
 
+	if c.cipher != nil {
+		data, err := c.cipher.Encrypt(salt[:])
+		if err != nil {
+			return err
+		}
+		return c.db.Put(saltBucket, c.saltKey(salt), &primitives.ByteSlice{Bytes: data})
+	}
+
 	// Need something to marshal... the data is no longer used
 	m := MarshalableUint32(0)
 	err := c.db.Put(saltBucket, salt[:], &m)
@@ -118,6 +155,14 @@ func (c *CrossReplayFilter) AddSalt(height uint32, salt [8]byte) error {
 	return nil
 }
 
+// saltKey is the key a salt is saved under
+func (c *CrossReplayFilter) saltKey(salt [8]byte) []byte {
+	if c.cipher != nil {
+		return c.cipher.HashKey(salt[:])
+	}
+	return salt[:]
+}
+
 // ExistOldSalt checks to see if the salt existed on the previous boot
 func (c *CrossReplayFilter) ExistOldSalt(salt [8]byte) bool {
 	_, ok := c.oldSaltCache[salt]
@@ -127,7 +172,7 @@ func (c *CrossReplayFilter) ExistOldSalt(salt [8]byte) bool {
 // Exists check if the hash is in the replay filter, and if it encounters a db error, it will report false
 //
 func (c *CrossReplayFilter) ExistSalt(salt [8]byte) (bool, error) {
-	return c.db.DoesKeyExist(saltBucket, salt[:])
+	return c.db.DoesKeyExist(saltBucket, c.saltKey(salt))
 }
 
 // Run is a simple loop that ensures we discard old data we do not need.
@@ -1,10 +1,16 @@
 package state_test
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/FactomProject/factomd/common/primitives/random"
+	"github.com/FactomProject/factomd/database/boltdb"
+	"github.com/FactomProject/factomd/database/securedb"
 	. "github.com/FactomProject/factomd/state"
 )
 
@@ -50,3 +56,46 @@ func testMarshal(u uint32) error {
 	}
 	return nil
 }
+
+func TestCrossReplayFilterCipher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crossreplay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "crossreplay.db")
+
+	cipher, err := securedb.NewEncryptedDB("", "Map", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	salt := [8]byte{1, 2, 3, 4, 5, 6, 7, 8}
+
+	c := NewCrossReplayFilterWithCipher(path, cipher)
+	err = c.AddSalt(1, salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := c.ExistSalt(salt); !ok || err != nil {
+		t.Errorf("The salt was not saved (%v)", err)
+	}
+	c.Close()
+
+	// The salt is not stored in the clear
+	db := boltdb.NewBoltDB(nil, path)
+	keys, err := db.ListAllKeys([]byte("AllSalts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || bytes.Equal(keys[0], salt[:]) {
+		t.Errorf("Got the keys %x", keys)
+	}
+	db.Close()
+
+	// It is known on the next boot
+	c = NewCrossReplayFilterWithCipher(path, cipher)
+	defer c.Close()
+	if !c.ExistOldSalt(salt) {
+		t.Error("The salt of the previous boot was lost")
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/FactomProject/factomd/common/directoryBlock"
 	"github.com/FactomProject/factomd/common/entryBlock"
 	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/eventLog"
 	"github.com/FactomProject/factomd/common/factoid"
 	"github.com/FactomProject/factomd/common/globals"
 	"github.com/FactomProject/factomd/common/identity"
@@ -26,6 +27,7 @@ import (
 	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/FactomProject/factomd/database/databaseOverlay"
 	"github.com/FactomProject/factomd/log"
+	"github.com/FactomProject/factomd/wsapi"
 )
 
 var _ = hex.EncodeToString
@@ -1171,6 +1173,9 @@ func (list *DBStateList) ProcessBlocks(d *DBState) (progress bool) {
 	if list.State.DBFinished {
 		list.State.Balancehash = fs.GetBalanceHash(false)
 	}
+	if list.State.ReplayCheck != nil {
+		list.State.ReplayCheck.Check(dbht, d.DirectoryBlock.GetKeyMR(), fs.GetBalanceHash(false))
+	}
 
 	// Make the current exchange rate whatever we had in the previous block.
 	// UNLESS there was a FER entry processed during this block  changeheight will be left at 1 on a change block
@@ -1453,6 +1458,9 @@ func (list *DBStateList) SaveDBStateToDB(d *DBState) (progress bool) {
 		}
 	}
 
+	// Entry blocks we saved, reported to the API subscribers once the batch is written
+	var savedEBlocks []interfaces.IEntryBlock
+
 	// Info from DBState
 	for _, eb := range d.EntryBlocks {
 		keymr, err := eb.KeyMR()
@@ -1464,6 +1472,7 @@ func (list *DBStateList) SaveDBStateToDB(d *DBState) (progress bool) {
 			if err := list.State.DB.ProcessEBlockMultiBatch(eb, true); err != nil {
 				panic(err.Error())
 			}
+			savedEBlocks = append(savedEBlocks, eb)
 		} else {
 			list.State.Logf("error", "Error saving eblock from dbstate, eblock not allowed")
 		}
@@ -1492,6 +1501,7 @@ func (list *DBStateList) SaveDBStateToDB(d *DBState) (progress bool) {
 				if err := list.State.DB.ProcessEBlockMultiBatch(eb, true); err != nil {
 					panic(err.Error())
 				}
+				savedEBlocks = append(savedEBlocks, eb)
 
 				for _, e := range eb.GetBody().GetEBEntries() {
 					if _, ok := allowedEntries[e.Fixed()]; ok {
@@ -1571,6 +1581,10 @@ func (list *DBStateList) SaveDBStateToDB(d *DBState) (progress bool) {
 	progress = true
 	d.ReadyToSave = false
 	d.Saved = true
+	if list.State.EventLog != nil {
+		list.State.LogEvent(&eventLog.Event{Type: eventLog.DBStateSaved, DBHeight: uint32(dbheight), Minute: list.State.CurrentMinute, VM: -1, Height: -1,
+			Hash: d.DirectoryBlock.GetKeyMR().Fixed()})
+	}
 
 	// Now that we have saved the perm balances, we can clear the api hashmaps that held the differences
 	// between the actual saved block prior, and this saved block.  If you are looking for balances of
@@ -1585,12 +1599,51 @@ func (list *DBStateList) SaveDBStateToDB(d *DBState) (progress bool) {
 	list.State.ECBalancesPapi = nil
 	list.State.ECBalancesPMutex.Unlock()
 
+	list.PublishSavedDBState(d, savedEBlocks)
+
 	return
 }
 
+// PublishSavedDBState feeds the API subscriptions with the blocks of a DBState we just
+// wrote to the database.  Notifications are queued without blocking, so a slow client
+// cannot hold up consensus.
+func (list *DBStateList) PublishSavedDBState(d *DBState, eblocks []interfaces.IEntryBlock) {
+	if !wsapi.HasSubscribers() {
+		return
+	}
+	node := list.State.FactomNodeName
+	dbheight := d.DirectoryBlock.GetHeader().GetDBHeight()
+
+	wsapi.PublishDBlock(node, d.DirectoryBlock)
+
+	for _, eb := range eblocks {
+		wsapi.PublishEntryBlock(node, eb)
+		for _, e := range eb.GetEntryHashes() {
+			if !e.IsMinuteMarker() {
+				wsapi.PublishAckStatus(node, e, constants.AckStatusDBlockConfirmed)
+			}
+		}
+	}
+
+	for _, fct := range d.FactoidBlock.GetTransactions() {
+		wsapi.PublishFactoidTransaction(node, fct, dbheight)
+		wsapi.PublishAckStatus(node, fct.GetSigHash(), constants.AckStatusDBlockConfirmed)
+	}
+
+	for _, en := range d.EntryCreditBlock.GetEntries() {
+		switch en.ECID() {
+		case constants.ECIDChainCommit, constants.ECIDEntryCommit:
+			wsapi.PublishAckStatus(node, en.GetSigHash(), constants.AckStatusDBlockConfirmed)
+		}
+	}
+}
+
 func (list *DBStateList) UpdateState() (progress bool) {
 	list.Catchup(false)
 
+	// We are between blocks, so this is where a snapshot can start
+	list.State.StartRequestedSnapshot(list.SavedHeight)
+
 	saved := 0
 	for i, d := range list.DBStates {
 		//fmt.Printf("dddd %20s %10s --- %10s %10v %10s %10v \n", "DBStateList Update", list.State.FactomNodeName, "Looking at", i, "DBHeight", list.Base+uint32(i))
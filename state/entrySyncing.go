@@ -29,8 +29,12 @@ func has(s *State, entry interfaces.IHash) bool {
 			return false
 		}
 
-		entry, err2 := s.DB.FetchEntry(entry)
-		if err2 != nil || entry == nil {
+		e, err2 := s.DB.FetchEntry(entry)
+		if err2 != nil || e == nil {
+			// A pruned node had the entry, and does not want it back
+			if pruned, _ := s.DB.IsEntryPruned(entry); pruned {
+				return true
+			}
 			panic("Should not happen;  key exists but not entry")
 			return false
 		}
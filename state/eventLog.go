@@ -0,0 +1,47 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/FactomProject/factomd/common/eventLog"
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// LogEvent appends an event to the consensus event log of the node.  It does nothing
+// if the node does not log its events.
+func (s *State) LogEvent(e *eventLog.Event) {
+	if s.EventLog == nil {
+		return
+	}
+	if err := s.EventLog.Log(e); err != nil && err != eventLog.ErrClosed {
+		fmt.Fprintln(os.Stderr, s.FactomNodeName, "stops logging events:", err)
+	}
+}
+
+// LogMsgEvent logs an event about a message.  vm and height are the place of the
+// message in the process list of dbheight, -1 if it has none.  The minute is the
+// caller's: CurrentMinute belongs to the state goroutine, callers off it pass 0.
+func (s *State) LogMsgEvent(t eventLog.EventType, dbheight uint32, minute int, vm int, height int, msg interfaces.IMsg, note string) {
+	if s.EventLog == nil {
+		return
+	}
+	e := new(eventLog.Event)
+	e.Type = t
+	e.DBHeight = dbheight
+	e.Minute = minute
+	e.VM = vm
+	e.Height = height
+	e.Note = note
+	if msg != nil {
+		e.MsgType = msg.Type()
+		if hash := msg.GetMsgHash(); hash != nil {
+			e.Hash = hash.Fixed()
+		}
+	}
+	s.LogEvent(e)
+}
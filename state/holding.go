@@ -0,0 +1,166 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+)
+
+// EvictionTimeout bounds the wait for the node to apply an eviction from holding
+var EvictionTimeout = 5 * time.Second
+
+type holdingEviction struct {
+	hash    [32]byte
+	requeue bool
+	done    chan error
+}
+
+// LoadHoldingList returns the messages in holding, the oldest first.  The list is
+// rebuilt about once a second.
+func (s *State) LoadHoldingList() []interfaces.HeldMessage {
+	s.HoldingMutex.RLock()
+	defer s.HoldingMutex.RUnlock()
+	return s.HoldingList
+}
+
+// EvictHeldMessage removes a message from holding.  If requeue is set, the message goes
+// back to the input queue, to be validated and executed again.  The holding queue
+// belongs to the ValidatorLoop, so the eviction is done there, and this waits for it.
+func (s *State) EvictHeldMessage(hash interfaces.IHash, requeue bool) error {
+	e := new(holdingEviction)
+	e.hash = hash.Fixed()
+	e.requeue = requeue
+	e.done = make(chan error, 1)
+
+	select {
+	case s.holdingEvictions <- e:
+	default:
+		return fmt.Errorf("Too many evictions pending")
+	}
+	select {
+	case err := <-e.done:
+		return err
+	case <-time.After(EvictionTimeout):
+		return fmt.Errorf("The node did not evict the message in %v", EvictionTimeout)
+	}
+}
+
+// evictHeldMessages applies the evictions asked for
+func (s *State) evictHeldMessages() {
+	for {
+		select {
+		case e := <-s.holdingEvictions:
+			msg, ok := s.Holding[e.hash]
+			if !ok {
+				e.done <- fmt.Errorf("Message %x is not in holding", e.hash)
+				continue
+			}
+			delete(s.Holding, e.hash)
+			delete(s.holdingSince, e.hash)
+			TotalHoldingQueueOutputs.Inc()
+			s.HoldingLast = 0 // Show the change in the next list
+			if e.requeue {
+				s.LogMessage("executeMsg", "requeue from holding by the API", msg)
+				s.inMsgQueue.Enqueue(msg)
+			} else {
+				s.LogMessage("executeMsg", "evict from holding by the API", msg)
+			}
+			e.done <- nil
+		default:
+			return
+		}
+	}
+}
+
+// describeHolding lists the messages in holding, and keeps track of when they were
+// first seen there
+func (s *State) describeHolding(now int64) []interfaces.HeldMessage {
+	since := make(map[[32]byte]int64, len(s.Holding))
+	list := make([]interfaces.HeldMessage, 0, len(s.Holding))
+	for k, msg := range s.Holding {
+		if msg == nil {
+			continue
+		}
+		first, ok := s.holdingSince[k]
+		if !ok {
+			first = now
+		}
+		since[k] = first
+
+		h := interfaces.HeldMessage{}
+		h.Hash = fmt.Sprintf("%x", k)
+		h.Type = constants.MessageName(msg.Type())
+		h.VM = msg.GetVMIndex()
+		if ts := msg.GetTimestamp(); ts != nil {
+			h.Timestamp = ts.GetTimeMilli()
+		}
+		h.Age = now - first
+		h.Reason = s.holdingReason(msg)
+		h.ResendCount = msg.GetResendCnt()
+		switch m := msg.(type) {
+		case *messages.CommitChainMsg:
+			h.EntryHash = m.CommitChain.EntryHash.String()
+		case *messages.CommitEntryMsg:
+			h.EntryHash = m.CommitEntry.EntryHash.String()
+		case *messages.RevealEntryMsg:
+			h.EntryHash = m.Entry.GetHash().String()
+		}
+		list = append(list, h)
+	}
+	s.holdingSince = since
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Age != list[j].Age {
+			return list[i].Age > list[j].Age
+		}
+		return list[i].Hash < list[j].Hash
+	})
+	return list
+}
+
+// holdingReason tells what a message in holding waits for
+func (s *State) holdingReason(msg interfaces.IMsg) string {
+	var dbheight uint32
+	future := false
+	switch m := msg.(type) {
+	case *messages.Ack:
+		dbheight = m.DBHeight
+	case *messages.EOM:
+		dbheight = m.DBHeight
+		future = m.DBHeight == s.LLeaderHeight && int(m.Minute) > s.CurrentMinute
+	case *messages.DirectoryBlockSignature:
+		dbheight = m.DBHeight
+	case *messages.DBStateMsg:
+		dbheight = m.DirectoryBlock.GetHeader().GetDBHeight()
+	}
+	if future || dbheight > s.LLeaderHeight {
+		return interfaces.HoldFutureHeight
+	}
+	if msg.Type() == constants.ACK_MSG {
+		return interfaces.HoldWaitingMessage
+	}
+
+	acked := s.Acks[msg.GetMsgHash().Fixed()] != nil
+	switch m := msg.(type) {
+	case *messages.RevealEntryMsg:
+		if s.Commits.Get(m.Entry.GetHash().Fixed()) == nil {
+			return interfaces.HoldWaitingCommit
+		}
+	case *messages.CommitChainMsg, *messages.CommitEntryMsg:
+		if acked {
+			return interfaces.HoldWaitingEntry
+		}
+	}
+	if !acked {
+		return interfaces.HoldWaitingAck
+	}
+	return interfaces.HoldWaitingProcess
+}
@@ -0,0 +1,227 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// A deterministic replay drives a node from its journal alone.  The messages are
+// processed one at a time, in the order of the journal, and the node runs until it has
+// nothing left to do before the next one.  The clock of the node is virtual: it is the
+// time the message was processed at when the journal was recorded.  Anything the node
+// sends, to the network, to the elections or to its own input queues, is dropped, since
+// the journal holds the messages it processed when it was recorded, the outcome of the
+// elections included.
+
+// ReplayCheckpoint is the directory block a node builds at a height, and its balance
+// hash once the block is applied
+type ReplayCheckpoint struct {
+	DBHeight    uint32
+	KeyMR       interfaces.IHash
+	BalanceHash interfaces.IHash // nil when it is not checked
+}
+
+func (c *ReplayCheckpoint) String() string {
+	balanceHash := "-"
+	if c.BalanceHash != nil {
+		balanceHash = c.BalanceHash.String()
+	}
+	return fmt.Sprintf("%d %s %s", c.DBHeight, c.KeyMR.String(), balanceHash)
+}
+
+// ReadReplayCheckpoints reads a list of checkpoints, one per line: the height, the key
+// MR of the directory block and the balance hash, or - to skip it.  Empty lines and
+// lines starting with # are ignored.
+func ReadReplayCheckpoints(r io.Reader) ([]*ReplayCheckpoint, error) {
+	list := []*ReplayCheckpoint{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("Line %d: expected a height, a key MR and a balance hash", n)
+		}
+
+		c := new(ReplayCheckpoint)
+		height, err := strconv.ParseUint(fields[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: invalid height %q", n, fields[0])
+		}
+		c.DBHeight = uint32(height)
+		c.KeyMR, err = primitives.HexToHash(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: invalid key MR %q", n, fields[1])
+		}
+		if len(fields) == 3 && fields[2] != "-" {
+			c.BalanceHash, err = primitives.HexToHash(fields[2])
+			if err != nil {
+				return nil, fmt.Errorf("Line %d: invalid balance hash %q", n, fields[2])
+			}
+		}
+		list = append(list, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// WriteReplayCheckpoints writes a list of checkpoints the way ReadReplayCheckpoints
+// reads them
+func WriteReplayCheckpoints(w io.Writer, list []*ReplayCheckpoint) error {
+	for _, c := range list {
+		if _, err := fmt.Fprintln(w, c.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReplayDivergence is the first block of a replay that is not the expected one
+type ReplayDivergence struct {
+	Expected *ReplayCheckpoint
+	Got      *ReplayCheckpoint
+}
+
+func (d *ReplayDivergence) Error() string {
+	if !d.Got.KeyMR.IsSameAs(d.Expected.KeyMR) {
+		return fmt.Sprintf("Replay diverged at height %d: directory block key MR is %v, expected %v",
+			d.Got.DBHeight, d.Got.KeyMR, d.Expected.KeyMR)
+	}
+	return fmt.Sprintf("Replay diverged at height %d: balance hash is %v, expected %v",
+		d.Got.DBHeight, d.Got.BalanceHash, d.Expected.BalanceHash)
+}
+
+// ReplayCheck records the blocks a replay builds, and compares them to the expected
+// ones.  The heights that are not expected are recorded but not checked.
+type ReplayCheck struct {
+	mutex      sync.Mutex
+	expected   map[uint32]*ReplayCheckpoint
+	reached    []*ReplayCheckpoint
+	checked    int
+	divergence *ReplayDivergence
+}
+
+func NewReplayCheck(expected []*ReplayCheckpoint) *ReplayCheck {
+	c := new(ReplayCheck)
+	c.expected = make(map[uint32]*ReplayCheckpoint, len(expected))
+	for _, e := range expected {
+		c.expected[e.DBHeight] = e
+	}
+	return c
+}
+
+// Check records the block built at a height
+func (c *ReplayCheck) Check(dbheight uint32, keyMR interfaces.IHash, balanceHash interfaces.IHash) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, r := range c.reached {
+		if r.DBHeight == dbheight {
+			return
+		}
+	}
+	got := new(ReplayCheckpoint)
+	got.DBHeight = dbheight
+	got.KeyMR = keyMR.Copy()
+	got.BalanceHash = balanceHash.Copy()
+	c.reached = append(c.reached, got)
+
+	e, ok := c.expected[dbheight]
+	if !ok {
+		return
+	}
+	c.checked++
+	if c.divergence == nil && (!got.KeyMR.IsSameAs(e.KeyMR) || (e.BalanceHash != nil && !got.BalanceHash.IsSameAs(e.BalanceHash))) {
+		c.divergence = &ReplayDivergence{Expected: e, Got: got}
+	}
+}
+
+// Divergence returns the first block that was not the expected one, or nil
+func (c *ReplayCheck) Divergence() *ReplayDivergence {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.divergence
+}
+
+// Reached returns the blocks built so far, in the order they were built
+func (c *ReplayCheck) Reached() []*ReplayCheckpoint {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]*ReplayCheckpoint{}, c.reached...)
+}
+
+// Checked returns the number of expected blocks built so far, and the number of
+// expected blocks
+func (c *ReplayCheck) Checked() (int, int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.checked, len(c.expected)
+}
+
+// ReplayMessage processes one message of a journal with the virtual clock at the time
+// it was recorded, and runs the node until it has nothing left to do.  The virtual
+// clock never goes back.  It must not run along with the ValidatorLoop.
+func (s *State) ReplayMessage(msg interfaces.IMsg, timestamp interfaces.Timestamp) {
+	if s.ReplayTimestamp == nil || timestamp.GetTimeMilli() > s.ReplayTimestamp.GetTimeMilli() {
+		s.ReplayTimestamp = timestamp
+	}
+
+	if msg.Type() == constants.ACK_MSG {
+		s.ackQueue <- msg
+	} else {
+		s.msgQueue <- msg
+	}
+
+	progress := true
+	for i := 0; i < 10 && progress; i++ {
+		progress = false
+		for s.Process() {
+			progress = true
+		}
+		for s.UpdateState() {
+			progress = true
+		}
+		s.dropReplayOutput()
+	}
+}
+
+// dropReplayOutput drops the messages the node sent during a replay
+func (s *State) dropReplayOutput() {
+	for _, q := range []interfaces.IQueue{s.networkOutMsgQueue, s.inMsgQueue, s.inMsgQueue2, s.electionsQueue} {
+		for q.Length() > 0 {
+			q.Dequeue()
+		}
+	}
+	for len(s.networkInvalidMsgQueue) > 0 {
+		<-s.networkInvalidMsgQueue
+	}
+}
+
+// mapOrder returns the sorted keys of a map of messages.  The order of a map is random,
+// so a deterministic replay goes through the messages in the order of their keys.
+func (s *State) mapOrder(m map[[32]byte]interfaces.IMsg) [][32]byte {
+	keys := make([][32]byte, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i][:], keys[j][:]) < 0 })
+	return keys
+}
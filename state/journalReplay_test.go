@@ -0,0 +1,70 @@
+package state_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+)
+
+func TestReplayCheckpoints(t *testing.T) {
+	keyMR := primitives.Sha([]byte("keymr"))
+	balanceHash := primitives.Sha([]byte("balances"))
+	list := []*ReplayCheckpoint{
+		{DBHeight: 1, KeyMR: keyMR, BalanceHash: balanceHash},
+		{DBHeight: 2, KeyMR: keyMR},
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("# height keymr balancehash\n\n")
+	if err := WriteReplayCheckpoints(buf, list); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadReplayCheckpoints(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != 2 || read[0].DBHeight != 1 || !read[0].KeyMR.IsSameAs(keyMR) ||
+		!read[0].BalanceHash.IsSameAs(balanceHash) || read[1].BalanceHash != nil {
+		t.Errorf("Got %v, expected %v", read, list)
+	}
+
+	for _, bad := range []string{"1", "x " + keyMR.String(), "1 1234", "1 " + keyMR.String() + " 1234"} {
+		if _, err := ReadReplayCheckpoints(strings.NewReader(bad)); err == nil {
+			t.Errorf("Expected an error for %q", bad)
+		}
+	}
+}
+
+func TestReplayCheck(t *testing.T) {
+	keyMR := primitives.Sha([]byte("keymr"))
+	balanceHash := primitives.Sha([]byte("balances"))
+	other := primitives.Sha([]byte("other"))
+
+	c := NewReplayCheck([]*ReplayCheckpoint{
+		{DBHeight: 1, KeyMR: keyMR},
+		{DBHeight: 2, KeyMR: keyMR, BalanceHash: balanceHash},
+		{DBHeight: 3, KeyMR: keyMR},
+	})
+	c.Check(0, other, other)
+	c.Check(1, keyMR, other)
+	if c.Divergence() != nil {
+		t.Errorf("Unexpected divergence %v", c.Divergence())
+	}
+	c.Check(2, keyMR, other)
+	c.Check(2, keyMR, balanceHash)
+	c.Check(3, other, balanceHash)
+
+	d := c.Divergence()
+	if d == nil || d.Got.DBHeight != 2 || !strings.Contains(d.Error(), "balance hash") {
+		t.Errorf("Got divergence %v, expected the balance hash of height 2", d)
+	}
+	if checked, total := c.Checked(); checked != 3 || total != 3 {
+		t.Errorf("Checked %d of %d blocks, expected 3 of 3", checked, total)
+	}
+	if len(c.Reached()) != 4 {
+		t.Errorf("Reached %d blocks, expected 4", len(c.Reached()))
+	}
+}
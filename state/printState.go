@@ -40,13 +40,22 @@ func PrintState(state *State) {
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "LogPath", state.LogPath)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "LdbPath", state.LdbPath)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "BoltDBPath", state.BoltDBPath)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "BadgerDBPath", state.BadgerDBPath)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "DBPasswordSource", state.DBPasswordSource)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "LogLevel", state.LogLevel)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "ConsoleLogLevel", state.ConsoleLogLevel)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "NodeMode", state.NodeMode)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "PruneDepth", state.PruneDepth)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "DBType", state.DBType)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "CloneDBType", state.CloneDBType)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "ExportData", state.ExportData)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "ExportDataSubpath", state.ExportDataSubpath)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "AddressIndex", state.AddressIndex)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "ExtIDIndex", state.ExtIDIndex)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "BalanceHistory", state.BalanceHistory)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "ChainInfo", state.ChainInfo)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "AnchorSigningKeys", state.AnchorSigningKeys)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "SnapshotPath", state.SnapshotPath)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "LocalServerPrivKey", state.LocalServerPrivKey)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "DirectoryBlockInSeconds", state.DirectoryBlockInSeconds)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "PortNumber", state.PortNumber)
@@ -70,6 +79,8 @@ func PrintState(state *State) {
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "CustomNetworkPort", state.CustomNetworkPort)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "CustomSeedURL", state.CustomSeedURL)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "CustomSpecialPeers", state.CustomSpecialPeers)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "P2PEncryption", state.P2PEncryption)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "P2PKeyFile", state.P2PKeyFile)
 	str = fmt.Sprintf("%s %35s = %+v(%s)\n", str, "CustomNetworkID", state.CustomNetworkID, globals.Params.CustomNetName)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "IdentityChainID", state.IdentityChainID)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "Identities", state.IdentityControl.GetIdentities())
@@ -115,6 +126,13 @@ func PrintState(state *State) {
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "factomdTLSKeyFile", state.factomdTLSKeyFile)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "factomdTLSCertFile", state.factomdTLSCertFile)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "FactomdLocations", state.FactomdLocations)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "APIReadRate", state.APIReadRate)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "APIReadBurst", state.APIReadBurst)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "APISubmitRate", state.APISubmitRate)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "APISubmitBurst", state.APISubmitBurst)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "APIUsersFile", state.APIUsersFile)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "GRPCEnabled", state.GRPCEnabled)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "GRPCPort", state.GRPCPort)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "StartDelay", state.StartDelay)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "StartDelayLimit", state.StartDelayLimit)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "RunLeader", state.RunLeader)
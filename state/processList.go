@@ -17,10 +17,12 @@ import (
 	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/directoryBlock"
 	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/eventLog"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/FactomProject/factomd/util/atomic"
+	"github.com/FactomProject/factomd/wsapi"
 
 	//"github.com/FactomProject/factomd/database/databaseOverlay"
 
@@ -106,6 +108,10 @@ type ProcessList struct {
 	asks chan askRef   // Requests to ask for missing messages
 	adds chan plRef    // notices of slots filled in the process list
 	done chan struct{} // Notice that this DBHeight is done
+
+	// Copy of the asks makeMMRs waits on, for the debug API
+	outstandingAsks map[plRef]*interfaces.MissingMsgAsk
+	asksMutex       sync.Mutex
 }
 
 var _ interfaces.IProcessList = (*ProcessList)(nil)
@@ -604,12 +610,14 @@ func (p *ProcessList) makeMMRs(s interfaces.IState, asks <-chan askRef, adds <-c
 		if !ok {
 			when := ask.When
 			pending[ask.plRef] = &when // add the requests to the map
+			p.setAsk(ask.plRef, when, false)
 			s.LogPrintf(logname, "Ask %d/%d/%d %d", ask.DBH, ask.VM, ask.H, len(pending))
 		} // don't update the when if it already existed...
 	}
 
 	addAdd := func(add plRef) {
 		delete(pending, add) // Delete request that was just added to the process list in the map
+		p.clearAsk(add)
 		s.LogPrintf(logname, "Add %d/%d/%d %d", add.DBH, add.VM, add.H, len(pending))
 	}
 
@@ -712,6 +720,7 @@ func (p *ProcessList) makeMMRs(s interfaces.IState, asks <-chan askRef, adds <-c
 						mmrs[index].ProcessListHeight = append(mmrs[index].ProcessListHeight, uint32(ref.H))
 					}
 					*when = *when + askDelay // update when we asked, set lsb to say we already asked...
+					p.setAsk(ref, *when, true)
 					//s.LogPrintf(logname, "mmr ask %d/%d/%d %d", ref.DBH, ref.VM, ref.H, len(pending))
 					// Maybe when asking for past the end of the list we should not ask again?
 				}
@@ -727,6 +736,7 @@ func (p *ProcessList) makeMMRs(s interfaces.IState, asks <-chan askRef, adds <-c
 		case <-done:
 			addAllAsks() // process all pending asks before any adds
 			addAllAdds() // process all pending add before any ticks
+			p.clearAllAsks()
 
 			if len(pending) != 0 {
 				s.LogPrintf(logname, "End PL DBH %d with %d still outstanding %v", p.DBHeight, len(pending), pending)
@@ -1015,6 +1025,7 @@ func (p *ProcessList) Process(state *State) (progress bool) {
 					vm.heartBeat = 0
 					vm.Height = j + 1 // Don't process it again if the process worked.
 					p.State.LogMessage("process", fmt.Sprintf("done %v/%v/%v", p.DBHeight, i, j), msg)
+					p.State.LogMsgEvent(eventLog.MsgExecuted, p.DBHeight, p.State.CurrentMinute, i, j, msg, "")
 
 					progress = true
 
@@ -1027,6 +1038,8 @@ func (p *ProcessList) Process(state *State) (progress bool) {
 					delete(p.State.Acks, msgHashFixed)
 					delete(p.State.Holding, msgHashFixed)
 
+					p.publishAck(msg)
+
 				} else {
 					p.State.LogMessage("process", fmt.Sprintf("retry %v/%v/%v", p.DBHeight, i, j), msg)
 					//p.State.AddStatus(fmt.Sprintf("processList.Process(): Could not process entry dbht: %d VM: %d  msg: [[%s]]", p.DBHeight, i, msg.String()))
@@ -1042,6 +1055,27 @@ func (p *ProcessList) Process(state *State) (progress bool) {
 	return
 }
 
+// publishAck tells the API subscribers that a transaction or entry has been acknowledged
+// and processed in this process list
+func (p *ProcessList) publishAck(msg interfaces.IMsg) {
+	if !wsapi.HasSubscribers() {
+		return
+	}
+	node := p.State.FactomNodeName
+	switch m := msg.(type) {
+	case *messages.FactoidTransaction:
+		wsapi.PublishAckStatus(node, m.Transaction.GetSigHash(), constants.AckStatusACK)
+	case *messages.CommitChainMsg:
+		wsapi.PublishAckStatus(node, m.CommitChain.GetSigHash(), constants.AckStatusACK)
+		wsapi.PublishAckStatus(node, m.CommitChain.GetEntryHash(), constants.AckStatusACK)
+	case *messages.CommitEntryMsg:
+		wsapi.PublishAckStatus(node, m.CommitEntry.GetSigHash(), constants.AckStatusACK)
+		wsapi.PublishAckStatus(node, m.CommitEntry.GetEntryHash(), constants.AckStatusACK)
+	case *messages.RevealEntryMsg:
+		wsapi.PublishAckStatus(node, m.Entry.GetHash(), constants.AckStatusACK)
+	}
+}
+
 func (p *ProcessList) AddToProcessList(ack *messages.Ack, m interfaces.IMsg) {
 	if p == nil { // Just do nothing if we don't have a process list here.
 		return
@@ -1196,6 +1230,7 @@ func (p *ProcessList) AddToProcessList(ack *messages.Ack, m interfaces.IMsg) {
 	delete(p.State.Acks, msgHash.Fixed())
 	p.VMs[ack.VMIndex].List[ack.Height] = m
 	p.VMs[ack.VMIndex].ListAck[ack.Height] = ack
+	p.State.LogMsgEvent(eventLog.MsgAcked, p.DBHeight, p.State.CurrentMinute, ack.VMIndex, int(ack.Height), m, "")
 	p.AddOldMsgs(m)
 	p.OldAcks[msgHash.Fixed()] = ack
 
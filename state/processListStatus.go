@@ -0,0 +1,117 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"sort"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// setAsk records an ask makeMMRs waits on, with the time it asks for the message next
+func (p *ProcessList) setAsk(ref plRef, next int64, sent bool) {
+	p.asksMutex.Lock()
+	defer p.asksMutex.Unlock()
+
+	if p.outstandingAsks == nil {
+		p.outstandingAsks = make(map[plRef]*interfaces.MissingMsgAsk)
+	}
+	ask, ok := p.outstandingAsks[ref]
+	if !ok {
+		ask = new(interfaces.MissingMsgAsk)
+		ask.Height = int(ref.H)
+		p.outstandingAsks[ref] = ask
+	}
+	ask.NextAsk = next
+	if sent {
+		ask.Sent++
+	}
+}
+
+func (p *ProcessList) clearAsk(ref plRef) {
+	p.asksMutex.Lock()
+	defer p.asksMutex.Unlock()
+	delete(p.outstandingAsks, ref)
+}
+
+func (p *ProcessList) clearAllAsks() {
+	p.asksMutex.Lock()
+	defer p.asksMutex.Unlock()
+	p.outstandingAsks = nil
+}
+
+// GetAsks returns the missing messages of a VM makeMMRs waits on, by height
+func (p *ProcessList) GetAsks(vmIndex int) []interfaces.MissingMsgAsk {
+	p.asksMutex.Lock()
+	defer p.asksMutex.Unlock()
+
+	asks := []interfaces.MissingMsgAsk{}
+	for ref, ask := range p.outstandingAsks {
+		if ref.VM == vmIndex {
+			asks = append(asks, *ask)
+		}
+	}
+	sort.Slice(asks, func(i, j int) bool { return asks[i].Height < asks[j].Height })
+	return asks
+}
+
+// GetStatus describes the VMs of the process list
+func (p *ProcessList) GetStatus() *interfaces.ProcessListStatus {
+	now := p.State.GetTimestamp().GetTimeMilli()
+
+	status := new(interfaces.ProcessListStatus)
+	status.DBHeight = p.DBHeight
+	status.Complete = p.Complete()
+	status.CurrentMinute = p.State.CurrentMinute
+	status.Timestamp = now
+	status.VMs = []interfaces.VMStatus{}
+
+	// The ServerMap belongs to the consensus, so work out a map of our own
+	serverMap := MakeMap(len(p.FedServers), p.DBHeight)
+	for i := 0; i < len(p.FedServers); i++ {
+		vm := p.VMs[i]
+
+		v := interfaces.VMStatus{VM: i}
+		minute := vm.LeaderMinute
+		if minute > 9 {
+			minute = 9
+		}
+		if fedIndex := serverMap[minute][i]; fedIndex < len(p.FedServers) {
+			v.Leader = p.FedServers[fedIndex].GetChainID().String()
+		}
+		v.Height = vm.Height
+		v.ListLength = len(vm.List)
+		v.Minute = vm.LeaderMinute
+		v.Synced = vm.Synced
+		v.Signed = vm.Signed
+		v.Faulted = vm.WhenFaulted > 0
+		v.Unprocessed = []int{}
+		v.Missing = []int{}
+		for j := vm.Height; j < len(vm.List); j++ {
+			if vm.List[j] == nil {
+				v.Missing = append(v.Missing, j)
+			} else {
+				v.Unprocessed = append(v.Unprocessed, j)
+			}
+		}
+		v.Asks = p.GetAsks(i)
+		if vm.ProcessTime != nil {
+			v.LastProgress = vm.ProcessTime.GetTimeMilli()
+			v.SinceProgress = now - v.LastProgress
+		}
+		status.VMs = append(status.VMs, v)
+	}
+	return status
+}
+
+// GetProcessListStatus describes the process list of a height, or returns nil if the
+// node has no process list for it
+func (s *State) GetProcessListStatus(dbheight uint32) *interfaces.ProcessListStatus {
+	pl := s.ProcessLists.GetSafe(dbheight)
+	if pl == nil {
+		return nil
+	}
+	return pl.GetStatus()
+}
@@ -0,0 +1,106 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// GoPruneEntries runs on PRUNED nodes.  It keeps deleting the content of entries
+// older than PruneDepth directory blocks.
+func (s *State) GoPruneEntries() {
+	for {
+		err := s.PruneEntries()
+		if err != nil {
+			s.LogPrintf("pruning", "Error pruning entries: %v", err)
+		}
+		time.Sleep(10 * time.Second)
+	}
+}
+
+// PruneEntries deletes the content of the entries below the prune depth, starting
+// where the last call stopped.  Only blocks whose entries were all synced are pruned,
+// so we never go asking our peers for entries we just deleted.  Entries written again
+// to their chain above the prune depth are kept.
+func (s *State) PruneEntries() error {
+	complete := s.GetEntryDBHeightComplete()
+	if complete <= s.PruneDepth {
+		return nil
+	}
+	below := complete - s.PruneDepth
+
+	start, err := s.DB.FetchPrunedHeight()
+	if err != nil {
+		return err
+	}
+
+	// The entries of the blocks above the prune depth, by chain
+	kept := map[[32]byte]map[[32]byte]bool{}
+
+	for height := start; height < below; height++ {
+		dblock, err := s.DB.FetchDBlockByHeight(height)
+		if err != nil {
+			return err
+		}
+		if dblock == nil {
+			return fmt.Errorf("Missing directory block %d", height)
+		}
+
+		// The first three entries are the admin, entry credit and factoid blocks
+		for _, ebKeyMR := range dblock.GetEntryHashes()[3:] {
+			eblock, err := s.DB.FetchEBlock(ebKeyMR)
+			if err != nil {
+				return err
+			}
+			if eblock == nil {
+				return fmt.Errorf("Missing entry block %x at height %d", ebKeyMR.Bytes()[:3], height)
+			}
+			if keepEntries(eblock.GetDatabaseHeight(), eblock.GetChainID().Bytes()) {
+				continue
+			}
+			keep, ok := kept[eblock.GetChainID().Fixed()]
+			if !ok {
+				hashes, err := s.DB.FetchEntryHashesByChainFrom(eblock.GetChainID(), below)
+				if err != nil {
+					return err
+				}
+				keep = map[[32]byte]bool{}
+				for _, h := range hashes {
+					keep[h.Fixed()] = true
+				}
+				kept[eblock.GetChainID().Fixed()] = keep
+			}
+			err = s.DB.PruneEBlockEntries(eblock, keep)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = s.DB.SavePrunedHeight(height + 1)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keepEntries is true for the chains the node itself needs to process blocks:
+// the identity chains, the exchange rate chain and the anchor chain
+func keepEntries(dbheight uint32, chainID []byte) bool {
+	if dbheight < 2 {
+		return true
+	}
+	if bytes.HasPrefix(chainID, []byte{0x88, 0x88, 0x88}) {
+		return true
+	}
+	if bytes.HasPrefix(chainID, []byte{0x11, 0x11, 0x11}) {
+		return true
+	}
+	return fmt.Sprintf("%x", chainID) == databaseOverlay.AnchorBlockID
+}
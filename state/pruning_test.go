@@ -0,0 +1,64 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestPruneEntries(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+	blocks := testHelper.CreateFullTestBlockSet()
+
+	s.NodeMode = "PRUNED"
+	s.PruneDepth = 4
+	s.EntryDBHeightComplete = uint32(len(blocks) - 1)
+	below := s.EntryDBHeightComplete - s.PruneDepth
+
+	err := s.PruneEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	height, err := s.DB.FetchPrunedHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if height != below {
+		t.Errorf("Pruned height is %d, expected %d", height, below)
+	}
+
+	for i, block := range blocks {
+		for _, h := range block.EBlock.GetEntryHashes() {
+			if h.IsMinuteMarker() {
+				continue
+			}
+			pruned, err := s.DB.IsEntryPruned(h)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := uint32(i) < below && i >= 2
+			if pruned != expected {
+				t.Errorf("Entry %v at height %d: pruned is %v, expected %v", h, i, pruned, expected)
+			}
+		}
+		// The anchor chain is needed by the node, and never pruned
+		for _, h := range block.AnchorEBlock.GetEntryHashes() {
+			if h.IsMinuteMarker() {
+				continue
+			}
+			if pruned, _ := s.DB.IsEntryPruned(h); pruned {
+				t.Errorf("Anchor entry %v at height %d was pruned", h, i)
+			}
+		}
+	}
+
+	// A pruned entry is not served to peers
+	h := blocks[2].EBlock.GetEntryHashes()[0]
+	if s.DatabaseContains(h) {
+		t.Error("A pruned entry is still served")
+	}
+}
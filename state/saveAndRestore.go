@@ -685,6 +685,7 @@ func (ss *SaveState) RestoreFactomdState(s *State) { //, d *DBState) {
 	s.FERChangePrice = ss.FERChangePrice
 	s.FERPriority = ss.FERPriority
 	s.FERPrioritySetHeight = ss.FERPrioritySetHeight
+	s.PublishFERChange()
 }
 
 func (ss *SaveState) MarshalBinary() (rval []byte, err error) {
@@ -0,0 +1,162 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// Snapshots are taken while the node runs.  A request through the debug API is picked
+// up by the DBStateList between two blocks.  The snapshot is copied from a view of the
+// database opened when it starts, so blocks, entries and pruning go on being saved
+// while it is written, and the snapshot holds the database as it was at its start.
+
+// RequestSnapshot asks for a snapshot of the database at the next block boundary, to
+// be written to filename in the SnapshotPath.  It returns the path of the file.
+func (s *State) RequestSnapshot(filename string) (string, error) {
+	if !s.DBFinished {
+		return "", fmt.Errorf("The database is still loading")
+	}
+	dbo, ok := s.DB.(*databaseOverlay.Overlay)
+	if !ok {
+		return "", fmt.Errorf("The database does not support snapshots")
+	}
+	if _, ok := dbo.DB.(interfaces.IViewDatabase); !ok {
+		return "", fmt.Errorf("The database does not support snapshots")
+	}
+
+	s.snapshotMutex.Lock()
+	defer s.snapshotMutex.Unlock()
+	if s.snapshot.Pending || s.snapshot.Running {
+		return "", fmt.Errorf("A snapshot is already in progress")
+	}
+
+	// Snapshots only go in the snapshot directory, the API can't write anywhere else
+	if filename == "" {
+		filename = fmt.Sprintf("snapshot_%s_%s.fsnap", s.Network, time.Now().UTC().Format("20060102-150405"))
+	}
+	if filename != filepath.Base(filename) || filename == "." || filename == ".." {
+		return "", fmt.Errorf("The snapshot file must be a name, not a path")
+	}
+	filename = filepath.Join(s.SnapshotPath, filename)
+	s.snapshot = interfaces.SnapshotStatus{File: filename, Pending: true}
+	return filename, nil
+}
+
+func (s *State) GetSnapshotStatus() interfaces.SnapshotStatus {
+	s.snapshotMutex.Lock()
+	defer s.snapshotMutex.Unlock()
+	return s.snapshot
+}
+
+// IsSnapshotRunning is true while a snapshot is being written
+func (s *State) IsSnapshotRunning() bool {
+	s.snapshotMutex.Lock()
+	defer s.snapshotMutex.Unlock()
+	return s.snapshot.Running
+}
+
+// StartRequestedSnapshot starts the pending snapshot, if any.  It is called between
+// blocks, with the database holding everything up to height.
+func (s *State) StartRequestedSnapshot(height uint32) {
+	s.snapshotMutex.Lock()
+	defer s.snapshotMutex.Unlock()
+	if !s.snapshot.Pending {
+		return
+	}
+	s.snapshot.Pending = false
+	s.snapshot.Running = true
+	s.snapshot.Height = height
+	s.snapshot.Started = time.Now().Unix()
+	go s.writeSnapshot(s.snapshot.File)
+}
+
+func (s *State) writeSnapshot(filename string) {
+	m, err := s.writeSnapshotFile(filename)
+
+	s.snapshotMutex.Lock()
+	defer s.snapshotMutex.Unlock()
+	s.snapshot.Running = false
+	s.snapshot.Finished = time.Now().Unix()
+	if err != nil {
+		s.snapshot.Error = err.Error()
+		s.LogPrintf("snapshot", "Snapshot %s failed: %v", filename, err)
+		return
+	}
+	// The view may be ahead of the block the snapshot was started at
+	s.snapshot.Height = m.Height
+	s.LogPrintf("snapshot", "Snapshot of height %d written to %s", s.snapshot.Height, filename)
+}
+
+// writeSnapshotFile writes to a temporary file, so a file with the snapshot's name is
+// always complete
+func (s *State) writeSnapshotFile(filename string) (*databaseOverlay.SnapshotManifest, error) {
+	err := os.MkdirAll(filepath.Dir(filename), 0750)
+	if err != nil {
+		return nil, err
+	}
+	tmp := filename + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return nil, err
+	}
+	m, err := s.DB.(*databaseOverlay.Overlay).WriteSnapshot(f, s.Network)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return nil, err
+	}
+	return m, os.Rename(tmp, filename)
+}
+
+// RestoreDatabaseSnapshot loads the RestoreSnapshot file into the empty database,
+// and checks every directory block against the snapshot's manifest.  It runs from
+// Init, before any block is processed.
+func (s *State) RestoreDatabaseSnapshot() error {
+	dbo, ok := s.DB.(*databaseOverlay.Overlay)
+	if !ok {
+		return fmt.Errorf("The database does not support snapshots")
+	}
+
+	f, err := os.Open(s.RestoreSnapshot)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	m, err := databaseOverlay.ReadSnapshotManifest(f)
+	if err != nil {
+		return err
+	}
+	if m.Network != s.Network {
+		return fmt.Errorf("The snapshot is of the %s network, not %s", m.Network, s.Network)
+	}
+	_, err = f.Seek(0, 0)
+	if err != nil {
+		return err
+	}
+
+	s.Println("Restoring the database from", s.RestoreSnapshot, "up to height", m.Height)
+	_, err = dbo.RestoreSnapshot(f)
+	if err != nil {
+		return err
+	}
+
+	// A fast boot file would be of a different database
+	s.StateSaverStruct.DeleteSaveState(s.Network)
+	s.Println("Restored and verified", m.Height+1, "directory blocks")
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestSnapshotAndRestore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshots")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := testHelper.CreateAndPopulateTestState()
+	s.SnapshotPath = dir
+	s.DBFinished = true
+
+	if _, err := s.RequestSnapshot("../outside.fsnap"); err == nil {
+		t.Error("Requested a snapshot outside the snapshot directory")
+	}
+	file, err := s.RequestSnapshot("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.RequestSnapshot(""); err == nil {
+		t.Error("Requested a second snapshot while one is pending")
+	}
+	if !s.GetSnapshotStatus().Pending {
+		t.Error("The snapshot is not pending")
+	}
+
+	head, err := s.DB.FetchDBlockHead()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.StartRequestedSnapshot(head.GetDatabaseHeight())
+	for i := 0; s.IsSnapshotRunning(); i++ {
+		if i > 100 {
+			t.Fatal("The snapshot is taking too long")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	status := s.GetSnapshotStatus()
+	if status.Error != "" || status.File != file || status.Height != head.GetDatabaseHeight() {
+		t.Fatalf("Snapshot status %+v", status)
+	}
+
+	// Restore into an empty database of the same network
+	restored := new(state.State)
+	restored.DB = testHelper.CreateEmptyTestDatabaseOverlay()
+	restored.Network = s.Network
+	restored.RestoreSnapshot = file
+	err = restored.RestoreDatabaseSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restoredHead, err := restored.DB.FetchDBlockHead()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restoredHead == nil || !restoredHead.GetKeyMR().IsSameAs(head.GetKeyMR()) {
+		t.Error("The restored database has a different head")
+	}
+
+	// But not into another network's
+	other := new(state.State)
+	other.DB = testHelper.CreateEmptyTestDatabaseOverlay()
+	other.Network = "MAIN"
+	other.RestoreSnapshot = file
+	if err := other.RestoreDatabaseSnapshot(); err == nil {
+		t.Error("Restored a snapshot of another network")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"crypto/rand"
 	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -20,15 +21,18 @@ import (
 	"github.com/FactomProject/factomd/activations"
 	"github.com/FactomProject/factomd/common/adminBlock"
 	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/eventLog"
 	"github.com/FactomProject/factomd/common/globals"
 	. "github.com/FactomProject/factomd/common/identity"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/badgerdb"
 	"github.com/FactomProject/factomd/database/boltdb"
 	"github.com/FactomProject/factomd/database/databaseOverlay"
 	"github.com/FactomProject/factomd/database/leveldb"
 	"github.com/FactomProject/factomd/database/mapdb"
+	"github.com/FactomProject/factomd/database/securedb"
 	"github.com/FactomProject/factomd/p2p"
 	"github.com/FactomProject/factomd/util"
 	"github.com/FactomProject/factomd/util/atomic"
@@ -61,9 +65,11 @@ type State struct {
 	LogPath         string
 	LdbPath         string
 	BoltDBPath      string
+	BadgerDBPath    string
 	LogLevel        string
 	ConsoleLogLevel string
 	NodeMode        string
+	PruneDepth      uint32 // Entry content older than this many blocks is deleted in PRUNED mode
 	DBType          string
 	CheckChainHeads struct {
 		CheckChainHeads bool
@@ -72,6 +78,19 @@ type State struct {
 	CloneDBType       string
 	ExportData        bool
 	ExportDataSubpath string
+	AddressIndex      bool
+	ExtIDIndex        bool
+	BalanceHistory    bool
+	ChainInfo         bool
+	AnchorSigningKeys string
+	DBPasswordSource  string // Where the password of EncryptedLDB and EncryptedBolt is read from
+	// Shared with the simulated nodes, so a password is asked once
+	dbPasswords *dbPasswordCache
+	// Encrypts what is kept next to an encrypted database
+	dbCipher DataCipher
+
+	SnapshotPath    string // Snapshots requested through the debug API are written here
+	RestoreSnapshot string // Snapshot file loaded into the empty database at boot
 
 	LogBits int64 // Bit zero is for logging the Directory Block on DBSig [5]
 
@@ -110,6 +129,8 @@ type State struct {
 	CustomSeedURL           string
 	CustomSpecialPeers      string
 	CustomNetworkID         []byte
+	P2PEncryption           bool
+	P2PKeyFile              string
 	CustomBootstrapIdentity string
 	CustomBootstrapKey      string
 
@@ -136,6 +157,11 @@ type State struct {
 	HoldingMutex sync.RWMutex
 	HoldingLast  int64
 	HoldingMap   map[[32]byte]interfaces.IMsg
+	HoldingList  []interfaces.HeldMessage
+	holdingSince map[[32]byte]int64 // When the messages were first seen in holding, in seconds
+
+	// Evictions from the holding queue asked for through the debug API
+	holdingEvictions chan *holdingEviction
 
 	// Elections are managed through the Elections Structure
 	EFactory  interfaces.IElectionsFactory
@@ -180,6 +206,7 @@ type State struct {
 	ShutdownChan chan int // For gracefully halting Factom
 	JournalFile  string
 	Journaling   bool
+	EventLog     *eventLog.Writer // Consensus events of the node, nil when not logged
 
 	serverPrivKey         *primitives.PrivateKey
 	serverPubKey          *primitives.PublicKey
@@ -196,6 +223,15 @@ type State struct {
 	factomdTLSCertFile string
 	FactomdLocations   string
 
+	// API rate limits, per remote IP and per RPC user.  A rate of 0 is no limit
+	APIReadRate    float64
+	APIReadBurst   int
+	APISubmitRate  float64
+	APISubmitBurst int
+	APIUsersFile   string // Further API users, and the methods they may call
+	GRPCEnabled    bool
+	GRPCPort       int
+
 	// Server State
 	StartDelay      int64 // Time in Milliseconds since the last DBState was applied
 	StartDelayLimit int64
@@ -209,6 +245,7 @@ type State struct {
 	IgnoreMissing bool
 
 	LLeaderHeight   uint32
+	leaderHeight    atomic.AtomicUint32 // LLeaderHeight, for other goroutines
 	Leader          bool
 	LeaderVMIndex   int
 	LeaderPL        *ProcessList
@@ -324,6 +361,7 @@ type State struct {
 	// For Replay / journal
 	IsReplaying     bool
 	ReplayTimestamp interfaces.Timestamp
+	ReplayCheck     *ReplayCheck // Blocks expected from a deterministic replay, set for the whole replay
 
 	MissingEntryBlockRepeat interfaces.Timestamp
 	// DBlock Height at which node has a complete set of eblocks+entries
@@ -365,6 +403,11 @@ type State struct {
 	FERPriority          uint32
 	FERPrioritySetHeight uint32
 
+	// The pending exchange rate change, as last published for other goroutines
+	ferChangeMutex           sync.RWMutex
+	publishedFERChangePrice  uint64
+	publishedFERChangeHeight uint32
+
 	AckChange uint32
 
 	StateSaverStruct StateSaverStruct
@@ -396,6 +439,10 @@ type State struct {
 	processCnt          int64 // count of attempts to process .. so we can see if the thread is running
 
 	reportedActivations [activations.ACTIVATION_TYPE_COUNT + 1]bool // flags about which activations we have reported (+1 because we don't use 0)
+
+	// Database snapshots, see snapshot.go
+	snapshotMutex sync.Mutex
+	snapshot      interfaces.SnapshotStatus
 }
 
 var _ interfaces.IState = (*State)(nil)
@@ -448,6 +495,12 @@ func (s *State) Clone(cloneNumber int) interfaces.IState {
 	newState.JournalFile = s.LogPath + "/journal" + number + ".log"
 	newState.Journaling = s.Journaling
 	newState.BoltDBPath = s.BoltDBPath + "/Sim" + number
+	newState.BadgerDBPath = s.BadgerDBPath + "/Sim" + number
+	newState.DBPasswordSource = s.DBPasswordSource
+	if s.dbPasswords == nil {
+		s.dbPasswords = new(dbPasswordCache)
+	}
+	newState.dbPasswords = s.dbPasswords
 	newState.LogLevel = s.LogLevel
 	newState.ConsoleLogLevel = s.ConsoleLogLevel
 	newState.NodeMode = "FULL"
@@ -456,6 +509,12 @@ func (s *State) Clone(cloneNumber int) interfaces.IState {
 	newState.CheckChainHeads = s.CheckChainHeads
 	newState.ExportData = s.ExportData
 	newState.ExportDataSubpath = s.ExportDataSubpath + "sim-" + number
+	newState.AddressIndex = s.AddressIndex
+	newState.ExtIDIndex = s.ExtIDIndex
+	newState.BalanceHistory = s.BalanceHistory
+	newState.ChainInfo = s.ChainInfo
+	newState.AnchorSigningKeys = s.AnchorSigningKeys
+	newState.SnapshotPath = s.SnapshotPath + "/Sim" + number
 	newState.Network = s.Network
 	newState.MainNetworkPort = s.MainNetworkPort
 	newState.PeersFile = s.PeersFile
@@ -470,6 +529,8 @@ func (s *State) Clone(cloneNumber int) interfaces.IState {
 	newState.CustomNetworkPort = s.CustomNetworkPort
 	newState.CustomSeedURL = s.CustomSeedURL
 	newState.CustomSpecialPeers = s.CustomSpecialPeers
+	newState.P2PEncryption = s.P2PEncryption
+	newState.P2PKeyFile = s.P2PKeyFile
 	newState.StartDelayLimit = s.StartDelayLimit
 	newState.CustomNetworkID = s.CustomNetworkID
 
@@ -518,15 +579,27 @@ func (s *State) Clone(cloneNumber int) interfaces.IState {
 	newState.factomdTLSCertFile = s.factomdTLSCertFile
 	newState.FactomdLocations = s.FactomdLocations
 
+	newState.APIReadRate = s.APIReadRate
+	newState.APIReadBurst = s.APIReadBurst
+	newState.APISubmitRate = s.APISubmitRate
+	newState.APISubmitBurst = s.APISubmitBurst
+	newState.APIUsersFile = s.APIUsersFile
+	newState.GRPCEnabled = s.GRPCEnabled
+	newState.GRPCPort = s.GRPCPort
+
 	switch newState.DBType {
-	case "LDB":
+	case "LDB", "EncryptedLDB":
 		newState.StateSaverStruct.FastBoot = s.StateSaverStruct.FastBoot
 		newState.StateSaverStruct.FastBootLocation = newState.LdbPath
 		break
-	case "Bolt":
+	case "Bolt", "EncryptedBolt":
 		newState.StateSaverStruct.FastBoot = s.StateSaverStruct.FastBoot
 		newState.StateSaverStruct.FastBootLocation = newState.BoltDBPath
 		break
+	case "Badger":
+		newState.StateSaverStruct.FastBoot = s.StateSaverStruct.FastBoot
+		newState.StateSaverStruct.FastBootLocation = newState.BadgerDBPath
+		break
 	}
 
 	if globals.Params.WriteProcessedDBStates {
@@ -622,6 +695,18 @@ func (s *State) GetTlsInfo() (bool, string, string) {
 	return s.FactomdTLSEnable, s.factomdTLSKeyFile, s.factomdTLSCertFile
 }
 
+func (s *State) GetAPIRateLimits() (float64, int, float64, int) {
+	return s.APIReadRate, s.APIReadBurst, s.APISubmitRate, s.APISubmitBurst
+}
+
+func (s *State) GetAPIUsersFile() string {
+	return s.APIUsersFile
+}
+
+func (s *State) GetGRPCInfo() (bool, int) {
+	return s.GRPCEnabled, s.GRPCPort
+}
+
 func (s *State) GetFactomdLocations() string {
 	return s.FactomdLocations
 }
@@ -690,21 +775,33 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 		// TODO: improve the paths after milestone 1
 		cfg.App.LdbPath = cfg.App.HomeDir + networkName + cfg.App.LdbPath
 		cfg.App.BoltDBPath = cfg.App.HomeDir + networkName + cfg.App.BoltDBPath
+		cfg.App.BadgerDBPath = cfg.App.HomeDir + networkName + cfg.App.BadgerDBPath
 		cfg.App.DataStorePath = cfg.App.HomeDir + networkName + cfg.App.DataStorePath
 		cfg.Log.LogPath = cfg.App.HomeDir + networkName + cfg.Log.LogPath
 		cfg.App.ExportDataSubpath = cfg.App.HomeDir + networkName + cfg.App.ExportDataSubpath
+		cfg.App.SnapshotPath = cfg.App.HomeDir + networkName + cfg.App.SnapshotPath
 		cfg.App.PeersFile = cfg.App.HomeDir + networkName + cfg.App.PeersFile
+		cfg.App.P2PKeyFile = cfg.App.HomeDir + networkName + cfg.App.P2PKeyFile
 		cfg.App.ControlPanelFilesPath = cfg.App.HomeDir + cfg.App.ControlPanelFilesPath
 
 		s.LogPath = cfg.Log.LogPath + s.Prefix
 		s.LdbPath = cfg.App.LdbPath + s.Prefix
 		s.BoltDBPath = cfg.App.BoltDBPath + s.Prefix
+		s.BadgerDBPath = cfg.App.BadgerDBPath + s.Prefix
+		s.DBPasswordSource = cfg.App.DBPasswordSource
 		s.LogLevel = cfg.Log.LogLevel
 		s.ConsoleLogLevel = cfg.Log.ConsoleLogLevel
 		s.NodeMode = cfg.App.NodeMode
+		s.PruneDepth = cfg.App.PruneDepth
 		s.DBType = cfg.App.DBType
 		s.ExportData = cfg.App.ExportData // bool
 		s.ExportDataSubpath = cfg.App.ExportDataSubpath
+		s.AddressIndex = cfg.App.AddressIndex
+		s.ExtIDIndex = cfg.App.ExtIDIndex
+		s.BalanceHistory = cfg.App.BalanceHistory
+		s.ChainInfo = cfg.App.ChainInfo
+		s.AnchorSigningKeys = cfg.App.AnchorSigningKeys
+		s.SnapshotPath = cfg.App.SnapshotPath
 		s.MainNetworkPort = cfg.App.MainNetworkPort
 		s.PeersFile = cfg.App.PeersFile
 		s.MainSeedURL = cfg.App.MainSeedURL
@@ -721,12 +818,21 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 		s.CustomNetworkPort = cfg.App.CustomNetworkPort
 		s.CustomSeedURL = cfg.App.CustomSeedURL
 		s.CustomSpecialPeers = cfg.App.CustomSpecialPeers
+		s.P2PEncryption = cfg.App.P2PEncryption
+		s.P2PKeyFile = cfg.App.P2PKeyFile
 		s.FactoshisPerEC = cfg.App.ExchangeRate
 		s.DirectoryBlockInSeconds = cfg.App.DirectoryBlockInSeconds
 		s.PortNumber = cfg.App.PortNumber
 		s.ControlPanelPort = cfg.App.ControlPanelPort
 		s.RpcUser = cfg.App.FactomdRpcUser
 		s.RpcPass = cfg.App.FactomdRpcPass
+		s.APIReadRate = cfg.App.APIReadRate
+		s.APIReadBurst = cfg.App.APIReadBurst
+		s.APISubmitRate = cfg.App.APISubmitRate
+		s.APISubmitBurst = cfg.App.APISubmitBurst
+		s.APIUsersFile = cfg.App.APIUsersFile
+		s.GRPCEnabled = cfg.App.GRPCEnabled
+		s.GRPCPort = cfg.App.GRPCPort
 		s.StateSaverStruct.FastBoot = cfg.App.FastBoot
 		s.StateSaverStruct.FastBootLocation = cfg.App.FastBootLocation
 		s.FastBoot = cfg.App.FastBoot
@@ -766,12 +872,15 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 		s.LogPath = "database/"
 		s.LdbPath = "database/ldb"
 		s.BoltDBPath = "database/bolt"
+		s.BadgerDBPath = "database/badger"
 		s.LogLevel = "none"
 		s.ConsoleLogLevel = "standard"
 		s.NodeMode = "SERVER"
+		s.PruneDepth = 4320
 		s.DBType = "Map"
 		s.ExportData = false
 		s.ExportDataSubpath = "data/export"
+		s.SnapshotPath = "snapshots"
 		s.Network = "TEST"
 		s.MainNetworkPort = "8108"
 		s.PeersFile = "peers.json"
@@ -783,6 +892,7 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 		s.LocalNetworkPort = "8110"
 		s.LocalSeedURL = "https://raw.githubusercontent.com/FactomProject/factomproject.github.io/master/seed/localseed.txt"
 		s.LocalSpecialPeers = ""
+		s.P2PKeyFile = "p2pkey.txt"
 
 		s.LocalServerPrivKey = "4c38c72fc5cdad68f13b74674d3ffb1f3d63a112710868c9b08946553448d26d"
 		s.FactoshisPerEC = 006666
@@ -857,6 +967,7 @@ func (s *State) Init() {
 	s.timerMsgQueue = make(chan interfaces.IMsg, 100)          //incoming eom notifications, used by leaders
 	s.TimeOffset = new(primitives.Timestamp)                   //interfaces.Timestamp(int64(rand.Int63() % int64(time.Microsecond*10)))
 	s.networkInvalidMsgQueue = make(chan interfaces.IMsg, 100) //incoming message queue from the network messages
+	s.holdingEvictions = make(chan *holdingEviction, 10)       //evictions from the holding queue asked for by the debug API
 	s.InvalidMessages = make(map[[32]byte]interfaces.IMsg, 0)
 	s.networkOutMsgQueue = NewNetOutMsgQueue(5000)                 //Messages to be broadcast to the network
 	s.inMsgQueue = NewInMsgQueue(constants.INMSGQUEUE_HIGH + 100)  //incoming message queue for Factom application messages
@@ -878,6 +989,16 @@ func (s *State) Init() {
 		}
 		f.Close()
 	}
+	if globals.Params.EventLogPath != "" {
+		path := filepath.Join(globals.Params.EventLogPath, s.FactomNodeName+".events")
+		os.MkdirAll(globals.Params.EventLogPath, 0755)
+		w, err := eventLog.Open(path, s.FactomNodeName)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Could not open the event log:", err)
+		} else {
+			s.EventLog = w
+		}
+	}
 	// Set up struct to stop replay attacks
 	s.Replay = new(Replay)
 	s.Replay.s = s
@@ -916,12 +1037,17 @@ func (s *State) Init() {
 		s.Println("\n   +---------------------------+")
 		s.Println("   +------ Follower Only ------+")
 		s.Print("   +---------------------------+\n\n")
+	case "PRUNED":
+		s.Leader = false
+		s.Println("\n   +---------------------------+")
+		s.Println("   +----- Pruned Follower -----+")
+		s.Print("   +---------------------------+\n\n")
 	case "SERVER":
 		s.Println("\n   +-------------------------+")
 		s.Println("   |       Leader Node       |")
 		s.Print("   +-------------------------+\n\n")
 	default:
-		panic("Bad Node Mode (must be FULL or SERVER)")
+		panic("Bad Node Mode (must be FULL, SERVER or PRUNED)")
 	}
 
 	//Database
@@ -934,6 +1060,18 @@ func (s *State) Init() {
 		if err := s.InitBoltDB(); err != nil {
 			panic(fmt.Sprintf("Error initializing the database: %v", err))
 		}
+	case "Badger":
+		if err := s.InitBadgerDB(); err != nil {
+			panic(fmt.Sprintf("Error initializing the database: %v", err))
+		}
+	case "EncryptedLDB":
+		if err := s.InitEncryptedDB("LDB"); err != nil {
+			panic(fmt.Sprintf("Error initializing the database: %v", err))
+		}
+	case "EncryptedBolt":
+		if err := s.InitEncryptedDB("Bolt"); err != nil {
+			panic(fmt.Sprintf("Error initializing the database: %v", err))
+		}
 	case "Map":
 		if err := s.InitMapDB(); err != nil {
 			panic(fmt.Sprintf("Error initializing the database: %v", err))
@@ -942,6 +1080,12 @@ func (s *State) Init() {
 		panic("No Database type specified")
 	}
 
+	if s.RestoreSnapshot != "" {
+		if err := s.RestoreDatabaseSnapshot(); err != nil {
+			panic(fmt.Sprintf("Error restoring the snapshot %s: %v", s.RestoreSnapshot, err))
+		}
+	}
+
 	if s.CheckChainHeads.CheckChainHeads {
 		if s.CheckChainHeads.Fix {
 			// Set dblock head to 184 if 184 is present and head is not 184
@@ -973,6 +1117,52 @@ func (s *State) Init() {
 		s.DB.SetExportData(s.ExportDataSubpath)
 	}
 
+	if s.AddressIndex {
+		// Index whatever was saved while the index was off, before any new block is saved
+		s.DB.SetAddressIndex(true)
+		s.Println("Updating the address index...")
+		if err := s.DB.BackfillAddressIndex(); err != nil {
+			panic(fmt.Sprintf("Error updating the address index: %v", err))
+		}
+	}
+
+	if s.ExtIDIndex {
+		s.DB.SetExtIDIndex(true)
+		s.Println("Updating the external ID index...")
+		if err := s.DB.BackfillExtIDIndex(); err != nil {
+			panic(fmt.Sprintf("Error updating the external ID index: %v", err))
+		}
+	}
+
+	if s.BalanceHistory {
+		s.DB.SetBalanceHistory(true)
+		s.Println("Updating the balance history...")
+		if err := s.DB.BackfillBalanceHistory(); err != nil {
+			panic(fmt.Sprintf("Error updating the balance history: %v", err))
+		}
+	}
+
+	// Count the chains saved before the chain statistics were kept, or while the
+	// database was used by other programs
+	if s.ChainInfo {
+		s.DB.SetChainInfo(true)
+		s.Println("Updating the chain statistics...")
+		if err := s.DB.BackfillChainInfo(); err != nil {
+			panic(fmt.Sprintf("Error updating the chain statistics: %v", err))
+		}
+	}
+
+	if s.AnchorSigningKeys != "" {
+		err := databaseOverlay.SetAnchorSigningKeys(strings.Split(s.AnchorSigningKeys, ","))
+		if err != nil {
+			panic(fmt.Sprintf("Error in AnchorSigningKeys: %v", err))
+		}
+	}
+	// Index the anchor records saved before the index, or checked against other keys
+	if err := s.DB.RebuildAnchorIndex(); err != nil {
+		panic(fmt.Sprintf("Error updating the anchor index: %v", err))
+	}
+
 	// Cross Boot Replay
 	switch s.DBType {
 	case "Map":
@@ -1094,6 +1284,12 @@ func (s *State) GetLLeaderHeight() uint32 {
 	return s.LLeaderHeight
 }
 
+// GetPublishedLeaderHeight returns the leader height, and can be called off the state
+// goroutine
+func (s *State) GetPublishedLeaderHeight() uint32 {
+	return s.leaderHeight.Load()
+}
+
 func (s *State) GetFaultTimeout() int {
 	return s.FaultTimeout
 }
@@ -1318,6 +1514,10 @@ func (s *State) LoadDataByHash(requestedHash interfaces.IHash) (interfaces.Binar
 	if result != nil && err == nil {
 		return result, 0, nil
 	}
+	if pruned, _ := s.DB.IsEntryPruned(requestedHash); pruned {
+		// We no longer have the content, and must not pretend otherwise
+		return nil, -1, fmt.Errorf("Entry %x has been pruned", requestedHash.Bytes()[:3])
+	}
 
 	// Check for Entry Block
 	result, err = s.DB.FetchEBlock(requestedHash)
@@ -1376,10 +1576,12 @@ func (s *State) fillHoldingMap() {
 		for i, msg := range s.Holding {
 			localMap[i] = msg
 		}
+		list := s.describeHolding(time.Now().Unix())
 		s.HoldingLast = time.Now().Unix()
 		s.HoldingMutex.Lock()
 		defer s.HoldingMutex.Unlock()
 		s.HoldingMap = localMap
+		s.HoldingList = list
 
 	}
 }
@@ -1746,10 +1948,14 @@ func (s *State) DatabaseContains(hash interfaces.IHash) bool {
 	return false
 }
 
-// JournalMessage writes the message to the message journal for debugging
+// JournalMessage writes the message to the message journal for debugging.  Along with
+// the message, the journal keeps its binary form and the local time it was processed
+// at, so the journal can be replayed.
 func (s *State) JournalMessage(msg interfaces.IMsg) {
 	type journalentry struct {
 		Type    byte
+		Time    int64 // Milliseconds
+		MsgHex  string
 		Message interfaces.IMsg
 	}
 
@@ -1761,8 +1967,15 @@ func (s *State) JournalMessage(msg interfaces.IMsg) {
 		}
 		defer f.Close()
 
+		data, err := msg.MarshalBinary()
+		if err != nil {
+			return
+		}
+
 		e := new(journalentry)
 		e.Type = msg.Type()
+		e.Time = primitives.NewTimestampNow().GetTimeMilli()
+		e.MsgHex = hex.EncodeToString(data)
 		e.Message = msg
 
 		p, err := json.Marshal(e)
@@ -1857,6 +2070,7 @@ func (s *State) UpdateState() (progress bool) {
 	}
 
 	// check to see ig a holding queue list request has been made
+	s.evictHeldMessages()
 	s.fillHoldingMap()
 	s.fillAcksMap()
 
@@ -2060,7 +2274,6 @@ func (s *State) SetIsDoneReplaying() {
 // Returns a millisecond timestamp
 func (s *State) GetTimestamp() interfaces.Timestamp {
 	if s.IsReplaying == true {
-		fmt.Println("^^^^^^^^ IsReplying is true")
 		return s.ReplayTimestamp
 	}
 	return primitives.NewTimestampNow()
@@ -2141,6 +2354,7 @@ func (s *State) SetLeaderTimestamp(ts interfaces.Timestamp) {
 }
 func (s *State) SetLLeaderHeight(height uint32) {
 	s.LLeaderHeight = height //SetLeaderHeight()
+	s.leaderHeight.Store(height)
 	//s.LogPrintf("executeMsg", "Set LeaderHeight %d for %s", s.LLeaderHeight, atomic.WhereAmIString(1))
 }
 
@@ -2331,6 +2545,104 @@ func (s *State) InitBoltDB() error {
 	return nil
 }
 
+func (s *State) InitBadgerDB() error {
+	if s.DB != nil {
+		return nil
+	}
+
+	path := s.BadgerDBPath + "/" + s.Network + "/" + "factom_badger.db"
+
+	s.Println("Database:", path)
+	fmt.Fprint(os.Stderr, "Database:", path)
+
+	dbase, err := badgerdb.NewBadgerDB(path, true)
+	if err != nil {
+		return err
+	}
+
+	s.DB = databaseOverlay.NewOverlay(dbase)
+	return nil
+}
+
+// DataCipher encrypts the files and keys the state keeps next to an encrypted database,
+// with the key of that database
+type DataCipher interface {
+	Encrypt(data []byte) ([]byte, error)
+	Decrypt(data []byte) ([]byte, error)
+	HashKey(key []byte) []byte
+}
+
+// dbPasswordCache holds the database passwords read so far, by source, so simulated
+// nodes sharing a source read it once
+type dbPasswordCache struct {
+	mutex     sync.Mutex
+	passwords map[string]string
+}
+
+func (c *dbPasswordCache) get(source string) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if password, ok := c.passwords[source]; ok {
+		return password, nil
+	}
+	password, err := securedb.GetPassword(source, "Database password")
+	if err != nil {
+		return "", err
+	}
+	if c.passwords == nil {
+		c.passwords = map[string]string{}
+	}
+	c.passwords[source] = password
+	return password, nil
+}
+
+// InitEncryptedDB opens a LevelDB or Bolt database that encrypts everything it stores
+// with the password read from DBPasswordSource.  The files are not the ones of the
+// plain databases, so a plain database is never mistaken for an encrypted one.  The
+// FastBoot save state and the cross boot replay filter are encrypted with its key.
+func (s *State) InitEncryptedDB(dbtype string) error {
+	if s.DB != nil {
+		return nil
+	}
+
+	if s.dbPasswords == nil {
+		s.dbPasswords = new(dbPasswordCache)
+	}
+	password, err := s.dbPasswords.get(s.DBPasswordSource)
+	if err != nil {
+		return err
+	}
+
+	var dbase interfaces.IDatabase
+	switch dbtype {
+	case "LDB":
+		path := s.LdbPath + "/" + s.Network + "/" + "factoid_level_encrypted.db"
+		s.Println("Encrypted database:", path)
+		dbase, err = leveldb.NewLevelDB(path, true)
+		if err != nil {
+			return err
+		}
+	case "Bolt":
+		path := s.BoltDBPath + "/" + s.Network + "/"
+		s.Println("Encrypted database path for", s.FactomNodeName, "is", path)
+		os.MkdirAll(path, 0777)
+		dbase = boltdb.NewBoltDB(nil, path+"FactomBoltEncrypted.db")
+	default:
+		return fmt.Errorf("No encrypted database of type %s", dbtype)
+	}
+
+	edb, err := securedb.NewEncryptedDBFromDB(dbase, password)
+	if err != nil {
+		dbase.Close()
+		return err
+	}
+	s.DB = databaseOverlay.NewOverlay(edb)
+	s.dbCipher = edb
+	s.StateSaverStruct.Cipher = edb
+	return nil
+}
+
 func (s *State) InitMapDB() error {
 	if s.DB != nil {
 		return nil
@@ -15,6 +15,7 @@ import (
 	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/entryBlock"
 	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/eventLog"
 	"github.com/FactomProject/factomd/common/globals"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
@@ -430,25 +431,34 @@ func (s *State) ReviewHolding() {
 
 	highest := s.GetHighestKnownBlock()
 	saved := s.GetHighestSavedBlk()
-	for _, a := range s.Acks {
-		if s.Holding[a.GetHash().Fixed()] != nil {
+	reviewAck := func(a interfaces.IMsg) {
+		if a != nil && s.Holding[a.GetHash().Fixed()] != nil {
 			a.FollowerExecute(s)
 		}
 	}
+	if s.ReplayCheck != nil {
+		// The order of a map is random, a replay goes through the maps in the same order every time
+		for _, k := range s.mapOrder(s.Acks) {
+			reviewAck(s.Acks[k])
+		}
+	} else {
+		for _, a := range s.Acks {
+			reviewAck(a)
+		}
+	}
 
 	// Set this flag, so it acts as a constant.  We will set s.LeaderNewMin to false
 	// after processing the Holding Queue.  Ensures we only do this one per minute.
 	//	processMinute := s.LeaderNewMin // Have we processed this minute
 	s.LeaderNewMin++ // Either way, don't do it again until the ProcessEOM resets LeaderNewMin
 
-	for k, v := range s.Holding {
-
+	review := func(k [32]byte, v interfaces.IMsg) {
 		if v.Expire(s) {
 			s.LogMessage("executeMsg", "expire from holding", v)
 			s.ExpireCnt++
 			TotalHoldingQueueOutputs.Inc()
 			delete(s.Holding, k)
-			continue
+			return
 		}
 
 		switch v.Validate(s) {
@@ -456,9 +466,9 @@ func (s *State) ReviewHolding() {
 			s.LogMessage("executeMsg", "invalid from holding", v)
 			TotalHoldingQueueOutputs.Inc()
 			delete(s.Holding, k)
-			continue
+			return
 		case 0:
-			continue
+			return
 		}
 
 		if v.GetResendCnt() == 0 {
@@ -478,21 +488,21 @@ func (s *State) ReviewHolding() {
 		if ok && ((eom.DBHeight <= saved && saved > 0) || int(eom.Minute) < s.CurrentMinute) {
 			TotalHoldingQueueOutputs.Inc()
 			delete(s.Holding, k)
-			continue
+			return
 		}
 
 		dbsmsg, ok := v.(*messages.DBStateMsg)
 		if ok && (dbsmsg.DirectoryBlock.GetHeader().GetDBHeight() < saved-1 && saved > 0) {
 			TotalHoldingQueueOutputs.Inc()
 			delete(s.Holding, k)
-			continue
+			return
 		}
 
 		dbsigmsg, ok := v.(*messages.DirectoryBlockSignature)
 		if ok && ((dbsigmsg.DBHeight <= saved && saved > 0) || (dbsigmsg.DBHeight < highest-3 && highest > 2)) {
 			TotalHoldingQueueOutputs.Inc()
 			delete(s.Holding, k)
-			continue
+			return
 		}
 
 		_, ok = s.Replay.Valid(constants.INTERNAL_REPLAY, v.GetRepeatHash().Fixed(), v.GetTimestamp(), s.GetTimestamp())
@@ -500,7 +510,7 @@ func (s *State) ReviewHolding() {
 		if !ok || !ok2 {
 			TotalHoldingQueueOutputs.Inc()
 			delete(s.Holding, k)
-			continue
+			return
 		}
 
 		// If it is an entryCommit/ChainCommit/RevealEntry and it has a duplicate hash to an existing entry throw it away here
@@ -511,7 +521,7 @@ func (s *State) ReviewHolding() {
 			if !x {
 				TotalHoldingQueueOutputs.Inc()
 				delete(s.Holding, k) // Drop commits with the same entry hash from holding because they are blocked by a previous entry
-				continue
+				return
 			}
 		}
 
@@ -523,7 +533,7 @@ func (s *State) ReviewHolding() {
 			if !x {
 				TotalHoldingQueueOutputs.Inc()
 				delete(s.Holding, k) // Drop commits with the same entry hash from holding because they are blocked by a previous entry
-				continue
+				return
 			}
 		}
 
@@ -532,27 +542,38 @@ func (s *State) ReviewHolding() {
 			if !s.NoEntryYet(re.GetHash(), s.GetLeaderTimestamp()) {
 				delete(s.Holding, re.GetHash().Fixed())
 				s.Commits.Delete(re.GetHash().Fixed())
-				continue
+				return
 			}
 			// Only reprocess if at the top of a new minute, and if we are a leader.
 			//if processMinute > 10 {
-			//	continue // No need for followers to review Reveal Entry messages
+			//	return // No need for followers to review Reveal Entry messages
 			//}
 			// Needs to be our VMIndex as well, or ignore.
 			if re.GetVMIndex() != s.LeaderVMIndex || !s.Leader {
-				continue // If we are a leader, but it isn't ours, and it isn't a new minute, ignore.
+				return // If we are a leader, but it isn't ours, and it isn't a new minute, ignore.
 			}
 		}
 		//TODO: Move this earlier!
 		// We don't reprocess messages if we are a leader, but it ain't ours!
 		if s.LeaderVMIndex != v.GetVMIndex() {
-			continue
+			return
 		}
 
 		TotalXReviewQueueInputs.Inc()
 		s.XReview = append(s.XReview, v)
 		TotalHoldingQueueOutputs.Inc()
 	}
+	if s.ReplayCheck != nil {
+		for _, k := range s.mapOrder(s.Holding) {
+			if v, ok := s.Holding[k]; ok {
+				review(k, v)
+			}
+		}
+	} else {
+		for k, v := range s.Holding {
+			review(k, v)
+		}
+	}
 	reviewHoldingTime := time.Since(preReviewHoldingTime)
 	TotalReviewHoldingTime.Add(float64(reviewHoldingTime.Nanoseconds()))
 }
@@ -1748,6 +1769,7 @@ func (s *State) ProcessEOM(dbheight uint32, msg interfaces.IMsg) bool {
 		//fmt.Println(fmt.Sprintf("SigType PROCESS: %10s vm %2d Process Once: !e.Processed(%v) SigType: %s", s.FactomNodeName, e.VMIndex, e.Processed, e.String()))
 		vm.LeaderMinute++
 		s.EOMProcessed++
+		s.LogMsgEvent(eventLog.EOMProcessed, dbheight, s.CurrentMinute, msg.GetVMIndex(), -1, msg, fmt.Sprintf("minute %d, %d of %d", e.Minute, s.EOMProcessed, s.EOMLimit))
 		//fmt.Println(fmt.Sprintf("EOM PROCESS: %10s vm %2d EOMProcessed++ (%2d)", s.FactomNodeName, e.VMIndex, s.EOMProcessed))
 		vm.Synced = true
 		markNoFault(pl, msg.GetVMIndex())
@@ -2105,6 +2127,7 @@ func (s *State) ProcessDBSig(dbheight uint32, msg interfaces.IMsg) bool {
 		s.AddDBSig(dbheight, dbs.ServerIdentityChainID, dbs.DBSignature)
 
 		s.DBSigProcessed++
+		s.LogMsgEvent(eventLog.DBSigProcessed, dbheight, s.CurrentMinute, dbs.VMIndex, -1, msg, fmt.Sprintf("%d of %d", s.DBSigProcessed, s.DBSigLimit))
 		//fmt.Println(fmt.Sprintf("Process DBSig %10s vm %2v DBSigProcessed++ (%2d)", s.FactomNodeName, dbs.VMIndex, s.DBSigProcessed))
 		vm.Synced = true
 
@@ -19,6 +19,8 @@ import (
 
 // Go through the factoid exchange rate chain and determine if an FER change should be scheduled
 func (this *State) ProcessRecentFERChainEntries() {
+	defer this.PublishFERChange()
+
 	// Find the FER entry chain
 	FERChainHash, err := primitives.HexToHash(this.FERChainId)
 	if err != nil {
@@ -215,3 +217,24 @@ func (this *State) GetPredictiveFER() uint64 {
 
 	return this.FERChangePrice
 }
+
+// Publishes the pending exchange rate change for GetFERChange, which is called off the
+// state goroutine.
+func (this *State) PublishFERChange() {
+	this.ferChangeMutex.Lock()
+	defer this.ferChangeMutex.Unlock()
+	this.publishedFERChangePrice = this.FERChangePrice
+	this.publishedFERChangeHeight = this.FERChangeHeight
+}
+
+// Returns the exchange rate a FER entry scheduled, and the block height it activates at,
+// as last published.  The height is 0 if no change is pending.
+func (this *State) GetFERChange() (uint64, uint32) {
+	this.ferChangeMutex.RLock()
+	defer this.ferChangeMutex.RUnlock()
+	// A height of 1 marks a change that was already applied
+	if this.publishedFERChangeHeight <= 1 {
+		return 0, 0
+	}
+	return this.publishedFERChangePrice, this.publishedFERChangeHeight
+}
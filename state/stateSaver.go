@@ -16,6 +16,8 @@ import (
 type StateSaverStruct struct {
 	FastBoot         bool
 	FastBootLocation string
+	// Cipher encrypts the save state of an encrypted database, nil for a plain one
+	Cipher DataCipher
 
 	TmpState []byte
 	Mutex    sync.Mutex
@@ -65,6 +67,12 @@ func (sss *StateSaverStruct) SaveDBStateList(ss *DBStateList, networkName string
 	//adding an integrity check
 	h := primitives.Sha(b)
 	b = append(h.Bytes(), b...)
+	if sss.Cipher != nil {
+		b, err = sss.Cipher.Encrypt(b)
+		if err != nil {
+			return err
+		}
+	}
 	sss.TmpState = b
 
 	return nil
@@ -82,6 +90,13 @@ func (sss *StateSaverStruct) LoadDBStateList(ss *DBStateList, networkName string
 	if b == nil {
 		return nil
 	}
+	if sss.Cipher != nil {
+		// A save state of another key or of a plain database is not used
+		b, err = sss.Cipher.Decrypt(b)
+		if err != nil {
+			return nil
+		}
+	}
 	h := primitives.NewZeroHash()
 	b, err = h.UnmarshalBinaryData(b)
 	if err != nil {
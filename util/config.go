@@ -24,13 +24,21 @@ type FactomdConfig struct {
 		DBType                                 string
 		LdbPath                                string
 		BoltDBPath                             string
+		BadgerDBPath                           string
+		DBPasswordSource                       string
 		DataStorePath                          string
 		DirectoryBlockInSeconds                int
 		ExportData                             bool
 		ExportDataSubpath                      string
+		AddressIndex                           bool
+		ExtIDIndex                             bool
+		BalanceHistory                         bool
+		ChainInfo                              bool
+		SnapshotPath                           string
 		FastBoot                               bool
 		FastBootLocation                       string
 		NodeMode                               string
+		PruneDepth                             uint32
 		IdentityChainID                        string
 		LocalServerPrivKey                     string
 		LocalServerPublicKey                   string
@@ -40,6 +48,7 @@ type FactomdConfig struct {
 		ExchangeRateAuthorityPublicKeyMainNet  string
 		ExchangeRateAuthorityPublicKeyTestNet  string
 		ExchangeRateAuthorityPublicKeyLocalNet string
+		AnchorSigningKeys                      string
 
 		// Network Configuration
 		Network                 string
@@ -56,6 +65,8 @@ type FactomdConfig struct {
 		CustomNetworkPort       string
 		CustomSeedURL           string
 		CustomSpecialPeers      string
+		P2PEncryption           bool
+		P2PKeyFile              string
 		CustomBootstrapIdentity string
 		CustomBootstrapKey      string
 		FactomdTlsEnabled       bool
@@ -63,6 +74,13 @@ type FactomdConfig struct {
 		FactomdTlsPublicCert    string
 		FactomdRpcUser          string
 		FactomdRpcPass          string
+		APIReadRate             float64
+		APIReadBurst            int
+		APISubmitRate           float64
+		APISubmitBurst          int
+		APIUsersFile            string
+		GRPCEnabled             bool
+		GRPCPort                int
 
 		ChangeAcksHeight uint32
 	}
@@ -111,14 +129,27 @@ HomeDir                               = ""
 ; --------------- ControlPanel disabled | readonly | readwrite
 ControlPanelSetting                   = readonly
 ControlPanelPort                      = 8090
-; --------------- DBType: LDB | Bolt | Map
+; --------------- DBType: LDB | Bolt | Badger | Map | EncryptedLDB | EncryptedBolt
 DBType                                = "LDB"
 LdbPath                               = "database/ldb"
 BoltDBPath                            = "database/bolt"
+BadgerDBPath                          = "database/badger"
+; --------------- DBPasswordSource: password of the EncryptedLDB and EncryptedBolt databases: env:VARIABLE | file:PATH | prompt
+DBPasswordSource                      = "prompt"
 DataStorePath                         = "data/export"
 DirectoryBlockInSeconds               = 6
 ExportData                            = false
 ExportDataSubpath                     = "database/export/"
+; --------------- AddressIndex: index the transactions of every address, for the address-transactions API
+AddressIndex                          = false
+; --------------- ExtIDIndex: index the entries of every chain by their external IDs, for the entries-by-extid API
+ExtIDIndex                            = false
+; --------------- BalanceHistory: keep the balance of every address at every height, for the balance-at-height API
+BalanceHistory                        = false
+; --------------- ChainInfo: keep the statistics of every chain, for the chain-info API
+ChainInfo                             = false
+; --------------- SnapshotPath: where database snapshots taken through the debug API are written
+SnapshotPath                          = "snapshots"
 FastBoot                              = true
 FastBootLocation                      = ""
 ; --------------- Network: MAIN | TEST | LOCAL
@@ -136,10 +167,15 @@ LocalSpecialPeers    = ""
 CustomNetworkPort    = 8110
 CustomSeedURL        = ""
 CustomSpecialPeers   = ""
+; --------------- P2PEncryption: encrypt and authenticate all peer connections. Special peers can be pinned to a node key with ip:port@<key>
+P2PEncryption        = false
+P2PKeyFile           = "p2pkey.txt"
 CustomBootstrapIdentity     = 38bab1455b7bd7e5efd15c53c777c79d0c988e9210f1da49a99d95b3a6417be9
 CustomBootstrapKey          = cc1985cdfae4e32b5a454dfda8ce5e1361558482684f3367649c3ad852c8e31a
-; --------------- NodeMode: FULL | SERVER ----------------
+; --------------- NodeMode: FULL | SERVER | PRUNED ----------------
 NodeMode                                = FULL
+; --------------- PruneDepth: in PRUNED mode, entry content older than this many directory blocks is deleted
+PruneDepth                              = 4320
 LocalServerPrivKey                      = 4c38c72fc5cdad68f13b74674d3ffb1f3d63a112710868c9b08946553448d26d
 LocalServerPublicKey                    = cc1985cdfae4e32b5a454dfda8ce5e1361558482684f3367649c3ad852c8e31a
 ExchangeRateChainId                     = 111111118d918a8be684e0dac725493a75862ef96d2d3f43f84b26969329bf03
@@ -147,6 +183,8 @@ ExchangeRateAuthorityPublicKeyMainNet   = daf5815c2de603dbfa3e1e64f88a5cf0608330
 ExchangeRateAuthorityPublicKeyTestNet   = 1d75de249c2fc0384fb6701b30dc86b39dc72e5a47ba4f79ef250d39e21e7a4f
 ; Private key all zeroes:
 ExchangeRateAuthorityPublicKeyLocalNet  = 3b6a27bcceb6a42d62a3a8d02a6f0d73653215771de243a63ac048a18b59da29
+; --------------- AnchorSigningKeys: comma separated public keys anchor records must be signed with, the mainnet keys if empty
+AnchorSigningKeys                       = ""
 
 ; These define if the RPC and Control Panel connection to factomd should be encrypted, and if it is, what files
 ; are the secret key and the public certificate.  factom-cli and factom-walletd uses the certificate specified here if TLS is enabled.
@@ -160,6 +198,22 @@ FactomdTlsPublicCert                  = "/full/path/to/factomdAPIpub.cert"
 FactomdRpcUser                        = ""
 FactomdRpcPass                        = ""
 
+; Rate limits of the API, per remote IP and per RPC user.  The rates are calls per second, and the bursts the calls
+; allowed at once.  Submits (commits, reveals, factoid-submit and send-raw-message) have their own limit.  0 is no limit
+APIReadRate                           = 0
+APIReadBurst                          = 0
+APISubmitRate                         = 0
+APISubmitBurst                        = 0
+
+; A JSON file of further API users, each with a password or bearer token, and the methods it may call.  The file is
+; read again by the reload-configuration debug call
+APIUsersFile                          = ""
+
+; The gRPC API offers the calls of the v2 API, and streams of new blocks.  It uses the TLS settings and the users
+; of the JSON-RPC API
+GRPCEnabled                           = false
+GRPCPort                              = 8091
+
 ; Specifying when to change ACKs for switching leader servers
 ChangeAcksHeight                      = 0
 
@@ -210,10 +264,17 @@ func (s *FactomdConfig) String() string {
 	out.WriteString(fmt.Sprintf("\n    DBType                  %v", s.App.DBType))
 	out.WriteString(fmt.Sprintf("\n    LdbPath                 %v", s.App.LdbPath))
 	out.WriteString(fmt.Sprintf("\n    BoltDBPath              %v", s.App.BoltDBPath))
+	out.WriteString(fmt.Sprintf("\n    BadgerDBPath            %v", s.App.BadgerDBPath))
+	out.WriteString(fmt.Sprintf("\n    DBPasswordSource        %v", s.App.DBPasswordSource))
 	out.WriteString(fmt.Sprintf("\n    DataStorePath           %v", s.App.DataStorePath))
 	out.WriteString(fmt.Sprintf("\n    DirectoryBlockInSeconds %v", s.App.DirectoryBlockInSeconds))
 	out.WriteString(fmt.Sprintf("\n    ExportData              %v", s.App.ExportData))
 	out.WriteString(fmt.Sprintf("\n    ExportDataSubpath       %v", s.App.ExportDataSubpath))
+	out.WriteString(fmt.Sprintf("\n    AddressIndex            %v", s.App.AddressIndex))
+	out.WriteString(fmt.Sprintf("\n    ExtIDIndex              %v", s.App.ExtIDIndex))
+	out.WriteString(fmt.Sprintf("\n    BalanceHistory          %v", s.App.BalanceHistory))
+	out.WriteString(fmt.Sprintf("\n    ChainInfo               %v", s.App.ChainInfo))
+	out.WriteString(fmt.Sprintf("\n    SnapshotPath            %v", s.App.SnapshotPath))
 	out.WriteString(fmt.Sprintf("\n    Network                 %v", s.App.Network))
 	out.WriteString(fmt.Sprintf("\n    MainNetworkPort         %v", s.App.MainNetworkPort))
 	out.WriteString(fmt.Sprintf("\n    PeersFile               %v", s.App.PeersFile))
@@ -228,20 +289,31 @@ func (s *FactomdConfig) String() string {
 	out.WriteString(fmt.Sprintf("\n    CustomNetworkPort       %v", s.App.CustomNetworkPort))
 	out.WriteString(fmt.Sprintf("\n    CustomSeedURL           %v", s.App.CustomSeedURL))
 	out.WriteString(fmt.Sprintf("\n    CustomSpecialPeers      %v", s.App.CustomSpecialPeers))
+	out.WriteString(fmt.Sprintf("\n    P2PEncryption           %v", s.App.P2PEncryption))
+	out.WriteString(fmt.Sprintf("\n    P2PKeyFile              %v", s.App.P2PKeyFile))
 	out.WriteString(fmt.Sprintf("\n    CustomBootstrapIdentity %v", s.App.CustomBootstrapIdentity))
 	out.WriteString(fmt.Sprintf("\n    CustomBootstrapKey      %v", s.App.CustomBootstrapKey))
 	out.WriteString(fmt.Sprintf("\n    NodeMode                %v", s.App.NodeMode))
+	out.WriteString(fmt.Sprintf("\n    PruneDepth              %v", s.App.PruneDepth))
 	out.WriteString(fmt.Sprintf("\n    IdentityChainID         %v", s.App.IdentityChainID))
 	out.WriteString(fmt.Sprintf("\n    LocalServerPrivKey      %v", s.App.LocalServerPrivKey))
 	out.WriteString(fmt.Sprintf("\n    LocalServerPublicKey    %v", s.App.LocalServerPublicKey))
 	out.WriteString(fmt.Sprintf("\n    ExchangeRate            %v", s.App.ExchangeRate))
 	out.WriteString(fmt.Sprintf("\n    ExchangeRateChainId     %v", s.App.ExchangeRateChainId))
 	out.WriteString(fmt.Sprintf("\n    ExchangeRateAuthorityPublicKey   %v", s.App.ExchangeRateAuthorityPublicKey))
+	out.WriteString(fmt.Sprintf("\n    AnchorSigningKeys       %v", s.App.AnchorSigningKeys))
 	out.WriteString(fmt.Sprintf("\n    FactomdTlsEnabled        %v", s.App.FactomdTlsEnabled))
 	out.WriteString(fmt.Sprintf("\n    FactomdTlsPrivateKey     %v", s.App.FactomdTlsPrivateKey))
 	out.WriteString(fmt.Sprintf("\n    FactomdTlsPublicCert     %v", s.App.FactomdTlsPublicCert))
 	out.WriteString(fmt.Sprintf("\n    FactomdRpcUser          	%v", s.App.FactomdRpcUser))
 	out.WriteString(fmt.Sprintf("\n    FactomdRpcPass          	%v", s.App.FactomdRpcPass))
+	out.WriteString(fmt.Sprintf("\n    APIReadRate             %v", s.App.APIReadRate))
+	out.WriteString(fmt.Sprintf("\n    APIReadBurst            %v", s.App.APIReadBurst))
+	out.WriteString(fmt.Sprintf("\n    APISubmitRate           %v", s.App.APISubmitRate))
+	out.WriteString(fmt.Sprintf("\n    APISubmitBurst          %v", s.App.APISubmitBurst))
+	out.WriteString(fmt.Sprintf("\n    APIUsersFile            %v", s.App.APIUsersFile))
+	out.WriteString(fmt.Sprintf("\n    GRPCEnabled             %v", s.App.GRPCEnabled))
+	out.WriteString(fmt.Sprintf("\n    GRPCPort                %v", s.App.GRPCPort))
 	out.WriteString(fmt.Sprintf("\n    ChangeAcksHeight         %v", s.App.ChangeAcksHeight))
 
 	out.WriteString(fmt.Sprintf("\n  Log"))
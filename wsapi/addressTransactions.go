@@ -0,0 +1,130 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+const (
+	// AddressTransactionsDefaultLimit is used when the request does not set a limit
+	AddressTransactionsDefaultLimit = 100
+	// AddressTransactionsMaxLimit caps the number of transactions returned by one call
+	AddressTransactionsMaxLimit = 1000
+)
+
+// addressTxCursor is the position of a transaction in the history of an address, its
+// key in the address index.  It is handed to clients as 74 hex characters.
+func addressTxCursor(tx interfaces.IAddressTransaction) []byte {
+	return databaseOverlay.AddressTransactionKey(tx.GetDBHeight(), tx.GetTxID(), tx.IsInput())
+}
+
+func parseAddressTxCursor(s string) ([]byte, bool) {
+	c, err := hex.DecodeString(s)
+	if err != nil || len(c) != 4+constants.HASH_LENGTH+1 {
+		return nil, false
+	}
+	return c, true
+}
+
+// HandleV2AddressTransactions returns a page of the transactions that touched a
+// factoid or entry credit address, along with the cursor to use to fetch the next page.
+// The node must run with the address index enabled.
+func HandleV2AddressTransactions(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallAddressTransactions.Observe(float64(time.Since(n).Nanoseconds()))
+
+	req := new(AddressTransactionsRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	address := addressToHash(req.Address)
+	if address == nil {
+		return nil, NewInvalidAddressError()
+	}
+
+	backward := false
+	switch req.Direction {
+	case "", "forward":
+	case "backward":
+		backward = true
+	default:
+		return nil, NewCustomInvalidParamsError("Invalid direction, expected 'forward' or 'backward'")
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = AddressTransactionsDefaultLimit
+	}
+	if limit > AddressTransactionsMaxLimit {
+		limit = AddressTransactionsMaxLimit
+	}
+
+	dbase := state.GetDB()
+	if !dbase.IsAddressIndexEnabled() {
+		return nil, NewAddressIndexDisabledError()
+	}
+
+	var start []byte
+	if req.Cursor != "" {
+		c, ok := parseAddressTxCursor(req.Cursor)
+		if !ok {
+			return nil, NewCustomInvalidParamsError("Invalid cursor")
+		}
+		start = c
+	} else if req.Height != nil {
+		height := uint32(*req.Height)
+		start = databaseOverlay.AddressTransactionKey(height, nil, false)
+		if backward {
+			// Start from the last transaction at or below the height, every key of
+			// the next height sorts after its bare height
+			start = nil
+			if height < math.MaxUint32 {
+				start = databaseOverlay.AddressTransactionKey(height+1, nil, false)
+			}
+		}
+	}
+
+	// One more than the page, for the cursor of the next page
+	txs, err := dbase.FetchAddressTransactions(address, start, backward, limit+1)
+	if err != nil {
+		return nil, NewInternalDatabaseError()
+	}
+	if req.Cursor != "" && (len(txs) == 0 || !bytes.Equal(addressTxCursor(txs[0]), start)) {
+		return nil, NewCustomInvalidParamsError("Invalid cursor")
+	}
+
+	resp := new(AddressTransactionsResponse)
+	resp.Address = req.Address
+	resp.Transactions = make([]AddressTransaction, 0)
+
+	for _, tx := range txs {
+		if len(resp.Transactions) == limit {
+			resp.NextCursor = hex.EncodeToString(addressTxCursor(tx))
+			break
+		}
+		t := AddressTransaction{}
+		t.TxID = tx.GetTxID().String()
+		t.DBHeight = int64(tx.GetDBHeight())
+		t.Direction = "output"
+		if tx.IsInput() {
+			t.Direction = "input"
+		}
+		t.Amount = tx.GetAmount()
+		resp.Transactions = append(resp.Transactions, t)
+	}
+
+	return resp, nil
+}
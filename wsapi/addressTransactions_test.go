@@ -0,0 +1,112 @@
+package wsapi_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func TestHandleV2AddressTransactions(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
+
+	// The most used address of the test blocks
+	counts := map[string]int{}
+	address := ""
+	for _, block := range testHelper.CreateFullTestBlockSet() {
+		for _, tx := range block.FBlock.GetTransactions() {
+			for _, out := range tx.GetOutputs() {
+				adr := primitives.ConvertFctAddressToUserStr(out.GetAddress())
+				counts[adr]++
+				if counts[adr] > counts[address] {
+					address = adr
+				}
+			}
+		}
+	}
+	if counts[address] < 3 {
+		t.Fatalf("Test address only has %d transactions", counts[address])
+	}
+
+	req := new(AddressTransactionsRequest)
+	req.Address = address
+	if _, jErr := HandleV2AddressTransactions(state, req); jErr == nil {
+		t.Error("Expected an error with the address index disabled")
+	}
+
+	state.GetDB().SetAddressIndex(true)
+	if err := state.GetDB().BackfillAddressIndex(); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, jErr := HandleV2AddressTransactions(state, req)
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	expected := resp.(*AddressTransactionsResponse).Transactions
+	if len(expected) < counts[address] {
+		t.Fatalf("Got %d transactions, expected at least %d", len(expected), counts[address])
+	}
+
+	// Walk the history forward and backward, two transactions at a time
+	for _, direction := range []string{"forward", "backward"} {
+		got := []AddressTransaction{}
+		req = new(AddressTransactionsRequest)
+		req.Address = address
+		req.Direction = direction
+		req.Limit = 2
+		for i := 0; ; i++ {
+			if i > len(expected) {
+				t.Fatal("Cursor never ran out")
+			}
+			resp, jErr := HandleV2AddressTransactions(state, req)
+			if jErr != nil {
+				t.Fatalf("%v", jErr)
+			}
+			r := resp.(*AddressTransactionsResponse)
+			got = append(got, r.Transactions...)
+			if r.NextCursor == "" {
+				break
+			}
+			req.Cursor = r.NextCursor
+		}
+		if len(got) != len(expected) {
+			t.Fatalf("Got %d transactions walking %s, expected %d", len(got), direction, len(expected))
+		}
+		for i := range expected {
+			j := i
+			if direction == "backward" {
+				j = len(got) - 1 - i
+			}
+			if got[j] != expected[i] {
+				t.Errorf("Transaction %d is %v, expected %v", i, got[j], expected[i])
+			}
+		}
+	}
+
+	height := expected[len(expected)-1].DBHeight
+	req = new(AddressTransactionsRequest)
+	req.Address = address
+	req.Height = &height
+	resp, jErr = HandleV2AddressTransactions(state, req)
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	for _, tx := range resp.(*AddressTransactionsResponse).Transactions {
+		if tx.DBHeight < height {
+			t.Errorf("Got a transaction at height %d, below %d", tx.DBHeight, height)
+		}
+	}
+
+	req.Height = nil
+	req.Cursor = "zz"
+	if _, jErr := HandleV2AddressTransactions(state, req); jErr == nil {
+		t.Error("Expected an error for an invalid cursor")
+	}
+	req.Cursor = ""
+	req.Address = "not an address"
+	if _, jErr := HandleV2AddressTransactions(state, req); jErr == nil {
+		t.Error("Expected an error for an invalid address")
+	}
+}
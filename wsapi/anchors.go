@@ -0,0 +1,84 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"math"
+	"time"
+
+	"github.com/FactomProject/factomd/anchor"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// HandleV2Anchors returns the Bitcoin and Ethereum transactions anchoring the
+// directory block at a height.  A network the block is not anchored on yet is null.
+// Only anchor records signed by an anchor signing key, and anchoring the KeyMR of the
+// block, are returned.
+func HandleV2Anchors(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallAnchors.Observe(float64(time.Since(n).Nanoseconds()))
+
+	heightRequest := new(HeightRequest)
+	err := MapToObject(params, heightRequest)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	if heightRequest.Height < 0 || heightRequest.Height > math.MaxUint32 {
+		return nil, NewInvalidParamsError()
+	}
+	height := uint32(heightRequest.Height)
+
+	dbase := state.GetDB()
+	keyMR, err := dbase.FetchDBKeyMRByHeight(height)
+	if err != nil {
+		return nil, NewInternalDatabaseError()
+	}
+	if keyMR == nil {
+		return nil, NewBlockNotFoundError()
+	}
+	entries, err := dbase.FetchAnchorEntries(height)
+	if err != nil {
+		return nil, NewInternalDatabaseError()
+	}
+
+	resp := new(AnchorsResponse)
+	resp.Height = height
+	resp.KeyMR = keyMR.String()
+	for _, entry := range entries {
+		ar, valid, err := anchor.UnmarshalAndValidateAnchorEntryAnyVersion(entry, databaseOverlay.AnchorSigPublicKeys)
+		if err != nil || !valid || ar == nil {
+			continue
+		}
+		if ar.KeyMR != resp.KeyMR || ar.DBHeight != height {
+			continue
+		}
+		if ar.Bitcoin != nil && resp.Bitcoin == nil {
+			b := new(BitcoinAnchor)
+			b.EntryHash = entry.GetHash().String()
+			b.RecordHeight = ar.RecordHeight
+			b.Address = ar.Bitcoin.Address
+			b.TransactionHash = ar.Bitcoin.TXID
+			b.BlockHeight = ar.Bitcoin.BlockHeight
+			b.BlockHash = ar.Bitcoin.BlockHash
+			b.Offset = ar.Bitcoin.Offset
+			resp.Bitcoin = b
+		}
+		if ar.Ethereum != nil && resp.Ethereum == nil {
+			e := new(EthereumAnchor)
+			e.EntryHash = entry.GetHash().String()
+			e.RecordHeight = ar.RecordHeight
+			e.Address = ar.Ethereum.Address
+			e.TransactionHash = ar.Ethereum.TXID
+			e.BlockHeight = ar.Ethereum.BlockHeight
+			e.BlockHash = ar.Ethereum.BlockHash
+			e.Offset = ar.Ethereum.Offset
+			resp.Ethereum = e
+		}
+	}
+
+	return resp, nil
+}
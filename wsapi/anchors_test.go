@@ -0,0 +1,41 @@
+package wsapi_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func TestHandleV2Anchors(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
+
+	for h := 0; h < testHelper.BlockCount; h++ {
+		resp, jErr := HandleV2Anchors(state, HeightRequest{Height: int64(h)})
+		if jErr != nil {
+			t.Fatalf("%v", jErr)
+		}
+		anchors := resp.(*AnchorsResponse)
+		keyMR, err := state.GetDB().FetchDBKeyMRByHeight(uint32(h))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if anchors.Height != uint32(h) || anchors.KeyMR != keyMR.String() {
+			t.Errorf("Anchors of block %d are for block %d %s", h, anchors.Height, anchors.KeyMR)
+		}
+		if anchors.Ethereum != nil {
+			t.Errorf("Block %d has an Ethereum anchor", h)
+		}
+		// The last block is not anchored yet
+		if (anchors.Bitcoin == nil) != (h == testHelper.BlockCount-1) {
+			t.Errorf("Block %d has the Bitcoin anchor %v", h, anchors.Bitcoin)
+		}
+	}
+
+	if _, jErr := HandleV2Anchors(state, HeightRequest{Height: int64(testHelper.BlockCount + 10)}); jErr == nil {
+		t.Error("Expected an error for a block past the head")
+	}
+	if _, jErr := HandleV2Anchors(state, HeightRequest{Height: -1}); jErr == nil {
+		t.Error("Expected an error for a negative height")
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// HandleV2BalanceAtHeight returns the balance of a factoid or entry credit address
+// once the blocks of a height were applied.  The address must be a user address (FA
+// or EC), as it tells which balance is asked for.  The node must run with the balance
+// history enabled.
+func HandleV2BalanceAtHeight(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallBalanceAtHeight.Observe(float64(time.Since(n).Nanoseconds()))
+
+	req := new(BalanceAtHeightRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	var ec bool
+	switch {
+	case primitives.ValidateFUserStr(req.Address):
+	case primitives.ValidateECUserStr(req.Address):
+		ec = true
+	default:
+		return nil, NewInvalidAddressError()
+	}
+	address := primitives.NewHash(primitives.ConvertUserStrToAddress(req.Address))
+
+	if req.Height < 0 {
+		return nil, NewCustomInvalidParamsError("Invalid height")
+	}
+
+	dbase := state.GetDB()
+	if !dbase.IsBalanceHistoryEnabled() {
+		return nil, NewBalanceHistoryDisabledError()
+	}
+	next, err := dbase.FetchBalanceHistoryHeight()
+	if err != nil {
+		return nil, NewInternalDatabaseError()
+	}
+	if req.Height >= int64(next) {
+		return nil, NewCustomInvalidParamsError("Height not in the balance history yet")
+	}
+
+	balance, err := dbase.FetchBalanceAtHeight(ec, address, uint32(req.Height))
+	if err != nil {
+		return nil, NewInternalDatabaseError()
+	}
+
+	resp := new(BalanceAtHeightResponse)
+	resp.Address = req.Address
+	resp.Height = req.Height
+	resp.Balance = balance
+	return resp, nil
+}
@@ -0,0 +1,101 @@
+package wsapi_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func TestHandleV2BalanceAtHeight(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
+	blocks := testHelper.CreateFullTestBlockSet()
+
+	// The balances of the addresses after every height of the test blocks
+	fct := []map[string]int64{}
+	ec := []map[string]int64{}
+	fctBal := map[string]int64{}
+	ecBal := map[string]int64{}
+	for _, block := range blocks {
+		for _, tx := range block.FBlock.GetTransactions() {
+			for _, in := range tx.GetInputs() {
+				fctBal[primitives.ConvertFctAddressToUserStr(in.GetAddress())] -= int64(in.GetAmount())
+			}
+			for _, out := range tx.GetOutputs() {
+				fctBal[primitives.ConvertFctAddressToUserStr(out.GetAddress())] += int64(out.GetAmount())
+			}
+		}
+		for _, entry := range block.ECBlock.GetEntries() {
+			if entry.ECID() == constants.ECIDBalanceIncrease {
+				ib := entry.(*entryCreditBlock.IncreaseBalance)
+				ecBal[primitives.ConvertECAddressToUserStr(factoid.NewAddress(ib.ECPubKey[:]))] += int64(ib.NumEC)
+			}
+			if entry.ECID() == constants.ECIDEntryCommit {
+				ce := entry.(*entryCreditBlock.CommitEntry)
+				ecBal[primitives.ConvertECAddressToUserStr(factoid.NewAddress(ce.ECPubKey[:]))] -= int64(ce.Credits)
+			}
+			if entry.ECID() == constants.ECIDChainCommit {
+				cc := entry.(*entryCreditBlock.CommitChain)
+				ecBal[primitives.ConvertECAddressToUserStr(factoid.NewAddress(cc.ECPubKey[:]))] -= int64(cc.Credits)
+			}
+		}
+		f := map[string]int64{}
+		for k, v := range fctBal {
+			f[k] = v
+		}
+		e := map[string]int64{}
+		for k, v := range ecBal {
+			e[k] = v
+		}
+		fct = append(fct, f)
+		ec = append(ec, e)
+	}
+
+	req := new(BalanceAtHeightRequest)
+	for k := range fctBal {
+		req.Address = k
+	}
+	if _, jErr := HandleV2BalanceAtHeight(state, req); jErr == nil || jErr.Code != -32019 {
+		t.Errorf("Expected the balance history disabled error, got %v", jErr)
+	}
+
+	state.GetDB().SetBalanceHistory(true)
+	if err := state.GetDB().BackfillBalanceHistory(); err != nil {
+		t.Fatal(err)
+	}
+
+	for height := range blocks {
+		for _, balances := range [][]map[string]int64{fct, ec} {
+			for address := range balances[len(blocks)-1] {
+				req.Address = address
+				req.Height = int64(height)
+				resp, jErr := HandleV2BalanceAtHeight(state, req)
+				if jErr != nil {
+					t.Fatalf("%v", jErr)
+				}
+				r := resp.(*BalanceAtHeightResponse)
+				if r.Balance != balances[height][address] || r.Address != address || r.Height != req.Height {
+					t.Errorf("Got %v, expected a balance of %d", r, balances[height][address])
+				}
+			}
+		}
+	}
+
+	req.Height = 1 << 30
+	if _, jErr := HandleV2BalanceAtHeight(state, req); jErr == nil {
+		t.Error("Expected an error for a height not in the balance history")
+	}
+	req.Height = -1
+	if _, jErr := HandleV2BalanceAtHeight(state, req); jErr == nil {
+		t.Error("Expected an error for a negative height")
+	}
+	req.Height = 0
+	req.Address = "not an address"
+	if _, jErr := HandleV2BalanceAtHeight(state, req); jErr == nil {
+		t.Error("Expected an error for an invalid address")
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/web"
+)
+
+const (
+	// MaxBatchSize is the most requests one JSON-RPC batch may hold
+	MaxBatchSize = 100
+	// BatchConcurrency is the most requests of one batch that run at once
+	BatchConcurrency = 8
+)
+
+// RequestHandler answers one JSON-RPC request, as HandleV2Request and
+// HandleDebugRequest do
+type RequestHandler func(interfaces.IState, *primitives.JSON2Request) (*primitives.JSON2Response, *primitives.JSONError)
+
+// IsBatchRequest is true if the body is a JSON array
+func IsBatchRequest(body []byte) bool {
+	body = bytes.TrimLeft(body, " \t\r\n")
+	return len(body) > 0 && body[0] == '['
+}
+
+// HandleBatch answers a JSON-RPC 2.0 batch.  Errors of single requests are returned
+// in their responses; only a batch that can't be parsed at all is an HTTP error.
+func HandleBatch(ctx *web.Context, state interfaces.IState, body []byte, handler RequestHandler) {
+	responses, jsonError := HandleBatchRequest(state, body, handler)
+	if jsonError != nil {
+		HandleV2Error(ctx, nil, jsonError)
+		return
+	}
+	// A batch of notifications gets no response at all
+	if len(responses) == 0 {
+		return
+	}
+	data, err := json.Marshal(responses)
+	if err != nil {
+		HandleV2Error(ctx, nil, NewInternalError())
+		return
+	}
+	ctx.Write(data)
+}
+
+// HandleBatchRequest runs the requests of a batch, at most BatchConcurrency at a time,
+// and returns their responses in the order of the requests.  Requests without an id
+// are notifications: they are run, but get no response.
+func HandleBatchRequest(state interfaces.IState, body []byte, handler RequestHandler) ([]*primitives.JSON2Response, *primitives.JSONError) {
+	var items []json.RawMessage
+	err := json.Unmarshal(body, &items)
+	if err != nil {
+		return nil, NewParseError()
+	}
+	if len(items) == 0 {
+		return nil, NewInvalidRequestError()
+	}
+	if len(items) > MaxBatchSize {
+		return nil, primitives.NewJSONError(-32600, "Invalid Request", fmt.Sprintf("A batch holds at most %d requests", MaxBatchSize))
+	}
+
+	responses := make([]*primitives.JSON2Response, len(items))
+	notification := make([]bool, len(items))
+	limit := make(chan struct{}, BatchConcurrency)
+	var wg sync.WaitGroup
+	for i, item := range items {
+		j, isNotification, err := parseBatchItem(item)
+		if err != nil {
+			responses[i] = batchError(nil, NewInvalidRequestError())
+			continue
+		}
+		notification[i] = isNotification
+
+		wg.Add(1)
+		limit <- struct{}{}
+		go func(i int, j *primitives.JSON2Request) {
+			defer wg.Done()
+			defer func() { <-limit }()
+			defer func() {
+				if r := recover(); r != nil {
+					responses[i] = batchError(j.ID, NewInternalError())
+				}
+			}()
+
+			resp, jsonError := handler(state, j)
+			if jsonError != nil {
+				resp = batchError(j.ID, jsonError)
+			}
+			responses[i] = resp
+		}(i, j)
+	}
+	wg.Wait()
+
+	answer := make([]*primitives.JSON2Response, 0, len(responses))
+	for i, resp := range responses {
+		if !notification[i] {
+			answer = append(answer, resp)
+		}
+	}
+	return answer, nil
+}
+
+// parseBatchItem decodes one request of a batch, and tells if it is a notification
+func parseBatchItem(item json.RawMessage) (*primitives.JSON2Request, bool, error) {
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(item, &fields)
+	if err != nil {
+		return nil, false, err
+	}
+	j, err := primitives.ParseJSON2Request(string(item))
+	if err != nil {
+		return nil, false, err
+	}
+	_, hasID := fields["id"]
+	return j, !hasID, nil
+}
+
+func batchError(id interface{}, err *primitives.JSONError) *primitives.JSON2Response {
+	resp := primitives.NewJSON2Response()
+	resp.ID = id
+	resp.Error = err
+	return resp
+}
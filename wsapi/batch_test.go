@@ -0,0 +1,77 @@
+package wsapi_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func TestHandleBatchRequest(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
+
+	batch := `[
+		{"jsonrpc": "2.0", "id": 1, "method": "heights"},
+		{"jsonrpc": "2.0", "id": "two", "method": "properties"},
+		{"jsonrpc": "2.0", "method": "heights"},
+		{"jsonrpc": "2.0", "id": 3, "method": "no-such-method"},
+		1
+	]`
+	if !IsBatchRequest([]byte(batch)) || IsBatchRequest([]byte(`{"jsonrpc": "2.0", "id": 1, "method": "heights"}`)) {
+		t.Error("Batches are not told apart from single requests")
+	}
+
+	responses, jErr := HandleBatchRequest(state, []byte(batch), HandleV2Request)
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	// The notification gets no response
+	if len(responses) != 4 {
+		t.Fatalf("Got %d responses, expected 4", len(responses))
+	}
+	if fmt.Sprint(responses[0].ID) != "1" || responses[0].Error != nil || responses[0].Result == nil {
+		t.Errorf("Unexpected response %v", responses[0])
+	}
+	if responses[1].ID != "two" || responses[1].Error != nil || responses[1].Result == nil {
+		t.Errorf("Unexpected response %v", responses[1])
+	}
+	if fmt.Sprint(responses[2].ID) != "3" || responses[2].Error == nil || responses[2].Error.Code != -32601 {
+		t.Errorf("Unexpected response %v", responses[2])
+	}
+	if responses[3].ID != nil || responses[3].Error == nil || responses[3].Error.Code != -32600 {
+		t.Errorf("Unexpected response %v", responses[3])
+	}
+
+	responses, jErr = HandleBatchRequest(state, []byte(`[{"jsonrpc": "2.0", "id": 1, "method": "predictive-fer"}]`), HandleDebugRequest)
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	if len(responses) != 1 || responses[0].Error != nil {
+		t.Errorf("Unexpected debug responses %v", responses)
+	}
+
+	if _, jErr = HandleBatchRequest(state, []byte(`[]`), HandleV2Request); jErr == nil || jErr.Code != -32600 {
+		t.Errorf("Expected an invalid request error for an empty batch, got %v", jErr)
+	}
+	if _, jErr = HandleBatchRequest(state, []byte(`[{"jsonrpc": "2.0"`), HandleV2Request); jErr == nil || jErr.Code != -32700 {
+		t.Errorf("Expected a parse error, got %v", jErr)
+	}
+	items := make([]string, MaxBatchSize+1)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"jsonrpc": "2.0", "id": %d, "method": "heights"}`, i)
+	}
+	if _, jErr = HandleBatchRequest(state, []byte("["+strings.Join(items, ",")+"]"), HandleV2Request); jErr == nil {
+		t.Error("Expected an error for an oversized batch")
+	}
+	responses, jErr = HandleBatchRequest(state, []byte("["+strings.Join(items[:MaxBatchSize], ",")+"]"), HandleV2Request)
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	for i, resp := range responses {
+		if fmt.Sprint(resp.ID) != fmt.Sprint(i) {
+			t.Fatalf("Response %d is for request %v", i, resp.ID)
+		}
+	}
+}
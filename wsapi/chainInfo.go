@@ -0,0 +1,59 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// HandleV2ChainInfo returns the statistics of a chain: when it was created, its first
+// entry, and how many entry blocks, entries and bytes of payload it has.  Entries
+// that are still being synced are counted in the entry count, but not in the payload.
+func HandleV2ChainInfo(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallChainInfo.Observe(float64(time.Since(n).Nanoseconds()))
+
+	req := new(ChainIDRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	chainID, err := primitives.HexToHash(req.ChainID)
+	if err != nil {
+		return nil, NewInvalidHashError()
+	}
+
+	dbase := state.GetDB()
+	if !dbase.IsChainInfoEnabled() {
+		return nil, NewChainInfoDisabledError()
+	}
+	info, err := dbase.FetchChainInfo(chainID)
+	if err != nil {
+		return nil, NewInternalDatabaseError()
+	}
+	head, err := dbase.FetchHeadIndexByChainID(chainID)
+	if err != nil {
+		return nil, NewInternalDatabaseError()
+	}
+	if info == nil || head == nil {
+		return nil, NewMissingChainHeadError()
+	}
+
+	resp := new(ChainInfoResponse)
+	resp.ChainID = chainID.String()
+	resp.ChainHead = head.String()
+	if !info.GetFirstEntryHash().IsZero() {
+		height := int64(info.GetCreationHeight())
+		resp.CreationHeight = &height
+		resp.FirstEntryHash = info.GetFirstEntryHash().String()
+	}
+	resp.EBlockCount = int64(info.GetEBlockCount())
+	resp.EntryCount = int64(info.GetEntryCount())
+	resp.PayloadBytes = int64(info.GetPayloadBytes())
+	return resp, nil
+}
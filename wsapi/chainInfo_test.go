@@ -0,0 +1,49 @@
+package wsapi_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func TestHandleV2ChainInfo(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
+	req := new(ChainIDRequest)
+	req.ChainID = testHelper.GetChainID().String()
+	if _, jErr := HandleV2ChainInfo(state, req); jErr == nil || jErr.Code != -32020 {
+		t.Errorf("Expected the chain statistics disabled error, got %v", jErr)
+	}
+
+	state.GetDB().SetChainInfo(true)
+	if err := state.GetDB().BackfillChainInfo(); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, jErr := HandleV2ChainInfo(state, req)
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	r := resp.(*ChainInfoResponse)
+	if r.CreationHeight == nil || *r.CreationHeight != 0 {
+		t.Errorf("Wrong creation height %v", r.CreationHeight)
+	}
+	if r.FirstEntryHash != testHelper.CreateFirstTestEntry().GetHash().String() {
+		t.Errorf("Wrong first entry %v", r.FirstEntryHash)
+	}
+	if r.EBlockCount != int64(testHelper.BlockCount) || r.EntryCount != int64(testHelper.BlockCount) {
+		t.Errorf("Got %d entry blocks and %d entries, expected %d of each", r.EBlockCount, r.EntryCount, testHelper.BlockCount)
+	}
+	if r.PayloadBytes == 0 || r.ChainHead == "" {
+		t.Errorf("Wrong chain info %v", r)
+	}
+
+	req.ChainID = "0000000000000000000000000000000000000000000000000000000000000123"
+	if _, jErr := HandleV2ChainInfo(state, req); jErr == nil || jErr.Code != -32009 {
+		t.Errorf("Expected a missing chain head error, got %v", jErr)
+	}
+	req.ChainID = "not a chain"
+	if _, jErr := HandleV2ChainInfo(state, req); jErr == nil {
+		t.Error("Expected an error for an invalid chain ID")
+	}
+}
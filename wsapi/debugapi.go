@@ -47,12 +47,26 @@ func HandleDebug(ctx *web.Context) {
 		return
 	}
 
+	if IsBatchRequest(body) {
+		HandleBatch(ctx, state, body, rateLimited(ctx.Request, permitted(ctx.Request, APIDebug, HandleDebugRequest)))
+		return
+	}
+
 	j, err := primitives.ParseJSON2Request(string(body))
 	if err != nil {
 		HandleV2Error(ctx, nil, NewInvalidRequestError())
 		return
 	}
 
+	if jsonError := CheckRateLimit(state, ctx.Request, j.Method); jsonError != nil {
+		HandleV2Error(ctx, j, jsonError)
+		return
+	}
+	if jsonError := CheckPermission(state, ctx.Request, APIDebug, j.Method); jsonError != nil {
+		HandleV2Error(ctx, j, jsonError)
+		return
+	}
+
 	jsonResp, jsonError := HandleDebugRequest(state, j)
 
 	if jsonError != nil {
@@ -104,6 +118,12 @@ func HandleDebugRequest(
 	case "holding-queue":
 		resp, jsonError = HandleHoldingQueue(state, params)
 		break
+	case "holding-list":
+		resp, jsonError = HandleHoldingList(state, params)
+		break
+	case "holding-evict":
+		resp, jsonError = HandleHoldingEvict(state, params)
+		break
 	case "messages":
 		resp, jsonError = HandleMessages(state, params)
 		break
@@ -122,6 +142,12 @@ func HandleDebugRequest(
 	case "reload-configuration":
 		resp, jsonError = HandleReloadConfig(state, params)
 		break
+	case "snapshot":
+		resp, jsonError = HandleSnapshot(state, params)
+		break
+	case "snapshot-status":
+		resp, jsonError = HandleSnapshotStatus(state, params)
+		break
 	default:
 		jsonError = NewMethodNotFoundError()
 		break
@@ -145,10 +171,7 @@ func HandleAuditServers(
 	interface{},
 	*primitives.JSONError,
 ) {
-	type ret struct {
-		AuditServers []interfaces.IServer
-	}
-	r := new(ret)
+	r := new(AuditServersResponse)
 
 	r.AuditServers = state.GetAuditServers(state.GetLeaderHeight())
 	return r, nil
@@ -161,10 +184,7 @@ func HandleAuthorities(
 	interface{},
 	*primitives.JSONError,
 ) {
-	type ret struct {
-		Authorities []interfaces.IAuthority `json: "authorities"`
-	}
-	r := new(ret)
+	r := new(AuthoritiesResponse)
 
 	r.Authorities = state.GetAuthorities()
 	return r, nil
@@ -187,10 +207,7 @@ func HandleCurrentMinute(
 	interface{},
 	*primitives.JSONError,
 ) {
-	type ret struct {
-		Minute int
-	}
-	r := new(ret)
+	r := new(DebugCurrentMinuteResponse)
 
 	r.Minute = state.GetCurrentMinute()
 	return r, nil
@@ -203,10 +220,7 @@ func HandleDelay(
 	interface{},
 	*primitives.JSONError,
 ) {
-	type ret struct {
-		Delay int64
-	}
-	r := new(ret)
+	r := new(DelayResponse)
 
 	r.Delay = state.GetDelay()
 	return r, nil
@@ -219,10 +233,7 @@ func HandleSetDelay(
 	interface{},
 	*primitives.JSONError,
 ) {
-	type ret struct {
-		Delay int64
-	}
-	r := new(ret)
+	r := new(DelayResponse)
 
 	delay := new(SetDelayRequest)
 	err := MapToObject(params, delay)
@@ -243,10 +254,7 @@ func HandleDropRate(
 	interface{},
 	*primitives.JSONError,
 ) {
-	type ret struct {
-		DropRate int
-	}
-	r := new(ret)
+	r := new(DropRateResponse)
 
 	r.DropRate = state.GetDropRate()
 	return r, nil
@@ -259,10 +267,7 @@ func HandleSetDropRate(
 	interface{},
 	*primitives.JSONError,
 ) {
-	type ret struct {
-		DropRate int
-	}
-	r := new(ret)
+	r := new(DropRateResponse)
 
 	droprate := new(SetDropRateRequest)
 	err := MapToObject(params, droprate)
@@ -282,10 +287,7 @@ func HandleFedServers(
 	interface{},
 	*primitives.JSONError,
 ) {
-	type ret struct {
-		FederatedServers []interfaces.IServer
-	}
-	r := new(ret)
+	r := new(FedServersResponse)
 
 	r.FederatedServers = state.GetFedServers(state.GetLeaderHeight())
 	return r, nil
@@ -298,10 +300,7 @@ func HandleHoldingQueue(
 	interface{},
 	*primitives.JSONError,
 ) {
-	type ret struct {
-		Messages []interfaces.IMsg
-	}
-	r := new(ret)
+	r := new(HoldingQueueResponse)
 
 	for _, v := range state.LoadHoldingMap() {
 		r.Messages = append(r.Messages, v)
@@ -309,17 +308,79 @@ func HandleHoldingQueue(
 	return r, nil
 }
 
-func HandleMessages(
+// HandleHoldingList describes the messages in holding, the oldest first, optionally
+// only the ones of a type or held for a reason
+func HandleHoldingList(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	req := new(HoldingListRequest)
+	if params != nil {
+		err := MapToObject(params, req)
+		if err != nil {
+			return nil, NewInvalidParamsError()
+		}
+	}
+
+	r := new(HoldingListResponse)
+	r.Messages = []interfaces.HeldMessage{}
+	r.ByType = map[string]int{}
+	r.ByReason = map[string]int{}
+	for _, m := range state.LoadHoldingList() {
+		if req.Type != "" && !strings.EqualFold(req.Type, m.Type) {
+			continue
+		}
+		if req.Reason != "" && req.Reason != m.Reason {
+			continue
+		}
+		r.Messages = append(r.Messages, m)
+		r.ByType[m.Type]++
+		r.ByReason[m.Reason]++
+	}
+	return r, nil
+}
+
+// HandleHoldingEvict removes a message from holding, and puts it back in the input
+// queue of the node if requeue is set
+func HandleHoldingEvict(
 	state interfaces.IState,
 	params interface{},
 ) (
 	interface{},
 	*primitives.JSONError,
 ) {
-	type ret struct {
-		Messages []json.RawMessage
+	req := new(HoldingEvictRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	hash, err := primitives.HexToHash(req.Hash)
+	if err != nil {
+		return nil, NewInvalidHashError()
 	}
-	r := new(ret)
+
+	err = state.EvictHeldMessage(hash, req.Requeue)
+	if err != nil {
+		return nil, NewCustomInvalidParamsError(err.Error())
+	}
+
+	r := new(HoldingEvictResponse)
+	r.Hash = hash.String()
+	r.Requeued = req.Requeue
+	return r, nil
+}
+
+func HandleMessages(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	r := new(MessagesResponse)
 	for _, v := range state.GetJournalMessages() {
 		r.Messages = append(r.Messages, v)
 	}
@@ -333,12 +394,7 @@ func HandleNetworkInfo(
 	interface{},
 	*primitives.JSONError,
 ) {
-	type ret struct {
-		NetworkNumber int
-		NetworkName   string
-		NetworkID     uint32
-	}
-	r := new(ret)
+	r := new(NetworkInfoResponse)
 	r.NetworkNumber = state.GetNetworkNumber()
 	r.NetworkName = state.GetNetworkName()
 	r.NetworkID = state.GetNetworkID()
@@ -352,10 +408,7 @@ func HandleSummary(
 	interface{},
 	*primitives.JSONError,
 ) {
-	type ret struct {
-		Summary string
-	}
-	r := new(ret)
+	r := new(SummaryResponse)
 	r.Summary = state.ShortString()
 
 	return r, nil
@@ -368,14 +421,13 @@ func HandlePredictiveFER(
 	interface{},
 	*primitives.JSONError,
 ) {
-	type ret struct {
-		PredictiveFER uint64
-	}
-	r := new(ret)
+	r := new(PredictiveFERResponse)
 	r.PredictiveFER = state.GetPredictiveFER()
 	return r, nil
 }
 
+// HandleProcessList describes the VMs of the process list of a height, by default the
+// one the node is building
 func HandleProcessList(
 	state interfaces.IState,
 	params interface{},
@@ -383,12 +435,23 @@ func HandleProcessList(
 	interface{},
 	*primitives.JSONError,
 ) {
-	type ret struct {
-		ProcessList string
+	req := new(ProcessListRequest)
+	if params != nil {
+		err := MapToObject(params, req)
+		if err != nil {
+			return nil, NewInvalidParamsError()
+		}
 	}
-	r := new(ret)
-	r.ProcessList = state.GetLeaderPL().String()
-	return r, nil
+
+	dbheight := state.GetLeaderHeight()
+	if req.DBHeight != nil {
+		dbheight = *req.DBHeight
+	}
+	status := state.GetProcessListStatus(dbheight)
+	if status == nil {
+		return nil, NewCustomInvalidParamsError(fmt.Sprintf("No process list at height %d", dbheight))
+	}
+	return status, nil
 }
 
 func HandleReloadConfig(
@@ -400,10 +463,48 @@ func HandleReloadConfig(
 ) {
 	// LoacConfig with "" strings should load the default location
 	state.LoadConfig(state.GetConfigPath(), state.GetNetworkName())
+	SetRateLimits(state)
+	if err := ReloadAPIUsers(state); err != nil {
+		return nil, NewCustomInternalError(err.Error())
+	}
 
 	return state.GetCfg(), nil
 }
 
+// HandleSnapshot asks for a snapshot of the database at the next block boundary.  The
+// snapshot is written in the background, snapshot-status tells when it is done.
+func HandleSnapshot(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	req := new(SnapshotRequest)
+	if params != nil {
+		err := MapToObject(params, req)
+		if err != nil {
+			return nil, NewInvalidParamsError()
+		}
+	}
+
+	_, err := state.RequestSnapshot(req.File)
+	if err != nil {
+		return nil, NewSnapshotError(err.Error())
+	}
+	return state.GetSnapshotStatus(), nil
+}
+
+func HandleSnapshotStatus(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	return state.GetSnapshotStatus(), nil
+}
+
 type SetDelayRequest struct {
 	Delay int64 `json:"delay"`
 }
@@ -411,3 +512,78 @@ type SetDelayRequest struct {
 type SetDropRateRequest struct {
 	DropRate int `json:"droprate"`
 }
+
+type SnapshotRequest struct {
+	File string `json:"file"` // a name in the SnapshotPath directory, optional
+}
+
+type HoldingListRequest struct {
+	Type   string `json:"type"`   // message type, as in the list, optional
+	Reason string `json:"reason"` // optional
+}
+
+type HoldingEvictRequest struct {
+	Hash    string `json:"hash"`
+	Requeue bool   `json:"requeue"` // put the message back in the input queue instead of dropping it
+}
+
+type ProcessListRequest struct {
+	DBHeight *uint32 `json:"dbheight"` // the height the node is building by default
+}
+
+type AuditServersResponse struct {
+	AuditServers []interfaces.IServer
+}
+
+type AuthoritiesResponse struct {
+	Authorities []interfaces.IAuthority `json: "authorities"`
+}
+
+type DebugCurrentMinuteResponse struct {
+	Minute int
+}
+
+type DelayResponse struct {
+	Delay int64
+}
+
+type DropRateResponse struct {
+	DropRate int
+}
+
+type FedServersResponse struct {
+	FederatedServers []interfaces.IServer
+}
+
+type HoldingQueueResponse struct {
+	Messages []interfaces.IMsg
+}
+
+type HoldingListResponse struct {
+	Messages []interfaces.HeldMessage `json:"messages"`
+	ByType   map[string]int           `json:"bytype"`
+	ByReason map[string]int           `json:"byreason"`
+}
+
+type HoldingEvictResponse struct {
+	Hash     string `json:"hash"`
+	Requeued bool   `json:"requeued"`
+}
+
+type MessagesResponse struct {
+	Messages []json.RawMessage
+}
+
+type NetworkInfoResponse struct {
+	NetworkNumber int
+	NetworkName   string
+	NetworkID     uint32
+}
+
+type SummaryResponse struct {
+	Summary string
+}
+
+type PredictiveFERResponse struct {
+	PredictiveFER uint64
+}
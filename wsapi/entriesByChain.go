@@ -0,0 +1,226 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+const (
+	// EntriesByChainDefaultLimit is used when the request does not set a limit
+	EntriesByChainDefaultLimit = 100
+	// EntriesByChainMaxLimit caps the number of entries returned by one call
+	EntriesByChainMaxLimit = 1000
+)
+
+// entryCursor is the position of an entry in a chain: the sequence number of its
+// entry block and its index among the entries of that block (minute markers excluded).
+// It is handed to clients as 16 hex characters.
+type entryCursor struct {
+	EBSequence uint32
+	Index      uint32
+}
+
+func (c entryCursor) String() string {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint32(b[:4], c.EBSequence)
+	binary.BigEndian.PutUint32(b[4:], c.Index)
+	return hex.EncodeToString(b)
+}
+
+func parseEntryCursor(s string) (entryCursor, bool) {
+	c := entryCursor{}
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 8 {
+		return c, false
+	}
+	c.EBSequence = binary.BigEndian.Uint32(b[:4])
+	c.Index = binary.BigEndian.Uint32(b[4:])
+	return c, true
+}
+
+// HandleV2EntriesByChain returns a page of entries of a chain, in chain order, along
+// with the cursor to use to fetch the next page.
+func HandleV2EntriesByChain(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallEntriesByChain.Observe(float64(time.Since(n).Nanoseconds()))
+
+	req := new(EntriesByChainRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	chainID, err := primitives.HexToHash(req.ChainID)
+	if err != nil {
+		return nil, NewInvalidHashError()
+	}
+
+	backward := false
+	switch req.Direction {
+	case "", "forward":
+	case "backward":
+		backward = true
+	default:
+		return nil, NewCustomInvalidParamsError("Invalid direction, expected 'forward' or 'backward'")
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = EntriesByChainDefaultLimit
+	}
+	if limit > EntriesByChainMaxLimit {
+		limit = EntriesByChainMaxLimit
+	}
+
+	dbase := state.GetDB()
+
+	head, err := dbase.FetchEBlockHead(chainID)
+	if err != nil {
+		return nil, NewInternalDatabaseError()
+	}
+	if head == nil {
+		return nil, NewMissingChainHeadError()
+	}
+	last := int(head.GetHeader().GetEBSequence())
+
+	// Find the sequence number of the entry block, and the entry inside of it, we start
+	// from.  An index of -1 means "the last entry of the block".
+	start, index := 0, 0
+	if backward {
+		start, index = last, -1
+	}
+	if req.Cursor != "" {
+		c, ok := parseEntryCursor(req.Cursor)
+		if !ok || int64(c.EBSequence) > int64(last) {
+			return nil, NewCustomInvalidParamsError("Invalid cursor")
+		}
+		start, index = int(c.EBSequence), int(c.Index)
+	} else if req.Height != nil {
+		height := uint32(*req.Height)
+		start, err = searchEBlocks(dbase, chainID, last, func(eblock interfaces.IEntryBlock) bool {
+			return eblock.GetHeader().GetDBHeight() >= height
+		})
+		if err != nil {
+			return nil, NewInternalDatabaseError()
+		}
+		if backward {
+			start, err = searchEBlocks(dbase, chainID, last, func(eblock interfaces.IEntryBlock) bool {
+				return eblock.GetHeader().GetDBHeight() > height
+			})
+			if err != nil {
+				return nil, NewInternalDatabaseError()
+			}
+			start--
+		}
+	}
+
+	resp := new(EntriesByChainResponse)
+	resp.ChainID = chainID.String()
+	resp.Entries = make([]EntryWithBlockInfo, 0)
+	// The entries of a chain are only counted by the chain info
+	if dbase.IsChainInfoEnabled() {
+		info, err := dbase.FetchChainInfo(chainID)
+		if err != nil {
+			return nil, NewInternalDatabaseError()
+		}
+		if info != nil {
+			resp.TotalEntries = int64(info.GetEntryCount())
+		}
+	}
+
+	step := 1
+	if backward {
+		step = -1
+	}
+	for i := start; i >= 0 && i <= last; i += step {
+		eblock, err := dbase.FetchEBlockBySequence(chainID, uint32(i))
+		if err != nil || eblock == nil {
+			return nil, NewInternalDatabaseError()
+		}
+		dbheight := eblock.GetHeader().GetDBHeight()
+
+		var timestamp int64
+		dblock, err := dbase.FetchDBlockByHeight(dbheight)
+		if err != nil {
+			return nil, NewInternalDatabaseError()
+		}
+		if dblock != nil {
+			timestamp = dblock.GetHeader().GetTimestamp().GetTimeSeconds()
+		}
+		addrs := EBlockEntryAddrs(eblock, timestamp)
+
+		j := 0
+		if i == start {
+			j = index
+		}
+		if backward && (i != start || j < 0 || j >= len(addrs)) {
+			j = len(addrs) - 1
+		}
+
+		for ; j >= 0 && j < len(addrs); j += step {
+			if len(resp.Entries) == limit {
+				c := entryCursor{EBSequence: eblock.GetHeader().GetEBSequence(), Index: uint32(j)}
+				resp.NextCursor = c.String()
+				return resp, nil
+			}
+
+			h, err := primitives.HexToHash(addrs[j].EntryHash)
+			if err != nil {
+				return nil, NewInternalError()
+			}
+			entry, err := dbase.FetchEntry(h)
+			if err != nil {
+				return nil, NewInternalDatabaseError()
+			}
+
+			e := new(EntryWithBlockInfo)
+			e.EntryHash = addrs[j].EntryHash
+			e.ChainID = resp.ChainID
+			e.DBHeight = int64(dbheight)
+			e.Timestamp = addrs[j].Timestamp
+			if entry != nil {
+				e.Content = hex.EncodeToString(entry.GetContent())
+				for _, v := range entry.ExternalIDs() {
+					e.ExtIDs = append(e.ExtIDs, hex.EncodeToString(v))
+				}
+			} else {
+				e.Pruned, _ = dbase.IsEntryPruned(h)
+			}
+			resp.Entries = append(resp.Entries, *e)
+		}
+	}
+
+	return resp, nil
+}
+
+// searchEBlocks returns the sequence number of the first entry block of the chain for
+// which f is true, or last+1 if there is none.  f must be false for the blocks before
+// that one, and true after.
+func searchEBlocks(dbase interfaces.DBOverlaySimple, chainID interfaces.IHash, last int, f func(interfaces.IEntryBlock) bool) (int, error) {
+	var err error
+	i := sort.Search(last+1, func(i int) bool {
+		if err != nil {
+			return true
+		}
+		eblock, e := dbase.FetchEBlockBySequence(chainID, uint32(i))
+		if e == nil && eblock == nil {
+			e = fmt.Errorf("Missing entry block %d of chain %x", i, chainID.Bytes()[:3])
+		}
+		if e != nil {
+			err = e
+			return true
+		}
+		return f(eblock)
+	})
+	return i, err
+}
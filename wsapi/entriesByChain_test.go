@@ -0,0 +1,148 @@
+package wsapi_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func TestHandleV2EntriesByChain(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
+	chainID := testHelper.GetChainID()
+
+	// The total number of entries comes from the chain info
+	state.GetDB().SetChainInfo(true)
+	if err := state.GetDB().BackfillChainInfo(); err != nil {
+		t.Fatal(err)
+	}
+
+	eblocks, err := state.GetDB().FetchAllEBlocksByChain(chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{}
+	for _, eb := range eblocks {
+		for _, h := range eb.GetEntryHashes() {
+			if !h.IsMinuteMarker() {
+				expected = append(expected, h.String())
+			}
+		}
+	}
+	if len(expected) < 3 {
+		t.Fatalf("Test chain only has %d entries", len(expected))
+	}
+
+	// Walk the chain forward, two entries at a time
+	forward := []string{}
+	req := new(EntriesByChainRequest)
+	req.ChainID = chainID.String()
+	req.Limit = 2
+	for i := 0; ; i++ {
+		if i > len(expected) {
+			t.Fatal("Cursor never ran out")
+		}
+		resp, jErr := HandleV2EntriesByChain(state, req)
+		if jErr != nil {
+			t.Fatalf("%v", jErr)
+		}
+		r := resp.(*EntriesByChainResponse)
+		if r.TotalEntries != int64(len(expected)) {
+			t.Errorf("TotalEntries is %d, expected %d", r.TotalEntries, len(expected))
+		}
+		for _, e := range r.Entries {
+			if e.ChainID != chainID.String() || e.Content == "" {
+				t.Errorf("Bad entry %v", e)
+			}
+			forward = append(forward, e.EntryHash)
+		}
+		if r.NextCursor == "" {
+			break
+		}
+		req.Cursor = r.NextCursor
+	}
+	if len(forward) != len(expected) {
+		t.Fatalf("Got %d entries walking forward, expected %d", len(forward), len(expected))
+	}
+	for i := range expected {
+		if forward[i] != expected[i] {
+			t.Errorf("Entry %d is %v, expected %v", i, forward[i], expected[i])
+		}
+	}
+
+	// And backward, from the head
+	backward := []string{}
+	req = new(EntriesByChainRequest)
+	req.ChainID = chainID.String()
+	req.Direction = "backward"
+	req.Limit = 3
+	for i := 0; ; i++ {
+		if i > len(expected) {
+			t.Fatal("Cursor never ran out")
+		}
+		resp, jErr := HandleV2EntriesByChain(state, req)
+		if jErr != nil {
+			t.Fatalf("%v", jErr)
+		}
+		r := resp.(*EntriesByChainResponse)
+		for _, e := range r.Entries {
+			backward = append(backward, e.EntryHash)
+		}
+		if r.NextCursor == "" {
+			break
+		}
+		req.Cursor = r.NextCursor
+	}
+	if len(backward) != len(expected) {
+		t.Fatalf("Got %d entries walking backward, expected %d", len(backward), len(expected))
+	}
+	for i := range expected {
+		if backward[len(backward)-1-i] != expected[i] {
+			t.Errorf("Entry %d is %v, expected %v", i, backward[len(backward)-1-i], expected[i])
+		}
+	}
+
+	// Starting at a height skips the entries below it
+	height := int64(eblocks[len(eblocks)-1].GetHeader().GetDBHeight())
+	req = new(EntriesByChainRequest)
+	req.ChainID = chainID.String()
+	req.Height = &height
+	resp, jErr := HandleV2EntriesByChain(state, req)
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	for _, e := range resp.(*EntriesByChainResponse).Entries {
+		if e.DBHeight < height {
+			t.Errorf("Got an entry at height %d, below %d", e.DBHeight, height)
+		}
+	}
+
+	// And backward from a height, the entries at or below it
+	height = int64(eblocks[1].GetHeader().GetDBHeight())
+	req.Direction = "backward"
+	resp, jErr = HandleV2EntriesByChain(state, req)
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	entries := resp.(*EntriesByChainResponse).Entries
+	if len(entries) == 0 || entries[0].DBHeight != height {
+		t.Errorf("Got %d entries, expected the last one at height %d first", len(entries), height)
+	}
+	for _, e := range entries {
+		if e.DBHeight > height {
+			t.Errorf("Got an entry at height %d, above %d", e.DBHeight, height)
+		}
+	}
+	req.Direction = ""
+
+	req.Height = nil
+	req.Cursor = "zz"
+	if _, jErr := HandleV2EntriesByChain(state, req); jErr == nil {
+		t.Error("Expected an error for an invalid cursor")
+	}
+	req.Cursor = ""
+	req.Direction = "sideways"
+	if _, jErr := HandleV2EntriesByChain(state, req); jErr == nil {
+		t.Error("Expected an error for an invalid direction")
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+const (
+	// EntriesByExtIDDefaultLimit is used when the request does not set a limit
+	EntriesByExtIDDefaultLimit = 100
+	// EntriesByExtIDMaxLimit caps the number of entries returned by one call
+	EntriesByExtIDMaxLimit = 1000
+)
+
+// extIDCursor is the position of an entry in the external ID index: height and entry
+// hash.  It is handed to clients as 72 hex characters.
+func extIDCursor(e interfaces.IExtIDIndexEntry) []byte {
+	return databaseOverlay.ExtIDIndexKey(e.GetDBHeight(), e.GetEntryHash())
+}
+
+func parseExtIDCursor(s string) ([]byte, bool) {
+	c, err := hex.DecodeString(s)
+	if err != nil || len(c) != 4+constants.HASH_LENGTH {
+		return nil, false
+	}
+	return c, true
+}
+
+// HandleV2EntriesByExtID returns a page of the entries of a chain that have the given
+// external ID, in block order, along with the cursor to use to fetch the next page.
+// The node must run with the external ID index enabled.
+func HandleV2EntriesByExtID(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallEntriesByExtID.Observe(float64(time.Since(n).Nanoseconds()))
+
+	req := new(EntriesByExtIDRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	chainID, err := primitives.HexToHash(req.ChainID)
+	if err != nil {
+		return nil, NewInvalidHashError()
+	}
+	extID, err := hex.DecodeString(req.ExtID)
+	if err != nil {
+		return nil, NewCustomInvalidParamsError("Invalid extid, expected hex")
+	}
+
+	backward := false
+	switch req.Direction {
+	case "", "forward":
+	case "backward":
+		backward = true
+	default:
+		return nil, NewCustomInvalidParamsError("Invalid direction, expected 'forward' or 'backward'")
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = EntriesByExtIDDefaultLimit
+	}
+	if limit > EntriesByExtIDMaxLimit {
+		limit = EntriesByExtIDMaxLimit
+	}
+
+	dbase := state.GetDB()
+	if !dbase.IsExtIDIndexEnabled() {
+		return nil, NewExtIDIndexDisabledError()
+	}
+
+	var start []byte
+	if req.Cursor != "" {
+		c, ok := parseExtIDCursor(req.Cursor)
+		if !ok {
+			return nil, NewCustomInvalidParamsError("Invalid cursor")
+		}
+		start = c
+	} else if req.Height != nil {
+		start = databaseOverlay.ExtIDIndexKey(uint32(*req.Height), nil)
+		if backward {
+			// Start from the last entry at or below the height
+			start = append(start, bytes.Repeat([]byte{0xff}, constants.HASH_LENGTH)...)
+		}
+	}
+
+	// One entry past the page tells where the next page starts
+	list, err := dbase.FetchEntriesByExtIDRange(chainID, extID, start, backward, limit+1)
+	if err != nil {
+		return nil, NewInternalDatabaseError()
+	}
+	if req.Cursor != "" && (len(list) == 0 || !bytes.Equal(extIDCursor(list[0]), start)) {
+		return nil, NewCustomInvalidParamsError("Invalid cursor")
+	}
+
+	resp := new(EntriesByExtIDResponse)
+	resp.ChainID = chainID.String()
+	resp.ExtID = hex.EncodeToString(extID)
+	resp.Entries = make([]EntryWithBlockInfo, 0)
+
+	timestamps := map[uint32]int64{}
+	for i := range list {
+		if len(resp.Entries) == limit {
+			resp.NextCursor = hex.EncodeToString(extIDCursor(list[i]))
+			break
+		}
+		h := list[i].GetEntryHash()
+		dbheight := list[i].GetDBHeight()
+
+		timestamp, ok := timestamps[dbheight]
+		if !ok {
+			dblock, err := dbase.FetchDBlockByHeight(dbheight)
+			if err != nil {
+				return nil, NewInternalDatabaseError()
+			}
+			if dblock != nil {
+				timestamp = dblock.GetHeader().GetTimestamp().GetTimeSeconds()
+			}
+			timestamps[dbheight] = timestamp
+		}
+
+		entry, err := dbase.FetchEntry(h)
+		if err != nil {
+			return nil, NewInternalDatabaseError()
+		}
+
+		e := new(EntryWithBlockInfo)
+		e.EntryHash = h.String()
+		e.ChainID = resp.ChainID
+		e.DBHeight = int64(dbheight)
+		e.Timestamp = timestamp
+		if entry != nil {
+			e.Content = hex.EncodeToString(entry.GetContent())
+			for _, v := range entry.ExternalIDs() {
+				e.ExtIDs = append(e.ExtIDs, hex.EncodeToString(v))
+			}
+		} else {
+			e.Pruned, _ = dbase.IsEntryPruned(h)
+		}
+		resp.Entries = append(resp.Entries, *e)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,67 @@
+package wsapi_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func TestHandleV2EntriesByExtID(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
+	first := testHelper.CreateFirstTestEntry()
+
+	req := new(EntriesByExtIDRequest)
+	req.ChainID = first.GetChainID().String()
+	req.ExtID = hex.EncodeToString([]byte("Test2"))
+	if _, jErr := HandleV2EntriesByExtID(state, req); jErr == nil || jErr.Code != -32018 {
+		t.Errorf("Expected the external ID index disabled error, got %v", jErr)
+	}
+
+	state.GetDB().SetExtIDIndex(true)
+	if err := state.GetDB().BackfillExtIDIndex(); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, jErr := HandleV2EntriesByExtID(state, req)
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	r := resp.(*EntriesByExtIDResponse)
+	if len(r.Entries) != 1 || r.Entries[0].EntryHash != first.GetHash().String() {
+		t.Fatalf("Got %v, expected entry %v", r.Entries, first.GetHash())
+	}
+	if r.Entries[0].DBHeight != 0 || len(r.Entries[0].ExtIDs) != 2 || r.NextCursor != "" {
+		t.Errorf("Wrong entry %v", r.Entries[0])
+	}
+
+	req.ExtID = hex.EncodeToString([]byte("ExtID 3"))
+	resp, jErr = HandleV2EntriesByExtID(state, req)
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	r = resp.(*EntriesByExtIDResponse)
+	if len(r.Entries) != 1 || r.Entries[0].DBHeight != 3 {
+		t.Errorf("Got %v, expected the entry of height 3", r.Entries)
+	}
+
+	req.ExtID = hex.EncodeToString([]byte("Not an external ID"))
+	resp, jErr = HandleV2EntriesByExtID(state, req)
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	if len(resp.(*EntriesByExtIDResponse).Entries) != 0 {
+		t.Error("Found entries for an unknown external ID")
+	}
+
+	req.ExtID = "not hex"
+	if _, jErr := HandleV2EntriesByExtID(state, req); jErr == nil {
+		t.Error("Expected an error for an external ID that is not hex")
+	}
+	req.ExtID = hex.EncodeToString([]byte("Test1"))
+	req.Cursor = "00"
+	if _, jErr := HandleV2EntriesByExtID(state, req); jErr == nil {
+		t.Error("Expected an error for an invalid cursor")
+	}
+}
@@ -74,6 +74,9 @@ func NewInvalidCommitEntryError() *primitives.JSONError {
 func NewInvalidDataPassedError() *primitives.JSONError {
 	return primitives.NewJSONError(-32602, "Invalid params", "Invalid data passed")
 }
+func NewInvalidTopicError() *primitives.JSONError {
+	return primitives.NewJSONError(-32602, "Invalid params", "Invalid Topic")
+}
 func NewInternalDatabaseError() *primitives.JSONError {
 	return primitives.NewJSONError(-32603, "Internal error", "database error")
 }
@@ -97,3 +100,33 @@ func NewReceiptError() *primitives.JSONError {
 func NewRepeatCommitError(data interface{}) *primitives.JSONError {
 	return primitives.NewJSONError(-32011, "Repeated Commit", data)
 }
+func NewSubscriptionNotFoundError() *primitives.JSONError {
+	return primitives.NewJSONError(-32012, "Subscription not found", nil)
+}
+func NewAddressIndexDisabledError() *primitives.JSONError {
+	return primitives.NewJSONError(-32013, "Address index disabled", nil)
+}
+func NewEntryPrunedError() *primitives.JSONError {
+	return primitives.NewJSONError(-32014, "Entry pruned", nil)
+}
+func NewSnapshotError(data interface{}) *primitives.JSONError {
+	return primitives.NewJSONError(-32015, "Snapshot unavailable", data)
+}
+func NewRateLimitedError() *primitives.JSONError {
+	return primitives.NewJSONError(-32016, "Rate limit exceeded", nil)
+}
+func NewPermissionDeniedError(data interface{}) *primitives.JSONError {
+	return primitives.NewJSONError(-32017, "Permission denied", data)
+}
+func NewExtIDIndexDisabledError() *primitives.JSONError {
+	return primitives.NewJSONError(-32018, "External ID index disabled", nil)
+}
+func NewBalanceHistoryDisabledError() *primitives.JSONError {
+	return primitives.NewJSONError(-32019, "Balance history disabled", nil)
+}
+func NewChainInfoDisabledError() *primitives.JSONError {
+	return primitives.NewJSONError(-32020, "Chain statistics disabled", nil)
+}
+func NewUnauthorizedError() *primitives.JSONError {
+	return primitives.NewJSONError(-32600, "Invalid Request", "Unauthorized")
+}
@@ -0,0 +1,114 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"encoding/hex"
+	"math"
+	"time"
+
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// HandleV2FeeEstimate returns the minimum fee of a factoid transaction, computed the
+// way FBlock.ValidateTransaction computes it.  The transaction is either a draft, or
+// the number of inputs, outputs and entry credit outputs.
+//
+// Inputs of a draft without an RCD are counted as single signature inputs.  The fee
+// of a shape assumes single signature inputs and the largest amounts, so it is the
+// fee of the largest transaction of that shape; a transaction of that shape never
+// needs more.
+//
+// The fee is at the rate of the current block, or at the rate a FER entry set, if
+// the new rate activates by the next block, as the transaction may land in either.
+func HandleV2FeeEstimate(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallFeeEstimate.Observe(float64(time.Since(n).Nanoseconds()))
+
+	req := new(FeeEstimateRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	var tx *factoid.Transaction
+	if req.Transaction != "" {
+		if req.Inputs != 0 || req.Outputs != 0 || req.ECOutputs != 0 {
+			return nil, NewCustomInvalidParamsError("Expected either a transaction or the number of inputs and outputs, not both")
+		}
+		tx, err = draftTransaction(req.Transaction)
+		if err != nil {
+			return nil, NewCustomInvalidParamsError("Unable to decode the transaction")
+		}
+	} else {
+		if req.Inputs < 1 || req.Inputs > 255 || req.Outputs < 0 || req.Outputs > 255 || req.ECOutputs < 0 || req.ECOutputs > 255 {
+			return nil, NewCustomInvalidParamsError("Expected 1 to 255 inputs, and up to 255 outputs and entry credit outputs")
+		}
+		tx = shapeTransaction(req.Inputs, req.Outputs, req.ECOutputs)
+	}
+
+	resp := new(FeeEstimateResponse)
+	resp.Rate = state.GetFactoshisPerEC()
+	if fBlock := state.GetFactoidState().GetCurrentBlock(); fBlock != nil {
+		resp.Rate = fBlock.GetExchRate()
+	}
+	price, height := state.GetFERChange()
+	if height != 0 && height <= state.GetPublishedLeaderHeight()+1 {
+		resp.Rate = price
+		resp.RateChangeHeight = height
+	}
+
+	resp.Fee, err = tx.CalculateFee(resp.Rate)
+	if err != nil {
+		return nil, NewCustomInvalidParamsError(err.Error())
+	}
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, NewInternalError()
+	}
+	resp.Size = len(data)
+	for _, rcd := range tx.GetRCDs() {
+		resp.Signatures += rcd.NumberOfSignatures()
+	}
+	if resp.Rate > 0 {
+		resp.FeeEC = resp.Fee / resp.Rate
+	}
+	return resp, nil
+}
+
+func draftTransaction(hexTx string) (*factoid.Transaction, error) {
+	data, err := hex.DecodeString(hexTx)
+	if err != nil {
+		return nil, err
+	}
+	tx := new(factoid.Transaction)
+	_, err = tx.UnmarshalBinaryData(data)
+	if err != nil {
+		return nil, err
+	}
+	// The signatures are paid for before they are made
+	for len(tx.GetRCDs()) < len(tx.GetInputs()) {
+		tx.AddAuthorization(factoid.NewRCD_1(make([]byte, 32)))
+	}
+	return tx, nil
+}
+
+func shapeTransaction(inputs, outputs, ecOutputs int) *factoid.Transaction {
+	tx := new(factoid.Transaction)
+	address := factoid.NewAddress(make([]byte, 32))
+	for i := 0; i < inputs; i++ {
+		tx.AddInput(address, math.MaxInt64)
+		tx.AddAuthorization(factoid.NewRCD_1(make([]byte, 32)))
+	}
+	for i := 0; i < outputs; i++ {
+		tx.AddOutput(address, math.MaxInt64)
+	}
+	for i := 0; i < ecOutputs; i++ {
+		tx.AddECOutput(address, math.MaxInt64)
+	}
+	return tx
+}
@@ -0,0 +1,88 @@
+package wsapi_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func TestHandleV2FeeEstimate(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
+	rate := state.GetFactoidState().GetCurrentBlock().GetExchRate()
+
+	// One signature, one output, and less than a KiB
+	resp, jErr := HandleV2FeeEstimate(state, FeeEstimateRequest{Inputs: 1, Outputs: 1})
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	estimate := resp.(*FeeEstimateResponse)
+	if estimate.Rate != rate || estimate.FeeEC != 12 || estimate.Fee != 12*rate || estimate.Signatures != 1 {
+		t.Errorf("Unexpected estimate %+v", estimate)
+	}
+
+	// An unsigned draft pays for the signature it will have
+	tx := new(factoid.Transaction)
+	tx.AddInput(testHelper.NewFactoidAddress(1), 1000)
+	tx.AddOutput(testHelper.NewFactoidAddress(2), 500)
+	tx.AddECOutput(testHelper.NewECAddress(1), 500)
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, jErr = HandleV2FeeEstimate(state, FeeEstimateRequest{Transaction: hex.EncodeToString(data)})
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	estimate = resp.(*FeeEstimateResponse)
+	testHelper.SignFactoidTransaction(1, tx)
+	fee, err := tx.CalculateFee(rate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimate.Fee != fee || estimate.FeeEC != 22 {
+		t.Errorf("Estimated %d, the signed transaction needs %d", estimate.Fee, fee)
+	}
+
+	if _, jErr = HandleV2FeeEstimate(state, FeeEstimateRequest{Transaction: hex.EncodeToString(data), Inputs: 1}); jErr == nil {
+		t.Error("Expected an error for both a transaction and a shape")
+	}
+	if _, jErr = HandleV2FeeEstimate(state, FeeEstimateRequest{Outputs: 1}); jErr == nil {
+		t.Error("Expected an error for a transaction without inputs")
+	}
+
+	estimateAt := func(price uint64, height uint32) *FeeEstimateResponse {
+		state.FERChangePrice = price
+		state.FERChangeHeight = height
+		state.PublishFERChange()
+		defer func() {
+			state.FERChangePrice = 0
+			state.FERChangeHeight = 0
+			state.PublishFERChange()
+		}()
+		resp, jErr := HandleV2FeeEstimate(state, FeeEstimateRequest{Inputs: 1, Outputs: 1})
+		if jErr != nil {
+			t.Fatalf("%v", jErr)
+		}
+		return resp.(*FeeEstimateResponse)
+	}
+
+	// A rate activating with the next block applies, whether higher or lower
+	next := state.GetPublishedLeaderHeight() + 1
+	estimate = estimateAt(2*rate, next)
+	if estimate.Rate != 2*rate || estimate.Fee != 24*rate || estimate.RateChangeHeight != next {
+		t.Errorf("The higher scheduled rate was not applied %+v", estimate)
+	}
+	estimate = estimateAt(rate/2, next)
+	if estimate.Rate != rate/2 || estimate.Fee != 12*(rate/2) || estimate.RateChangeHeight != next {
+		t.Errorf("The lower scheduled rate was not applied %+v", estimate)
+	}
+
+	// A rate activating later does not
+	estimate = estimateAt(2*rate, next+1)
+	if estimate.Rate != rate || estimate.RateChangeHeight != 0 {
+		t.Errorf("A later rate was applied %+v", estimate)
+	}
+}
@@ -0,0 +1,70 @@
+package wsapi_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func TestHandleHoldingListAndEvict(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
+
+	// A reveal without its commit stays in holding
+	entry := testHelper.CreateFirstTestEntry()
+	entry.Content = primitives.ByteSlice{Bytes: []byte("Held until its commit shows up")}
+	msg := new(messages.RevealEntryMsg)
+	msg.Entry = entry
+	msg.Timestamp = primitives.NewTimestampNow()
+	hash := msg.GetMsgHash().String()
+	state.InMsgQueue().Enqueue(msg)
+
+	req := new(HoldingListRequest)
+	req.Reason = interfaces.HoldWaitingCommit
+	var held *interfaces.HeldMessage
+	for i := 0; i < 50 && held == nil; i++ {
+		time.Sleep(100 * time.Millisecond)
+		resp, jErr := HandleHoldingList(state, req)
+		if jErr != nil {
+			t.Fatalf("%v", jErr)
+		}
+		for _, m := range resp.(*HoldingListResponse).Messages {
+			if m.Hash == hash {
+				held = &m
+				break
+			}
+		}
+	}
+	if held == nil {
+		t.Fatal("The reveal is not in the holding list")
+	}
+	if held.Type != constants.MessageName(constants.REVEAL_ENTRY_MSG) || held.EntryHash != entry.GetHash().String() {
+		t.Errorf("Wrong held message %v", held)
+	}
+
+	req.Reason = interfaces.HoldFutureHeight
+	resp, _ := HandleHoldingList(state, req)
+	for _, m := range resp.(*HoldingListResponse).Messages {
+		if m.Hash == hash {
+			t.Error("The reason filter does not filter")
+		}
+	}
+
+	evict := new(HoldingEvictRequest)
+	evict.Hash = hash
+	if _, jErr := HandleHoldingEvict(state, evict); jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	if _, jErr := HandleHoldingEvict(state, evict); jErr == nil {
+		t.Error("Evicted a message that is not in holding")
+	}
+	evict.Hash = "not a hash"
+	if _, jErr := HandleHoldingEvict(state, evict); jErr == nil {
+		t.Error("Expected an error for an invalid hash")
+	}
+}
@@ -15,11 +15,21 @@ var (
 		Help: "Time it takes to compelete a call",
 	})
 
+	HandleV2APICallBalanceAtHeight = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_balanceatheight_ns",
+		Help: "Time it takes to compelete a balanceatheight",
+	})
+
 	HandleV2APICallChainHead = prometheus.NewSummary(prometheus.SummaryOpts{
 		Name: "factomd_wsapi_v2_api_call_chainhead_ns",
 		Help: "Time it takes to compelete a chainhead",
 	})
 
+	HandleV2APICallChainInfo = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_chaininfo_ns",
+		Help: "Time it takes to compelete a chaininfo",
+	})
+
 	HandleV2APICallCommitChain = prometheus.NewSummary(prometheus.SummaryOpts{
 		Name: "factomd_wsapi_v2_api_call_commitchain_ns",
 		Help: "Time it takes to compelete a commithcain",
@@ -179,6 +189,56 @@ var (
 		Name: "factomd_wsapi_v2_api_call_tpsrate_ns",
 		Help: "Time it takes to compelete a tpsrate",
 	})
+
+	HandleV2APICallEntriesByChain = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_entriesbychain_ns",
+		Help: "Time it takes to compelete an entriesbychain",
+	})
+
+	HandleV2APICallEntriesByExtID = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_entriesbyextid_ns",
+		Help: "Time it takes to compelete an entriesbyextid",
+	})
+
+	HandleV2APICallAddressTransactions = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_addresstransactions_ns",
+		Help: "Time it takes to compelete an addresstransactions",
+	})
+
+	HandleV2APICallAnchors = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_anchors_ns",
+		Help: "Time it takes to compelete an anchors",
+	})
+
+	HandleV2APICallTransactionValidate = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_transactionvalidate_ns",
+		Help: "Time it takes to compelete a transactionvalidate",
+	})
+
+	HandleV2APICallFeeEstimate = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_feeestimate_ns",
+		Help: "Time it takes to compelete a feeestimate",
+	})
+
+	RateLimitedCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_wsapi_rate_limited_total",
+		Help: "Number of API calls rejected by a rate limit",
+	}, []string{"bucket", "by"})
+
+	WebsocketSubscribers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_wsapi_v2_websocket_subscribers",
+		Help: "Number of connected websocket clients",
+	})
+
+	WebsocketNotificationsSent = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_wsapi_v2_websocket_notifications_sent_total",
+		Help: "Number of notifications queued for websocket clients",
+	})
+
+	WebsocketNotificationsDropped = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_wsapi_v2_websocket_notifications_dropped_total",
+		Help: "Number of notifications dropped because a websocket client was too slow",
+	})
 )
 
 var registered = false
@@ -193,7 +253,9 @@ func RegisterPrometheus() {
 
 	prometheus.MustRegister(GensisFblockCall)
 	prometheus.MustRegister(HandleV2APICallGeneral)
+	prometheus.MustRegister(HandleV2APICallBalanceAtHeight)
 	prometheus.MustRegister(HandleV2APICallChainHead)
+	prometheus.MustRegister(HandleV2APICallChainInfo)
 	prometheus.MustRegister(HandleV2APICallCommitChain)
 	prometheus.MustRegister(HandleV2APICallCommitEntry)
 	prometheus.MustRegister(HandleV2APICallDBlock)
@@ -225,4 +287,14 @@ func RegisterPrometheus() {
 	prometheus.MustRegister(HandleV2APICallTpsRate)
 	prometheus.MustRegister(HandleV2APICallAblock)
 	prometheus.MustRegister(HandleV2APICallFblock)
+	prometheus.MustRegister(HandleV2APICallEntriesByChain)
+	prometheus.MustRegister(HandleV2APICallEntriesByExtID)
+	prometheus.MustRegister(HandleV2APICallAddressTransactions)
+	prometheus.MustRegister(HandleV2APICallAnchors)
+	prometheus.MustRegister(HandleV2APICallTransactionValidate)
+	prometheus.MustRegister(HandleV2APICallFeeEstimate)
+	prometheus.MustRegister(RateLimitedCalls)
+	prometheus.MustRegister(WebsocketSubscribers)
+	prometheus.MustRegister(WebsocketNotificationsSent)
+	prometheus.MustRegister(WebsocketNotificationsDropped)
 }
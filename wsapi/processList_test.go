@@ -0,0 +1,45 @@
+package wsapi_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func TestHandleProcessList(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
+
+	resp, jErr := HandleProcessList(state, nil)
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	status := resp.(*interfaces.ProcessListStatus)
+	if status.DBHeight != state.GetLeaderHeight() {
+		t.Errorf("Got the process list of height %d, expected %d", status.DBHeight, state.GetLeaderHeight())
+	}
+	if len(status.VMs) != len(state.GetFedServers(status.DBHeight)) {
+		t.Errorf("Got %d VMs, expected one per federated server", len(status.VMs))
+	}
+	for _, vm := range status.VMs {
+		if vm.Leader == "" {
+			t.Errorf("VM %d has no leader", vm.VM)
+		}
+		if vm.Height+len(vm.Missing)+len(vm.Unprocessed) != vm.ListLength {
+			t.Errorf("VM %d: height %d, %d missing and %d unprocessed do not add up to %d", vm.VM, vm.Height, len(vm.Missing), len(vm.Unprocessed), vm.ListLength)
+		}
+	}
+
+	req := new(ProcessListRequest)
+	height := state.GetLeaderHeight()
+	req.DBHeight = &height
+	if _, jErr := HandleProcessList(state, req); jErr != nil {
+		t.Errorf("%v", jErr)
+	}
+
+	height = 1000000
+	if _, jErr := HandleProcessList(state, req); jErr == nil {
+		t.Error("Expected an error for a height with no process list")
+	}
+}
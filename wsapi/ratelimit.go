@@ -0,0 +1,227 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// Every client gets two token buckets for its remote IP, and two for the API user of
+// the users file it logs in as: one for calls that submit something to the network,
+// and one for everything else.  A call is only served if the buckets of both its IP
+// and its user have a token.  The FactomdRpcUser, and anyone when the API is open, is
+// only limited by IP.
+
+// submitMethods are the calls that end up in the API queue of the state
+var submitMethods = map[string]bool{
+	"commit-chain":     true,
+	"commit-entry":     true,
+	"reveal-chain":     true,
+	"reveal-entry":     true,
+	"factoid-submit":   true,
+	"send-raw-message": true,
+}
+
+// How many buckets may pile up before the full ones are dropped.  If that is not
+// enough, the buckets idle for the longest are dropped, down to rateLimitKeepBuckets.
+const (
+	rateLimitMaxBuckets  = 10000
+	rateLimitKeepBuckets = rateLimitMaxBuckets * 9 / 10
+)
+
+// How often the full buckets may be looked for
+const rateLimitSweepInterval = time.Second
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RateLimiter is a set of token buckets, one per key.  Each bucket holds up to burst
+// tokens, and gains rate tokens per second.
+type RateLimiter struct {
+	mutex     sync.Mutex
+	rate      float64
+	burst     int
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewRateLimiter returns nil if rate is 0, and a nil RateLimiter allows everything
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	l := new(RateLimiter)
+	l.rate = rate
+	l.burst = burst
+	l.buckets = make(map[string]*tokenBucket)
+	l.now = time.Now
+	return l
+}
+
+// Allow takes a token from the bucket of key, if there is one
+func (l *RateLimiter) Allow(key string) bool {
+	if l == nil {
+		return true
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	b, ok := l.buckets[key]
+	if !ok {
+		l.makeRoom(now)
+		b = &tokenBucket{tokens: float64(l.burst), last: now}
+		l.buckets[key] = b
+	}
+	l.refill(b, now)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (l *RateLimiter) refill(b *tokenBucket, now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * l.rate
+	if b.tokens > float64(l.burst) {
+		b.tokens = float64(l.burst)
+	}
+	b.last = now
+}
+
+// makeRoom keeps the number of buckets below rateLimitMaxBuckets
+func (l *RateLimiter) makeRoom(now time.Time) {
+	if len(l.buckets) < rateLimitMaxBuckets {
+		return
+	}
+	if now.Sub(l.lastSweep) >= rateLimitSweepInterval {
+		l.sweep(now)
+		l.lastSweep = now
+	}
+	if len(l.buckets) < rateLimitMaxBuckets {
+		return
+	}
+
+	// A busy client keeps using its bucket, so the idlest buckets go first.  Dropping
+	// a batch of them spares the sort on the next new clients.
+	keys := make([]string, 0, len(l.buckets))
+	for key := range l.buckets {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return l.buckets[keys[i]].last.Before(l.buckets[keys[j]].last)
+	})
+	for _, key := range keys[:len(keys)-rateLimitKeepBuckets] {
+		delete(l.buckets, key)
+	}
+}
+
+// sweep drops the buckets that are full again; a new bucket starts out full anyway
+func (l *RateLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		l.refill(b, now)
+		if b.tokens >= float64(l.burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+var (
+	rateLimitMutex sync.Mutex
+	readLimiter    *RateLimiter
+	submitLimiter  *RateLimiter
+)
+
+// SetRateLimits replaces the limiters with ones using the limits of the state
+func SetRateLimits(state interfaces.IState) {
+	readRate, readBurst, submitRate, submitBurst := state.GetAPIRateLimits()
+
+	rateLimitMutex.Lock()
+	defer rateLimitMutex.Unlock()
+	readLimiter = NewRateLimiter(readRate, readBurst)
+	submitLimiter = NewRateLimiter(submitRate, submitBurst)
+}
+
+// CheckRateLimit takes a token from the buckets of the client for the method
+func CheckRateLimit(state interfaces.IState, r *http.Request, method string) *primitives.JSONError {
+	rateLimitMutex.Lock()
+	limiter, bucket := readLimiter, "read"
+	if submitMethods[method] {
+		limiter, bucket = submitLimiter, "submit"
+	}
+	rateLimitMutex.Unlock()
+	if limiter == nil {
+		return nil
+	}
+
+	if !limiter.Allow("ip:" + rateLimitHost(r)) {
+		RateLimitedCalls.WithLabelValues(bucket, "ip").Inc()
+		return NewRateLimitedError()
+	}
+	// Only the users of the users file are known, anything else would let a client
+	// make up a new bucket for every call
+	if u, err := Authenticate(state, r); err == nil && u != rpcUser {
+		if !limiter.Allow(u.rateLimitKey()) {
+			RateLimitedCalls.WithLabelValues(bucket, "user").Inc()
+			return NewRateLimitedError()
+		}
+	}
+	return nil
+}
+
+// rateLimited applies the rate limits to every request of a batch
+func rateLimited(r *http.Request, handler RequestHandler) RequestHandler {
+	return func(state interfaces.IState, j *primitives.JSON2Request) (*primitives.JSON2Response, *primitives.JSONError) {
+		if jsonError := CheckRateLimit(state, r, j.Method); jsonError != nil {
+			return nil, jsonError
+		}
+		return handler(state, j)
+	}
+}
+
+// v1Method is the name of a v1 call, which is the name of its v2 equivalent
+func v1Method(r *http.Request) string {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
+
+// rateLimitHost is the remote IP of a request, or its /64 for IPv6: a single IPv6
+// client usually holds a whole /64, and could take a new address for every call
+func rateLimitHost(r *http.Request) string {
+	host := remoteHost(r)
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return host
+	}
+	if ip.To4() != nil {
+		return ip.String()
+	}
+	return ip.Mask(net.CIDRMask(64, 128)).String() + "/64"
+}
+
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,91 @@
+package wsapi
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	var unlimited *RateLimiter
+	if NewRateLimiter(0, 10) != nil || !unlimited.Allow("ip:127.0.0.1") {
+		t.Error("A rate of 0 is not unlimited")
+	}
+
+	now := time.Unix(1500000000, 0)
+	l := NewRateLimiter(20, 2)
+	l.now = func() time.Time { return now }
+
+	if !l.Allow("ip:127.0.0.1") || !l.Allow("ip:127.0.0.1") {
+		t.Error("The burst was not allowed")
+	}
+	if l.Allow("ip:127.0.0.1") {
+		t.Error("A call past the burst was allowed")
+	}
+	// Every client has its own bucket
+	if !l.Allow("ip:127.0.0.2") || !l.Allow("user:alice") {
+		t.Error("Another client was limited")
+	}
+
+	// 20 tokens a second, one is back after 50ms
+	now = now.Add(40 * time.Millisecond)
+	if l.Allow("ip:127.0.0.1") {
+		t.Error("The bucket refilled too early")
+	}
+	now = now.Add(20 * time.Millisecond)
+	if !l.Allow("ip:127.0.0.1") {
+		t.Error("The bucket did not refill")
+	}
+}
+
+func TestRateLimiterEviction(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	// Slow enough that no bucket is full again during the test
+	l := NewRateLimiter(0.001, 2)
+	l.now = func() time.Time { return now }
+
+	// A busy client, which empties its bucket and keeps calling
+	busy := "ip:10.0.0.1"
+	l.Allow(busy)
+	l.Allow(busy)
+	for i := 0; len(l.buckets) < rateLimitMaxBuckets; i++ {
+		now = now.Add(time.Millisecond)
+		l.Allow(fmt.Sprintf("ip:10.1.%d.%d", i/256, i%256))
+		if i%100 == 0 {
+			l.Allow(busy)
+		}
+	}
+
+	// One more client drops the idlest buckets, which are not full yet
+	now = now.Add(time.Millisecond)
+	l.Allow("ip:10.2.0.1")
+	if len(l.buckets) > rateLimitKeepBuckets+1 {
+		t.Errorf("%d buckets are left, expected %d", len(l.buckets), rateLimitKeepBuckets+1)
+	}
+	if _, ok := l.buckets[busy]; !ok {
+		t.Error("The bucket of the busy client was dropped")
+	}
+	if _, ok := l.buckets["ip:10.1.0.0"]; ok {
+		t.Error("The idlest bucket was kept")
+	}
+	if l.Allow(busy) {
+		t.Error("The busy client got a new, full bucket")
+	}
+}
+
+func TestRateLimitHost(t *testing.T) {
+	for addr, expected := range map[string]string{
+		"127.0.0.1:8088":              "127.0.0.1",
+		"[2001:db8:1:2:3:4:5:6]:8088": "2001:db8:1:2::/64",
+		"[2001:db8:1:2:ffff::1]:8088": "2001:db8:1:2::/64",
+		"[::ffff:192.168.0.1]:8088":   "192.168.0.1",
+		"not an address":              "not an address",
+		"[2001:db8:1:3:3:4:5:6]:8088": "2001:db8:1:3::/64",
+	} {
+		r := &http.Request{RemoteAddr: addr}
+		if host := rateLimitHost(r); host != expected {
+			t.Errorf("%s is limited as %s, expected %s", addr, host, expected)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/web"
+)
+
+// The node describes its APIs as OpenRPC documents, at /v2/schema and /debug/schema.
+// The JSON schemas of the params and results are generated from the structs the
+// handlers use, so every method of HandleV2Request and HandleDebugRequest needs an
+// entry below.
+
+// MethodSchema gives the params and the result of a method, as values of the structs
+// the handler decodes and returns.  Params is nil for methods without params.
+type MethodSchema struct {
+	Params interface{}
+	Result interface{}
+}
+
+// AddressesRequest are the params of multiple-fct-balances and multiple-ec-balances
+type AddressesRequest struct {
+	Addresses []string `json:"addresses"`
+}
+
+var V2Methods = map[string]MethodSchema{
+	"balance-at-height":     {BalanceAtHeightRequest{}, BalanceAtHeightResponse{}},
+	"chain-head":            {ChainIDRequest{}, ChainHeadResponse{}},
+	"chain-info":            {ChainIDRequest{}, ChainInfoResponse{}},
+	"commit-chain":          {MessageRequest{}, CommitChainResponse{}},
+	"commit-entry":          {MessageRequest{}, CommitEntryResponse{}},
+	"current-minute":        {nil, CurrentMinuteResponse{}},
+	"directory-block":       {KeyMRRequest{}, DirectoryBlockResponse{}},
+	"directory-block-head":  {nil, DirectoryBlockHeadResponse{}},
+	"entry-block":           {KeyMRRequest{}, EntryBlockResponse{}},
+	"admin-block":           {KeyMRRequest{}, BlockHeightResponse{}},
+	"factoid-block":         {KeyMRRequest{}, BlockHeightResponse{}},
+	"entrycredit-block":     {KeyMRRequest{}, EntryCreditBlockResponse{}},
+	"entry":                 {HashRequest{}, EntryResponse{}},
+	"entry-credit-balance":  {AddressRequest{}, EntryCreditBalanceResponse{}},
+	"entry-credit-rate":     {nil, EntryCreditRateResponse{}},
+	"factoid-balance":       {AddressRequest{}, FactoidBalanceResponse{}},
+	"factoid-submit":        {TransactionRequest{}, FactoidSubmitResponse{}},
+	"heights":               {nil, HeightsResponse{}},
+	"properties":            {nil, PropertiesResponse{}},
+	"raw-data":              {HashRequest{}, RawDataResponse{}},
+	"receipt":               {HashRequest{}, ReceiptResponse{}},
+	"reveal-chain":          {EntryRequest{}, RevealEntryResponse{}},
+	"reveal-entry":          {EntryRequest{}, RevealEntryResponse{}},
+	"factoid-ack":           {AckRequest{}, FactoidTxStatus{}},
+	"entry-ack":             {AckRequest{}, EntryStatus{}},
+	"pending-entries":       {ChainIDRequest{}, []interfaces.IPendingEntry{}},
+	"pending-transactions":  {AddressRequest{}, []interfaces.IPendingTransaction{}},
+	"send-raw-message":      {SendRawMessageRequest{}, SendRawMessageResponse{}},
+	"transaction":           {HashRequest{}, TransactionResponse{}},
+	"dblock-by-height":      {HeightRequest{}, BlockHeightResponse{}},
+	"ecblock-by-height":     {HeightRequest{}, EntryCreditBlockResponse{}},
+	"fblock-by-height":      {HeightRequest{}, BlockHeightResponse{}},
+	"ablock-by-height":      {HeightRequest{}, BlockHeightResponse{}},
+	"authorities":           {nil, AuthoritiesResponse{}},
+	"tps-rate":              {nil, TransactionRateResponse{}},
+	"ack":                   {EntryAckWithChainRequest{}, EntryStatus{}},
+	"multiple-fct-balances": {AddressesRequest{}, MultipleFTBalances{}},
+	"multiple-ec-balances":  {AddressesRequest{}, MultipleECBalances{}},
+	"entries-by-chain":      {EntriesByChainRequest{}, EntriesByChainResponse{}},
+	"entries-by-extid":      {EntriesByExtIDRequest{}, EntriesByExtIDResponse{}},
+	"address-transactions":  {AddressTransactionsRequest{}, AddressTransactionsResponse{}},
+	"anchors":               {HeightRequest{}, AnchorsResponse{}},
+	"transaction-validate":  {TransactionValidateRequest{}, TransactionValidateResponse{}},
+	"fee-estimate":          {FeeEstimateRequest{}, FeeEstimateResponse{}},
+}
+
+var DebugMethods = map[string]MethodSchema{
+	"audit-servers":        {nil, AuditServersResponse{}},
+	"authorities":          {nil, AuthoritiesResponse{}},
+	"configuration":        {nil, new(interfaces.IFactomConfig)},
+	"current-minute":       {nil, DebugCurrentMinuteResponse{}},
+	"delay":                {nil, DelayResponse{}},
+	"set-delay":            {SetDelayRequest{}, DelayResponse{}},
+	"drop-rate":            {nil, DropRateResponse{}},
+	"set-drop-rate":        {SetDropRateRequest{}, DropRateResponse{}},
+	"federated-servers":    {nil, FedServersResponse{}},
+	"holding-queue":        {nil, HoldingQueueResponse{}},
+	"holding-list":         {HoldingListRequest{}, HoldingListResponse{}},
+	"holding-evict":        {HoldingEvictRequest{}, HoldingEvictResponse{}},
+	"messages":             {nil, MessagesResponse{}},
+	"network-info":         {nil, NetworkInfoResponse{}},
+	"summary":              {nil, SummaryResponse{}},
+	"predictive-fer":       {nil, PredictiveFERResponse{}},
+	"process-list":         {ProcessListRequest{}, interfaces.ProcessListStatus{}},
+	"reload-configuration": {nil, new(interfaces.IFactomConfig)},
+	"snapshot":             {SnapshotRequest{}, interfaces.SnapshotStatus{}},
+	"snapshot-status":      {nil, interfaces.SnapshotStatus{}},
+}
+
+type OpenRPCDocument struct {
+	OpenRPC string          `json:"openrpc"`
+	Info    OpenRPCInfo     `json:"info"`
+	Methods []OpenRPCMethod `json:"methods"`
+}
+
+type OpenRPCInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type OpenRPCMethod struct {
+	Name           string           `json:"name"`
+	ParamStructure string           `json:"paramStructure"`
+	Params         []OpenRPCContent `json:"params"`
+	Result         OpenRPCContent   `json:"result"`
+}
+
+type OpenRPCContent struct {
+	Name     string                 `json:"name"`
+	Required bool                   `json:"required,omitempty"`
+	Schema   map[string]interface{} `json:"schema"`
+}
+
+// NewOpenRPCDocument describes the methods, sorted by name
+func NewOpenRPCDocument(title string, methods map[string]MethodSchema) *OpenRPCDocument {
+	doc := new(OpenRPCDocument)
+	doc.OpenRPC = "1.2.6"
+	doc.Info.Title = title
+	doc.Info.Version = API_VERSION
+
+	names := make([]string, 0, len(methods))
+	for name := range methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		m := methods[name]
+		method := OpenRPCMethod{Name: name, ParamStructure: "by-name", Params: []OpenRPCContent{}}
+		if m.Params != nil {
+			for _, f := range jsonFields(reflect.TypeOf(m.Params)) {
+				method.Params = append(method.Params, OpenRPCContent{
+					Name:     f.name,
+					Required: f.required,
+					Schema:   TypeSchema(f.typ),
+				})
+			}
+		}
+		method.Result = OpenRPCContent{Name: "result", Schema: TypeSchema(reflect.TypeOf(m.Result))}
+		doc.Methods = append(doc.Methods, method)
+	}
+	return doc
+}
+
+var (
+	hashType          = reflect.TypeOf((*interfaces.IHash)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+)
+
+// TypeSchema returns the JSON schema of the JSON encoding of a Go type.  Interfaces
+// and types with their own JSON encoding, which the schema can't follow, allow any
+// value, and name the Go type in "x-go-type".
+func TypeSchema(t reflect.Type) map[string]interface{} {
+	return typeSchema(t, map[reflect.Type]bool{})
+}
+
+func typeSchema(t reflect.Type, parents map[reflect.Type]bool) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == hashType:
+		return map[string]interface{}{"type": "string", "pattern": "^[0-9a-f]{64}$"}
+	case t == rawMessageType:
+		return map[string]interface{}{"x-go-type": t.String()}
+	case t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType):
+		return map[string]interface{}{"type": "string", "x-go-type": t.String()}
+	case t.Kind() != reflect.Interface && (t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType)):
+		return map[string]interface{}{"x-go-type": t.String()}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem(), parents)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem(), parents)}
+	case reflect.Struct:
+		// A struct containing itself is only described once
+		if parents[t] {
+			return map[string]interface{}{"type": "object", "x-go-type": t.String()}
+		}
+		parents[t] = true
+		defer delete(parents, t)
+
+		properties := map[string]interface{}{}
+		required := []string{}
+		for _, f := range jsonFields(t) {
+			properties[f.name] = typeSchema(f.typ, parents)
+			if f.required {
+				required = append(required, f.name)
+			}
+		}
+		schema := map[string]interface{}{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	}
+	return map[string]interface{}{"x-go-type": t.String()}
+}
+
+type jsonField struct {
+	name     string
+	typ      reflect.Type
+	required bool
+}
+
+// jsonFields lists the fields encoding/json writes for a struct, in order
+func jsonFields(t reflect.Type) []jsonField {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var fields []jsonField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options := tag, ""
+		if i := strings.Index(tag, ","); i >= 0 {
+			name, options = tag[:i], tag[i+1:]
+		}
+
+		// Embedded structs without a name have their fields promoted
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				fields = append(fields, jsonFields(ft)...)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue // unexported
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, jsonField{
+			name:     name,
+			typ:      f.Type,
+			required: !strings.Contains(options, "omitempty") && f.Type.Kind() != reflect.Ptr,
+		})
+	}
+	return fields
+}
+
+func HandleV2Schema(ctx *web.Context) {
+	serveSchema(ctx, APIV2, "factomd v2 API", V2Methods)
+}
+
+func HandleDebugSchema(ctx *web.Context) {
+	serveSchema(ctx, APIDebug, "factomd debug API", DebugMethods)
+}
+
+func serveSchema(ctx *web.Context, api string, title string, methods map[string]MethodSchema) {
+	ServersMutex.Lock()
+	state := ctx.Server.Env["state"].(interfaces.IState)
+	ServersMutex.Unlock()
+
+	if err := checkAuthHeader(state, ctx.Request); err != nil {
+		fmt.Printf("Unauthorized API schema client connection attempt from %s\n", remoteHost(ctx.Request))
+		ctx.ResponseWriter.Header().Add("WWW-Authenticate", `Basic realm="factomd RPC"`)
+		http.Error(ctx.ResponseWriter, "401 Unauthorized.", http.StatusUnauthorized)
+		return
+	}
+	if jsonError := CheckPermission(state, ctx.Request, api, "schema"); jsonError != nil {
+		HandleV2Error(ctx, nil, jsonError)
+		return
+	}
+
+	data, err := json.Marshal(NewOpenRPCDocument(title, methods))
+	if err != nil {
+		HandleV2Error(ctx, nil, NewInternalError())
+		return
+	}
+	ctx.ResponseWriter.Header().Set("Content-Type", "application/json")
+	ctx.Write(data)
+}
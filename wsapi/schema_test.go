@@ -0,0 +1,98 @@
+package wsapi_test
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"testing"
+
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+// switchCases returns the string cases of the switch statements in a function
+func switchCases(t *testing.T, filename string, function string) []string {
+	f, err := parser.ParseFile(token.NewFileSet(), filename, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cases []string
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != function {
+			continue
+		}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			clause, ok := n.(*ast.CaseClause)
+			if !ok {
+				return true
+			}
+			for _, e := range clause.List {
+				if lit, ok := e.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					method, _ := strconv.Unquote(lit.Value)
+					cases = append(cases, method)
+				}
+			}
+			return true
+		})
+	}
+	if len(cases) == 0 {
+		t.Fatalf("No methods found in %s of %s", function, filename)
+	}
+	return cases
+}
+
+func TestSchemaCoversMethods(t *testing.T) {
+	for _, api := range []struct {
+		filename string
+		function string
+		methods  map[string]MethodSchema
+	}{
+		{"wsapiV2.go", "HandleV2Request", V2Methods},
+		{"debugapi.go", "HandleDebugRequest", DebugMethods},
+	} {
+		cases := map[string]bool{}
+		for _, method := range switchCases(t, api.filename, api.function) {
+			cases[method] = true
+			if _, ok := api.methods[method]; !ok {
+				t.Errorf("%s has no schema for %s", api.function, method)
+			}
+		}
+		for method := range api.methods {
+			if !cases[method] {
+				t.Errorf("%s has a schema for %s, which it does not handle", api.function, method)
+			}
+		}
+	}
+}
+
+func TestOpenRPCDocument(t *testing.T) {
+	doc := NewOpenRPCDocument("factomd v2 API", V2Methods)
+	if len(doc.Methods) != len(V2Methods) {
+		t.Fatalf("Expected %d methods, got %d", len(V2Methods), len(doc.Methods))
+	}
+	if _, err := json.Marshal(doc); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, m := range doc.Methods {
+		switch m.Name {
+		case "heights":
+			if len(m.Params) != 0 {
+				t.Errorf("heights has params %v", m.Params)
+			}
+			properties, _ := m.Result.Schema["properties"].(map[string]interface{})
+			if _, ok := properties["directoryblockheight"]; !ok {
+				t.Errorf("The heights result is missing directoryblockheight: %v", m.Result.Schema)
+			}
+		case "entry":
+			if len(m.Params) != 1 || m.Params[0].Name != "hash" || !m.Params[0].Required {
+				t.Errorf("Wrong params for entry: %v", m.Params)
+			}
+			if m.Params[0].Schema["type"] != "string" {
+				t.Errorf("The hash of entry is not a string: %v", m.Params[0].Schema)
+			}
+		}
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// Topics a websocket client can subscribe to
+const (
+	TopicNewDBlocks          = "new-dblocks"
+	TopicNewEntries          = "new-entries"
+	TopicFactoidTransactions = "factoid-transactions"
+	TopicAckStatus           = "ack-status"
+)
+
+// SubscriberBufferSize is the number of notifications queued for a single client.  Once
+// the queue is full, further notifications for that client are dropped.  Publishing is
+// called from the consensus thread, so it must never block on a slow consumer.
+var SubscriberBufferSize = 256
+
+// SubscriptionNotification is pushed to a websocket client when an event matches one
+// of its subscriptions
+type SubscriptionNotification struct {
+	JSONRPC string             `json:"jsonrpc"`
+	Method  string             `json:"method"`
+	Params  SubscriptionResult `json:"params"`
+}
+
+type SubscriptionResult struct {
+	Subscription int64       `json:"subscription"`
+	Topic        string      `json:"topic"`
+	Result       interface{} `json:"result"`
+}
+
+type subscription struct {
+	id     int64
+	topic  string
+	filter [32]byte
+}
+
+// Subscriber holds all the subscriptions of one websocket client.  Notifications
+// is closed when the subscriber is closed.
+type Subscriber struct {
+	Notifications chan *SubscriptionNotification
+
+	node          string
+	subscriptions map[int64]*subscription
+}
+
+type subscriptionHub struct {
+	mutex       sync.RWMutex
+	subscribers map[*Subscriber]struct{}
+	nextID      int64
+	count       int32 // number of active subscriptions, read without the lock
+}
+
+var hub = &subscriptionHub{subscribers: make(map[*Subscriber]struct{})}
+
+// NewSubscriber creates a subscriber that receives events published by the node
+// with the given name.  In the simulator, many nodes share the same process.
+func NewSubscriber(node string) *Subscriber {
+	s := new(Subscriber)
+	s.Notifications = make(chan *SubscriptionNotification, SubscriberBufferSize)
+	s.node = node
+	s.subscriptions = make(map[int64]*subscription)
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	hub.subscribers[s] = struct{}{}
+	return s
+}
+
+// Subscribe adds a subscription to the given topic.  The filter is the chain ID, the
+// address or the hash to watch; it is ignored for TopicNewDBlocks.
+func (s *Subscriber) Subscribe(topic string, filter interfaces.IHash) (int64, bool) {
+	switch topic {
+	case TopicNewDBlocks:
+	case TopicNewEntries, TopicFactoidTransactions, TopicAckStatus:
+		if filter == nil {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	if _, ok := hub.subscribers[s]; !ok {
+		return 0, false
+	}
+
+	hub.nextID++
+	sub := new(subscription)
+	sub.id = hub.nextID
+	sub.topic = topic
+	if filter != nil && topic != TopicNewDBlocks {
+		sub.filter = filter.Fixed()
+	}
+	s.subscriptions[sub.id] = sub
+	atomic.AddInt32(&hub.count, 1)
+	return sub.id, true
+}
+
+// Unsubscribe removes a subscription.  Returns false if the id is unknown.
+func (s *Subscriber) Unsubscribe(id int64) bool {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	if _, ok := s.subscriptions[id]; !ok {
+		return false
+	}
+	delete(s.subscriptions, id)
+	atomic.AddInt32(&hub.count, -1)
+	return true
+}
+
+// Close removes the subscriber from the hub and closes its Notifications channel
+func (s *Subscriber) Close() {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	if _, ok := hub.subscribers[s]; !ok {
+		return
+	}
+	delete(hub.subscribers, s)
+	atomic.AddInt32(&hub.count, -int32(len(s.subscriptions)))
+	s.subscriptions = make(map[int64]*subscription)
+	close(s.Notifications)
+}
+
+// HasSubscribers is a cheap check callers can use to avoid building events nobody
+// is listening to
+func HasSubscribers() bool {
+	return atomic.LoadInt32(&hub.count) > 0
+}
+
+// publish hands the result to every matching subscription without blocking
+func publish(node string, topic string, filter [32]byte, result interface{}) {
+	hub.mutex.RLock()
+	defer hub.mutex.RUnlock()
+
+	for s := range hub.subscribers {
+		if s.node != node {
+			continue
+		}
+		for _, sub := range s.subscriptions {
+			if sub.topic != topic || sub.filter != filter {
+				continue
+			}
+			n := new(SubscriptionNotification)
+			n.JSONRPC = "2.0"
+			n.Method = "subscription"
+			n.Params.Subscription = sub.id
+			n.Params.Topic = topic
+			n.Params.Result = result
+			select {
+			case s.Notifications <- n:
+				WebsocketNotificationsSent.Inc()
+			default:
+				WebsocketNotificationsDropped.Inc()
+			}
+		}
+	}
+}
+
+// PublishDBlock notifies the TopicNewDBlocks subscribers of a saved directory block
+func PublishDBlock(node string, dblock interfaces.IDirectoryBlock) {
+	if !HasSubscribers() {
+		return
+	}
+	r := new(NewDBlockNotification)
+	r.DBHeight = int64(dblock.GetDatabaseHeight())
+	r.KeyMR = dblock.GetKeyMR().String()
+	r.Timestamp = dblock.GetHeader().GetTimestamp().GetTimeSeconds()
+	publish(node, TopicNewDBlocks, [32]byte{}, r)
+}
+
+// PublishEntryBlock notifies the TopicNewEntries subscribers of the chain of a saved
+// entry block
+func PublishEntryBlock(node string, eblock interfaces.IEntryBlock) {
+	if !HasSubscribers() {
+		return
+	}
+	r := new(NewEntriesNotification)
+	r.ChainID = eblock.GetChainID().String()
+	r.DBHeight = int64(eblock.GetDatabaseHeight())
+	if keymr, err := eblock.KeyMR(); err == nil {
+		r.EntryBlockKeyMR = keymr.String()
+	}
+	for _, h := range eblock.GetEntryHashes() {
+		if h.IsMinuteMarker() {
+			continue
+		}
+		r.EntryHashes = append(r.EntryHashes, h.String())
+	}
+	publish(node, TopicNewEntries, eblock.GetChainID().Fixed(), r)
+}
+
+// PublishFactoidTransaction notifies the TopicFactoidTransactions subscribers of every
+// address the transaction touches
+func PublishFactoidTransaction(node string, tx interfaces.ITransaction, dbheight uint32) {
+	if !HasSubscribers() {
+		return
+	}
+	notify := func(adr interfaces.ITransAddress, direction string) {
+		r := new(FactoidTransactionNotification)
+		r.TxID = tx.GetSigHash().String()
+		r.DBHeight = int64(dbheight)
+		r.Address = adr.GetAddress().String()
+		r.Direction = direction
+		r.Amount = adr.GetAmount()
+		publish(node, TopicFactoidTransactions, adr.GetAddress().Fixed(), r)
+	}
+	for _, adr := range tx.GetInputs() {
+		notify(adr, "input")
+	}
+	for _, adr := range tx.GetOutputs() {
+		notify(adr, "output")
+	}
+	for _, adr := range tx.GetECOutputs() {
+		notify(adr, "ecoutput")
+	}
+}
+
+// PublishAckStatus notifies the TopicAckStatus subscribers watching the given
+// transaction ID or entry hash
+func PublishAckStatus(node string, hash interfaces.IHash, status int) {
+	if !HasSubscribers() {
+		return
+	}
+	r := new(AckStatusNotification)
+	r.Hash = hash.String()
+	r.Status = constants.AckStatusString(status)
+	publish(node, TopicAckStatus, hash.Fixed(), r)
+}
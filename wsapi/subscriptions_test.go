@@ -0,0 +1,130 @@
+package wsapi_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func TestSubscriptions(t *testing.T) {
+	blockSet := testHelper.CreateTestBlockSet(nil)
+
+	sub := NewSubscriber("FNode0")
+	other := NewSubscriber("FNode1")
+	defer other.Close()
+
+	if _, ok := sub.Subscribe("no-such-topic", nil); ok {
+		t.Error("Subscribed to an unknown topic")
+	}
+	if _, ok := sub.Subscribe(TopicNewEntries, nil); ok {
+		t.Error("Subscribed to new-entries without a chain id")
+	}
+
+	dbID, ok := sub.Subscribe(TopicNewDBlocks, nil)
+	if !ok {
+		t.Fatal("Could not subscribe to new-dblocks")
+	}
+	if _, ok := other.Subscribe(TopicNewDBlocks, nil); !ok {
+		t.Fatal("Could not subscribe to new-dblocks")
+	}
+	if !HasSubscribers() {
+		t.Error("HasSubscribers() should be true")
+	}
+
+	PublishDBlock("FNode0", blockSet.DBlock)
+	select {
+	case n := <-sub.Notifications:
+		if n.Params.Subscription != dbID || n.Params.Topic != TopicNewDBlocks {
+			t.Errorf("Wrong notification %v", n)
+		}
+		r := n.Params.Result.(*NewDBlockNotification)
+		if r.KeyMR != blockSet.DBlock.GetKeyMR().String() {
+			t.Errorf("Wrong KeyMR %v", r.KeyMR)
+		}
+	default:
+		t.Error("No notification for new-dblocks")
+	}
+	if len(other.Notifications) != 0 {
+		t.Error("Subscriber to another node got a notification")
+	}
+
+	ackID, ok := sub.Subscribe(TopicAckStatus, blockSet.EBlock.GetChainID())
+	if !ok {
+		t.Fatal("Could not subscribe to ack-status")
+	}
+	PublishAckStatus("FNode0", primitives.NewZeroHash(), constants.AckStatusACK)
+	if len(sub.Notifications) != 0 {
+		t.Error("Got a notification for an unwatched hash")
+	}
+	PublishAckStatus("FNode0", blockSet.EBlock.GetChainID(), constants.AckStatusACK)
+	n := <-sub.Notifications
+	if n.Params.Subscription != ackID || n.Params.Result.(*AckStatusNotification).Status != constants.AckStatusACKString {
+		t.Errorf("Wrong notification %v", n)
+	}
+
+	if !sub.Unsubscribe(ackID) {
+		t.Error("Could not unsubscribe")
+	}
+	if sub.Unsubscribe(ackID) {
+		t.Error("Unsubscribed twice")
+	}
+
+	// A slow consumer must not block the publisher
+	for i := 0; i < SubscriberBufferSize+10; i++ {
+		PublishDBlock("FNode0", blockSet.DBlock)
+	}
+	if len(sub.Notifications) != SubscriberBufferSize {
+		t.Errorf("Expected a full buffer, got %d", len(sub.Notifications))
+	}
+
+	sub.Close()
+	sub.Close()
+	if _, ok := sub.Subscribe(TopicNewDBlocks, nil); ok {
+		t.Error("Subscribed on a closed subscriber")
+	}
+}
+
+func TestHandleSubscriptionRequest(t *testing.T) {
+	sub := NewSubscriber("FNode0")
+	defer sub.Close()
+
+	_, _, fa := testHelper.NewFactoidAddressStrings(1)
+
+	req := new(SubscribeRequest)
+	req.Topic = TopicFactoidTransactions
+	req.Address = fa
+	resp, jErr := HandleSubscriptionRequest(sub, primitives.NewJSON2Request("subscribe", 1, req))
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	id := resp.(*SubscribeResponse).Subscription
+
+	req.Address = "not an address"
+	_, jErr = HandleSubscriptionRequest(sub, primitives.NewJSON2Request("subscribe", 2, req))
+	if jErr == nil {
+		t.Error("Subscribed with an invalid address")
+	}
+
+	req.Topic = "unknown"
+	_, jErr = HandleSubscriptionRequest(sub, primitives.NewJSON2Request("subscribe", 3, req))
+	if jErr == nil {
+		t.Error("Subscribed to an unknown topic")
+	}
+
+	_, jErr = HandleSubscriptionRequest(sub, primitives.NewJSON2Request("unsubscribe", 4, UnsubscribeRequest{Subscription: id}))
+	if jErr != nil {
+		t.Errorf("%v", jErr)
+	}
+	_, jErr = HandleSubscriptionRequest(sub, primitives.NewJSON2Request("unsubscribe", 5, UnsubscribeRequest{Subscription: id}))
+	if jErr == nil {
+		t.Error("Unsubscribed twice")
+	}
+
+	_, jErr = HandleSubscriptionRequest(sub, primitives.NewJSON2Request("heights", 6, nil))
+	if jErr == nil {
+		t.Error("Expected method not found")
+	}
+}
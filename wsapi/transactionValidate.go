@@ -0,0 +1,256 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The codes of the reasons a transaction or commit is rejected
+const (
+	ValidateDecode              = "decode"
+	ValidateAmount              = "amount-out-of-range"
+	ValidateNoInputs            = "no-inputs"
+	ValidateRCDCount            = "rcd-count"
+	ValidateRCDMismatch         = "rcd-mismatch"
+	ValidateSignature           = "invalid-signature"
+	ValidateTooLarge            = "too-large"
+	ValidateInputsDontCover     = "insufficient-inputs"
+	ValidateInsufficientFee     = "insufficient-fee"
+	ValidateInsufficientBalance = "insufficient-balance"
+	ValidateTooOld              = "too-old"
+	ValidateAlreadySubmitted    = "already-submitted"
+	ValidateVersion             = "invalid-version"
+	ValidateCredits             = "invalid-credits"
+	ValidateECBalance           = "insufficient-ec-balance"
+	ValidateRepeatCommit        = "repeat-commit"
+)
+
+// HandleV2TransactionValidate dry runs a factoid transaction, or an entry or chain
+// commit: it runs the checks the node applies before accepting it, against the
+// current pending balances, without submitting it.  Every failed check is listed,
+// not only the first one.
+func HandleV2TransactionValidate(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallTransactionValidate.Observe(float64(time.Since(n).Nanoseconds()))
+
+	req := new(TransactionValidateRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	resp := new(TransactionValidateResponse)
+	resp.Failures = make([]ValidationFailure, 0)
+
+	data, err := hex.DecodeString(req.Transaction)
+	switch req.Type {
+	case "", "factoid":
+		resp.Type = "factoid"
+		if err == nil {
+			validateFactoidTransaction(state, data, resp)
+		}
+	case "commit-entry":
+		resp.Type = req.Type
+		if err == nil {
+			validateCommitEntry(state, data, resp)
+		}
+	case "commit-chain":
+		resp.Type = req.Type
+		if err == nil {
+			validateCommitChain(state, data, resp)
+		}
+	default:
+		return nil, NewCustomInvalidParamsError("Invalid type, expected 'factoid', 'commit-entry' or 'commit-chain'")
+	}
+	if err != nil {
+		resp.fail(ValidateDecode, "The transaction is not valid hex")
+	}
+
+	resp.Valid = len(resp.Failures) == 0
+	return resp, nil
+}
+
+func (r *TransactionValidateResponse) fail(code string, format string, args ...interface{}) {
+	r.Failures = append(r.Failures, ValidationFailure{Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+// validateFactoidTransaction follows FBlock.ValidateTransaction and FactoidState.Validate
+func validateFactoidTransaction(state interfaces.IState, data []byte, resp *TransactionValidateResponse) {
+	msg := new(messages.FactoidTransaction)
+	rest, err := msg.UnmarshalTransData(data)
+	if err != nil || len(rest) != 0 {
+		resp.fail(ValidateDecode, "Unable to decode the transaction")
+		return
+	}
+	tx := msg.Transaction
+	resp.TxID = tx.GetSigHash().String()
+
+	// Well formed
+	tin, errIn := tx.TotalInputs()
+	if errIn != nil {
+		resp.fail(ValidateAmount, "%v", errIn)
+	}
+	tout, errOut := tx.TotalOutputs()
+	if errOut != nil {
+		resp.fail(ValidateAmount, "%v", errOut)
+	}
+	tec, errEC := tx.TotalECs()
+	if errEC != nil {
+		resp.fail(ValidateAmount, "%v", errEC)
+	}
+	if len(tx.GetInputs()) == 0 {
+		resp.fail(ValidateNoInputs, "Transactions (other than the coinbase) must have at least one input")
+	}
+	rcds := tx.GetRCDs()
+	if len(tx.GetInputs()) != len(rcds) {
+		resp.fail(ValidateRCDCount, "The transaction has %d inputs and %d RCDs, every input must have a corresponding RCD", len(tx.GetInputs()), len(rcds))
+	}
+	for i, rcd := range rcds {
+		if i >= len(tx.GetInputs()) {
+			break
+		}
+		address, err := rcd.GetAddress()
+		if err != nil {
+			resp.fail(ValidateRCDMismatch, "RCD %d failed to provide an address to compare with its input", i)
+			continue
+		}
+		if !tx.GetInputs()[i].GetAddress().IsSameAs(address) {
+			resp.fail(ValidateRCDMismatch, "Input %d does not match RCD %d", i, i)
+		}
+	}
+
+	// Properly signed
+	sigBlocks := tx.GetSignatureBlocks()
+	for i, rcd := range rcds {
+		if i >= len(sigBlocks) || !rcd.CheckSig(tx, sigBlocks[i]) {
+			resp.fail(ValidateSignature, "The signature of input %d is missing or invalid", i)
+		}
+	}
+
+	// Inputs cover the outputs and the fee, at the rate of the current block
+	rate := state.GetFactoshisPerEC()
+	fBlock := state.GetFactoidState().GetCurrentBlock()
+	if fBlock != nil {
+		rate = fBlock.GetExchRate()
+	}
+	resp.ExchangeRate = rate
+	fee, err := tx.CalculateFee(rate)
+	if err != nil {
+		resp.fail(ValidateTooLarge, "%v", err)
+	}
+	resp.Fee = fee
+	if errIn == nil && errOut == nil && errEC == nil {
+		if tin < tout+tec {
+			resp.fail(ValidateInputsDontCover, "The inputs %s do not cover the outputs %s and the Entry Credit outputs %s",
+				primitives.ConvertDecimalToString(tin),
+				primitives.ConvertDecimalToString(tout),
+				primitives.ConvertDecimalToString(tec))
+		} else if err == nil && tin < tout+tec+fee {
+			resp.fail(ValidateInsufficientFee, "The inputs %s leave a fee of %s, the required fee is %s",
+				primitives.ConvertDecimalToString(tin),
+				primitives.ConvertDecimalToString(tin-tout-tec),
+				primitives.ConvertDecimalToString(fee))
+		}
+	}
+
+	// The pending balances cover the inputs
+	sums := map[[32]byte]uint64{}
+	order := [][32]byte{}
+	for _, input := range tx.GetInputs() {
+		adr := input.GetAddress().Fixed()
+		if _, ok := sums[adr]; !ok {
+			order = append(order, adr)
+		}
+		sum, err := factoid.ValidateAmounts(sums[adr], input.GetAmount())
+		if err != nil {
+			continue // Already reported
+		}
+		sums[adr] = sum
+	}
+	for _, adr := range order {
+		balance := state.GetFactoidState().GetFactoidBalance(adr)
+		if int64(sums[adr]) > balance {
+			resp.fail(ValidateInsufficientBalance, "Address %s spends %s, its balance is %s",
+				primitives.ConvertFctAddressToUserStr(factoid.NewAddress(adr[:])),
+				primitives.ConvertDecimalToString(sums[adr]),
+				primitives.ConvertDecimalToString(uint64(balance)))
+		}
+	}
+
+	// Not too old for the current block
+	if fBlock != nil && fBlock.GetCoinbaseTimestamp().GetTimeMilli() >= 0 {
+		err = state.GetFactoidState().ValidateTransactionAge(tx)
+		if err != nil {
+			resp.fail(ValidateTooOld, "%v", err)
+		}
+	}
+
+	// Not submitted before
+	found, err := state.FetchFactoidTransactionByHash(tx.GetSigHash())
+	if err == nil && found != nil {
+		resp.fail(ValidateAlreadySubmitted, "The transaction %s was already submitted", resp.TxID)
+	}
+}
+
+// validateCommitEntry follows CommitEntry.IsValid and CommitEntryMsg.Validate
+func validateCommitEntry(state interfaces.IState, data []byte, resp *TransactionValidateResponse) {
+	commit := entryCreditBlock.NewCommitEntry()
+	rest, err := commit.UnmarshalBinaryData(data)
+	if err != nil || len(rest) != 0 {
+		resp.fail(ValidateDecode, "Unable to decode the entry commit")
+		return
+	}
+	resp.TxID = commit.GetSigHash().String()
+	resp.EntryHash = commit.EntryHash.String()
+
+	msg := new(messages.CommitEntryMsg)
+	msg.CommitEntry = commit
+	validateCommit(state, resp, msg, commit.Version, commit.Credits, 1, 10, commit.ValidateSignatures(), [32]byte(*commit.ECPubKey), commit.EntryHash)
+}
+
+// validateCommitChain follows CommitChain.IsValid and CommitChainMsg.Validate
+func validateCommitChain(state interfaces.IState, data []byte, resp *TransactionValidateResponse) {
+	commit := entryCreditBlock.NewCommitChain()
+	rest, err := commit.UnmarshalBinaryData(data)
+	if err != nil || len(rest) != 0 {
+		resp.fail(ValidateDecode, "Unable to decode the chain commit")
+		return
+	}
+	resp.TxID = commit.GetSigHash().String()
+	resp.EntryHash = commit.EntryHash.String()
+
+	msg := new(messages.CommitChainMsg)
+	msg.CommitChain = commit
+	validateCommit(state, resp, msg, commit.Version, commit.Credits, 11, 20, commit.ValidateSignatures(), [32]byte(*commit.ECPubKey), commit.EntryHash)
+}
+
+func validateCommit(state interfaces.IState, resp *TransactionValidateResponse, msg interfaces.IMsg, version uint8, credits uint8, min uint8, max uint8, sigErr error, ecPubKey [32]byte, entryHash interfaces.IHash) {
+	if version != 0 {
+		resp.fail(ValidateVersion, "Version %d, expected 0", version)
+	}
+	if credits < min || credits > max {
+		resp.fail(ValidateCredits, "The commit pays %d entry credits, it must pay %d to %d", credits, min, max)
+	}
+	if sigErr != nil {
+		resp.fail(ValidateSignature, "%v", sigErr)
+	}
+	balance := state.GetFactoidState().GetECBalance(ecPubKey)
+	if int64(credits) > balance {
+		resp.fail(ValidateECBalance, "The commit pays %d entry credits, the balance of %s is %d",
+			credits, primitives.ConvertECAddressToUserStr(factoid.NewAddress(ecPubKey[:])), balance)
+	}
+	if !state.IsHighestCommit(entryHash, msg) {
+		resp.fail(ValidateRepeatCommit, "A commit with equal or greater payment already exists for entry %s", entryHash.String())
+	}
+}
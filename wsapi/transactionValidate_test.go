@@ -0,0 +1,102 @@
+package wsapi_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func validateFailures(t *testing.T, resp interface{}) map[string]bool {
+	codes := map[string]bool{}
+	for _, f := range resp.(*TransactionValidateResponse).Failures {
+		codes[f.Code] = true
+	}
+	return codes
+}
+
+func TestHandleV2TransactionValidate(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
+
+	if _, jErr := HandleV2TransactionValidate(state, TransactionValidateRequest{Type: "bogus", Transaction: "00"}); jErr == nil {
+		t.Error("Expected an error for an unknown type")
+	}
+
+	resp, jErr := HandleV2TransactionValidate(state, TransactionValidateRequest{Transaction: "not hex"})
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	if resp.(*TransactionValidateResponse).Valid || !validateFailures(t, resp)[ValidateDecode] {
+		t.Errorf("Garbage was not rejected as undecodable: %+v", resp)
+	}
+
+	// An unsigned transaction, that pays no fee
+	tx := new(factoid.Transaction)
+	tx.AddInput(testHelper.NewFactoidAddress(1), 1000)
+	tx.AddOutput(testHelper.NewFactoidAddress(2), 1000)
+	tx.SetTimestamp(state.GetTimestamp())
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, jErr = HandleV2TransactionValidate(state, TransactionValidateRequest{Type: "factoid", Transaction: hex.EncodeToString(data)})
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	codes := validateFailures(t, resp)
+	if resp.(*TransactionValidateResponse).Valid || !codes[ValidateRCDCount] || !codes[ValidateInsufficientFee] {
+		t.Errorf("Unexpected failures %+v", resp)
+	}
+	if resp.(*TransactionValidateResponse).TxID != tx.GetSigHash().String() {
+		t.Errorf("Wrong TxID %s", resp.(*TransactionValidateResponse).TxID)
+	}
+
+	// Once signed, the signature checks pass
+	testHelper.SignFactoidTransaction(1, tx)
+	data, err = tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, jErr = HandleV2TransactionValidate(state, TransactionValidateRequest{Transaction: hex.EncodeToString(data)})
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	codes = validateFailures(t, resp)
+	if codes[ValidateRCDCount] || codes[ValidateRCDMismatch] || codes[ValidateSignature] || !codes[ValidateInsufficientFee] {
+		t.Errorf("Unexpected failures %+v", resp)
+	}
+
+	commit := "00015507b2f70bd0165d9fa19a28cfaafb6bc82f538955a98c7b7e60d79fbf92655c1bff1c76466cb3bc3f3cc68d8b2c111f4f24c88d9c031b4124395c940e5e2c5ea496e8aaa2f5c956749fc3eba4acc60fd485fb100e601070a44fcce54ff358d606698547340b3b6a27bcceb6a42d62a3a8d02a6f0d73653215771de243a63ac048a18b59da2946c901273e616bdbb166c535b26d0d446bc69b22c887c534297c7d01b2ac120237086112b5ef34fc6474e5e941d60aa054b465d4d770d7f850169170ef39150b"
+	resp, jErr = HandleV2TransactionValidate(state, TransactionValidateRequest{Type: "commit-chain", Transaction: commit})
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	codes = validateFailures(t, resp)
+	if codes[ValidateDecode] || codes[ValidateVersion] || codes[ValidateCredits] || codes[ValidateSignature] {
+		t.Errorf("Unexpected failures %+v", resp)
+	}
+	if resp.(*TransactionValidateResponse).TxID != "76e123d133a841fe3e08c5e3f3d392f8431f2d7668890c03f003f541efa8fc61" {
+		t.Errorf("Wrong TxID %s", resp.(*TransactionValidateResponse).TxID)
+	}
+
+	// Tamper with the signature
+	tampered := commit[:len(commit)-2] + "00"
+	resp, jErr = HandleV2TransactionValidate(state, TransactionValidateRequest{Type: "commit-chain", Transaction: tampered})
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	if resp.(*TransactionValidateResponse).Valid || !validateFailures(t, resp)[ValidateSignature] {
+		t.Errorf("A tampered signature was not rejected: %+v", resp)
+	}
+
+	// A chain commit is not an entry commit
+	resp, jErr = HandleV2TransactionValidate(state, TransactionValidateRequest{Type: "commit-entry", Transaction: commit})
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	if resp.(*TransactionValidateResponse).Valid {
+		t.Errorf("A chain commit was accepted as an entry commit")
+	}
+}
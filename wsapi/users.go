@@ -0,0 +1,232 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The users file gives each API user the methods it may call.  A user logs in with
+// HTTP basic auth, or with a bearer token:
+//
+//	{
+//		"users": [
+//			{"user": "dashboard", "password": "secret", "methods": ["read"]},
+//			{"token": "0123456789abcdef", "methods": ["submit"]},
+//			{"user": "admin", "password": "secret", "methods": ["*"]}
+//		]
+//	}
+//
+// A method is the name of a call, or one of the groups:
+//	read	every v1 and v2 call but the submits, and the control panel
+//	submit	commit-chain, commit-entry, reveal-chain, reveal-entry, factoid-submit and send-raw-message
+//	admin	every debug call, and changing the log settings from the control panel
+//	*	everything
+//
+// The FactomdRpcUser of factomd.conf keeps access to everything.
+
+// The APIs a method is called through
+const (
+	APIV1           = "v1"
+	APIV2           = "v2"
+	APIDebug        = "debug"
+	APIControlPanel = "control-panel"
+)
+
+// The methods of the control panel
+const (
+	ControlPanelView       = "control-panel"
+	ControlPanelChangeLogs = "control-panel-changelogs"
+)
+
+type APIUser struct {
+	User     string   `json:"user,omitempty"`
+	Password string   `json:"password,omitempty"`
+	Token    string   `json:"token,omitempty"`
+	Methods  []string `json:"methods"`
+
+	passHash  []byte
+	tokenHash []byte
+}
+
+type apiUsersFile struct {
+	Users []*APIUser `json:"users"`
+}
+
+// rpcUser stands for the FactomdRpcUser, or for anyone when the API is open
+var rpcUser = &APIUser{User: "rpcuser", Methods: []string{"*"}}
+
+var (
+	apiUsersMutex sync.RWMutex
+	apiUsers      []*APIUser
+)
+
+// Allows tells if the user may call the method through the api
+func (u *APIUser) Allows(api string, method string) bool {
+	for _, m := range u.Methods {
+		switch m {
+		case "*":
+			return true
+		case "read":
+			if api != APIDebug && !submitMethods[method] && method != ControlPanelChangeLogs {
+				return true
+			}
+		case "submit":
+			if api != APIDebug && submitMethods[method] {
+				return true
+			}
+		case "admin":
+			if api == APIDebug || method == ControlPanelChangeLogs {
+				return true
+			}
+		default:
+			if m == method {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// rateLimitKey names the rate limit buckets of the user.  Users with a token are told
+// apart by the hash of their token, so the token itself is not kept around.
+func (u *APIUser) rateLimitKey() string {
+	if u.User != "" {
+		return "user:" + u.User
+	}
+	return fmt.Sprintf("token:%x", u.tokenHash[:8])
+}
+
+// LoadAPIUsers replaces the API users with the ones of the users file.  No file
+// means no users besides the FactomdRpcUser.  If the file is not valid, the users
+// are left as they were.
+func LoadAPIUsers(filename string) error {
+	var users []*APIUser
+	if filename != "" {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		file := new(apiUsersFile)
+		err = json.Unmarshal(data, file)
+		if err != nil {
+			return fmt.Errorf("Error in the users file %s: %v", filename, err)
+		}
+		names := map[string]bool{}
+		for i, u := range file.Users {
+			if u == nil {
+				return fmt.Errorf("User %d of %s is empty", i, filename)
+			}
+			if (u.User == "") == (u.Token == "") {
+				return fmt.Errorf("User %d of %s needs either a user and a password, or a token", i, filename)
+			}
+			if u.User != "" {
+				if u.Password == "" {
+					return fmt.Errorf("User %s of %s has no password", u.User, filename)
+				}
+				if names[u.User] {
+					return fmt.Errorf("User %s of %s is defined twice", u.User, filename)
+				}
+				names[u.User] = true
+				u.passHash = authHash([]byte(u.Password))
+			} else {
+				u.tokenHash = authHash([]byte(u.Token))
+			}
+			users = append(users, u)
+		}
+	}
+
+	apiUsersMutex.Lock()
+	defer apiUsersMutex.Unlock()
+	apiUsers = users
+	return nil
+}
+
+// ReloadAPIUsers loads the users file of the state
+func ReloadAPIUsers(state interfaces.IState) error {
+	return LoadAPIUsers(state.GetAPIUsersFile())
+}
+
+func authHash(data []byte) []byte {
+	h := sha256.Sum256(data)
+	return h[:]
+}
+
+// Authenticate returns the user the request logs in as
+func Authenticate(state interfaces.IState, r *http.Request) (*APIUser, error) {
+	apiUsersMutex.RLock()
+	users := apiUsers
+	apiUsersMutex.RUnlock()
+
+	if state.GetRpcUser() == "" && len(users) == 0 {
+		//no username was specified in the config file or command line, meaning factomd API is open access
+		return rpcUser, nil
+	}
+
+	authhdr := r.Header["Authorization"]
+	if len(authhdr) == 0 {
+		return nil, errors.New("no auth")
+	}
+
+	//compare hashes because ConstantTimeCompare takes a constant time based on the slice size.  hashing gives a constant slice size.
+	if state.GetRpcUser() != "" && subtle.ConstantTimeCompare(authHash([]byte(authhdr[0])), state.GetRpcAuthHash()) == 1 {
+		return rpcUser, nil
+	}
+
+	if strings.HasPrefix(authhdr[0], "Bearer ") {
+		presented := authHash([]byte(strings.TrimPrefix(authhdr[0], "Bearer ")))
+		for _, u := range users {
+			if u.tokenHash != nil && subtle.ConstantTimeCompare(presented, u.tokenHash) == 1 {
+				return u, nil
+			}
+		}
+		return nil, errors.New("bad auth")
+	}
+
+	name, pass, ok := r.BasicAuth()
+	if !ok {
+		return nil, errors.New("bad auth")
+	}
+	presented := authHash([]byte(pass))
+	for _, u := range users {
+		if u.passHash != nil && u.User == name && subtle.ConstantTimeCompare(presented, u.passHash) == 1 {
+			return u, nil
+		}
+	}
+	return nil, errors.New("bad auth")
+}
+
+// CheckPermission tells if the request may call the method through the api
+func CheckPermission(state interfaces.IState, r *http.Request, api string, method string) *primitives.JSONError {
+	u, err := Authenticate(state, r)
+	if err != nil {
+		return NewUnauthorizedError()
+	}
+	if !u.Allows(api, method) {
+		return NewPermissionDeniedError(method)
+	}
+	return nil
+}
+
+// permitted checks the permission of every request of a batch
+func permitted(r *http.Request, api string, handler RequestHandler) RequestHandler {
+	return func(state interfaces.IState, j *primitives.JSON2Request) (*primitives.JSON2Response, *primitives.JSONError) {
+		if jsonError := CheckPermission(state, r, api, j.Method); jsonError != nil {
+			return nil, jsonError
+		}
+		return handler(state, j)
+	}
+}
@@ -0,0 +1,91 @@
+package wsapi_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func TestAPIUsers(t *testing.T) {
+	state := testHelper.CreateEmptyTestState()
+
+	f, err := ioutil.TempFile("", "users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(`{"users": [
+		{"user": "dashboard", "password": "dashpass", "methods": ["read"]},
+		{"token": "writertoken", "methods": ["submit"]},
+		{"user": "admin", "password": "adminpass", "methods": ["admin", "heights"]}
+	]}`)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := func(user, pass, token string) *http.Request {
+		r, _ := http.NewRequest("POST", "http://localhost:8088/v2", nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		} else if user != "" {
+			r.SetBasicAuth(user, pass)
+		}
+		return r
+	}
+
+	// Without users the API is open
+	if err := LoadAPIUsers(""); err != nil {
+		t.Fatal(err)
+	}
+	if CheckPermission(state, request("", "", ""), APIDebug, "set-delay") != nil {
+		t.Error("The open API refused a call")
+	}
+
+	if err := LoadAPIUsers(f.Name()); err != nil {
+		t.Fatal(err)
+	}
+	defer LoadAPIUsers("")
+
+	for _, c := range []struct {
+		r       *http.Request
+		api     string
+		method  string
+		allowed bool
+	}{
+		{request("", "", ""), APIV2, "heights", false},
+		{request("dashboard", "wrong", ""), APIV2, "heights", false},
+		{request("dashboard", "dashpass", ""), APIV2, "heights", true},
+		{request("dashboard", "dashpass", ""), APIControlPanel, ControlPanelView, true},
+		{request("dashboard", "dashpass", ""), APIControlPanel, ControlPanelChangeLogs, false},
+		{request("dashboard", "dashpass", ""), APIV2, "commit-entry", false},
+		{request("dashboard", "dashpass", ""), APIDebug, "holding-queue", false},
+		{request("", "", "writertoken"), APIV2, "commit-entry", true},
+		{request("", "", "writertoken"), APIV1, "factoid-submit", true},
+		{request("", "", "writertoken"), APIV2, "heights", false},
+		{request("", "", "othertoken"), APIV2, "commit-entry", false},
+		{request("admin", "adminpass", ""), APIDebug, "set-delay", true},
+		{request("admin", "adminpass", ""), APIV2, "heights", true},
+		{request("admin", "adminpass", ""), APIV2, "entry", false},
+	} {
+		if (CheckPermission(state, c.r, c.api, c.method) == nil) != c.allowed {
+			t.Errorf("%s %v calling %s %s: expected allowed %v", c.r.Header.Get("Authorization"), c.r.URL, c.api, c.method, c.allowed)
+		}
+	}
+
+	// A broken file leaves the users as they were
+	err = ioutil.WriteFile(f.Name(), []byte(`{"users": [{"user": "nopass", "methods": ["*"]}]}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if LoadAPIUsers(f.Name()) == nil {
+		t.Error("Loaded a user without a password")
+	}
+	if CheckPermission(state, request("dashboard", "dashpass", ""), APIV2, "heights") != nil {
+		t.Error("The users were dropped")
+	}
+}
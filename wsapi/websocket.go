@@ -0,0 +1,176 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"encoding/hex"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/web"
+	"golang.org/x/net/websocket"
+)
+
+// websocketHandler binds the websocket endpoint to the server so the handler can find
+// the state currently served on that port
+func websocketHandler(server *web.Server) websocket.Handler {
+	return func(ws *websocket.Conn) {
+		ServersMutex.Lock()
+		state := server.Env["state"].(interfaces.IState)
+		ServersMutex.Unlock()
+
+		HandleV2Websocket(state, ws)
+	}
+}
+
+// HandleV2Websocket serves one websocket client.  The client sends JSON-RPC 2.0
+// "subscribe" and "unsubscribe" requests, and receives a "subscription" notification
+// for every event matching one of its subscriptions.
+func HandleV2Websocket(state interfaces.IState, ws *websocket.Conn) {
+	defer ws.Close()
+
+	if err := checkAuthHeader(state, ws.Request()); err != nil {
+		wsLog.Warningf("Unauthorized V2 websocket client connection attempt from %s", remoteHost(ws.Request()))
+		resp := primitives.NewJSON2Response()
+		resp.Error = primitives.NewJSONError(-32600, "Invalid Request", "Unauthorized")
+		websocket.JSON.Send(ws, resp)
+		return
+	}
+	if jsonError := CheckPermission(state, ws.Request(), APIV2, "subscribe"); jsonError != nil {
+		resp := primitives.NewJSON2Response()
+		resp.Error = jsonError
+		websocket.JSON.Send(ws, resp)
+		return
+	}
+
+	sub := NewSubscriber(state.GetFactomNodeName())
+	defer sub.Close()
+	WebsocketSubscribers.Inc()
+	defer WebsocketSubscribers.Dec()
+
+	// All writes go through this goroutine, so responses and notifications are
+	// never interleaved on the connection
+	replies := make(chan *primitives.JSON2Response, 10)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			var err error
+			select {
+			case n, ok := <-sub.Notifications:
+				if !ok {
+					return
+				}
+				err = websocket.JSON.Send(ws, n)
+			case r := <-replies:
+				err = websocket.JSON.Send(ws, r)
+			case <-done:
+				return
+			}
+			if err != nil {
+				ws.Close()
+				return
+			}
+		}
+	}()
+
+	for {
+		j := new(primitives.JSON2Request)
+		if err := websocket.JSON.Receive(ws, j); err != nil {
+			return
+		}
+
+		resp := primitives.NewJSON2Response()
+		resp.ID = j.ID
+		if j.JSONRPC != "2.0" {
+			resp.Error = NewInvalidRequestError()
+		} else {
+			resp.Result, resp.Error = HandleSubscriptionRequest(sub, j)
+		}
+
+		select {
+		case replies <- resp:
+		case <-done:
+			return
+		}
+	}
+}
+
+// HandleSubscriptionRequest executes a "subscribe" or "unsubscribe" request for the
+// given subscriber
+func HandleSubscriptionRequest(sub *Subscriber, j *primitives.JSON2Request) (interface{}, *primitives.JSONError) {
+	switch j.Method {
+	case "subscribe":
+		req := new(SubscribeRequest)
+		err := MapToObject(j.Params, req)
+		if err != nil {
+			return nil, NewInvalidParamsError()
+		}
+
+		var filter interfaces.IHash
+		switch req.Topic {
+		case TopicNewDBlocks:
+		case TopicNewEntries:
+			filter, err = primitives.HexToHash(req.ChainID)
+			if err != nil {
+				return nil, NewInvalidHashError()
+			}
+		case TopicFactoidTransactions:
+			filter = addressToHash(req.Address)
+			if filter == nil {
+				return nil, NewInvalidAddressError()
+			}
+		case TopicAckStatus:
+			filter, err = primitives.HexToHash(req.Hash)
+			if err != nil {
+				return nil, NewInvalidHashError()
+			}
+		default:
+			return nil, NewInvalidTopicError()
+		}
+
+		id, ok := sub.Subscribe(req.Topic, filter)
+		if !ok {
+			return nil, NewInvalidTopicError()
+		}
+		resp := new(SubscribeResponse)
+		resp.Subscription = id
+		return resp, nil
+
+	case "unsubscribe":
+		req := new(UnsubscribeRequest)
+		err := MapToObject(j.Params, req)
+		if err != nil {
+			return nil, NewInvalidParamsError()
+		}
+		if !sub.Unsubscribe(req.Subscription) {
+			return nil, NewSubscriptionNotFoundError()
+		}
+		resp := new(UnsubscribeResponse)
+		resp.Unsubscribed = true
+		return resp, nil
+	}
+	return nil, NewMethodNotFoundError()
+}
+
+// addressToHash accepts a factoid or entry credit address, either as a user string
+// or as the hex of the underlying 32 bytes.  Returns nil if the address is invalid.
+func addressToHash(address string) interfaces.IHash {
+	var adr []byte
+	if primitives.ValidateFUserStr(address) || primitives.ValidateECUserStr(address) {
+		adr = primitives.ConvertUserStrToAddress(address)
+	} else {
+		var err error
+		adr, err = hex.DecodeString(address)
+		if err != nil {
+			return nil
+		}
+	}
+	if len(adr) != constants.HASH_LENGTH {
+		return nil
+	}
+	return primitives.NewHash(adr)
+}
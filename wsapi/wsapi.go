@@ -6,11 +6,9 @@ package wsapi
 
 import (
 	"crypto/sha256"
-	"crypto/subtle"
 	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -50,6 +48,10 @@ func Start(state interfaces.IState) {
 	h := sha256.New()
 	h.Write(httpBasicAuth(rpcUser, rpcPass))
 	state.SetRpcAuthHash(h.Sum(nil)) //set this in the beginning to prevent timing attacks
+	SetRateLimits(state)
+	if err := ReloadAPIUsers(state); err != nil {
+		panic(fmt.Sprintf("could not load the API users with error: %v", err))
+	}
 
 	if Servers[state.GetPort()] == nil {
 		server = web.NewServer()
@@ -85,11 +87,14 @@ func Start(state interfaces.IState) {
 
 		server.Post("/v2", HandleV2)
 		server.Get("/v2", HandleV2)
+		server.Get("/v2/schema", HandleV2Schema)
+		server.Websocket("/v2/ws", websocketHandler(server))
 
 		// start the debugging api if we are not on the main network
 		if state.GetNetworkName() != "MAIN" {
 			server.Post("/debug", HandleDebug)
 			server.Get("/debug", HandleDebug)
+			server.Get("/debug/schema", HandleDebugSchema)
 		}
 
 		tlsIsEnabled, tlsPrivate, tlsPublic := state.GetTlsInfo()
@@ -860,27 +865,8 @@ func httpBasicAuth(username, password string) []byte {
 }
 
 func checkAuthHeader(state interfaces.IState, r *http.Request) error {
-	if "" == state.GetRpcUser() {
-		//no username was specified in the config file or command line, meaning factomd API is open access
-		return nil
-	}
-
-	authhdr := r.Header["Authorization"]
-	if len(authhdr) == 0 {
-		return errors.New("no auth")
-	}
-
-	correctAuth := state.GetRpcAuthHash()
-
-	h := sha256.New()
-	h.Write([]byte(authhdr[0]))
-	presentedPassHash := h.Sum(nil)
-
-	cmp := subtle.ConstantTimeCompare(presentedPassHash, correctAuth) //compare hashes because ConstantTimeCompare takes a constant time based on the slice size.  hashing gives a constant slice size.
-	if cmp != 1 {
-		return errors.New("bad auth")
-	}
-	return nil
+	_, err := Authenticate(state, r)
+	return err
 }
 
 func checkHttpPasswordOkV1(state interfaces.IState, ctx *web.Context) bool {
@@ -892,6 +878,14 @@ func checkHttpPasswordOkV1(state interfaces.IState, ctx *web.Context) bool {
 		http.Error(ctx.ResponseWriter, "401 Unauthorized.", http.StatusUnauthorized)
 		return false
 	}
+	if jsonError := CheckRateLimit(state, ctx.Request, v1Method(ctx.Request)); jsonError != nil {
+		handleV1Error(ctx, jsonError)
+		return false
+	}
+	if jsonError := CheckPermission(state, ctx.Request, APIV1, v1Method(ctx.Request)); jsonError != nil {
+		handleV1Error(ctx, jsonError)
+		return false
+	}
 	return true
 }
 
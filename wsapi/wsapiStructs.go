@@ -112,11 +112,118 @@ type EntryResponse struct {
 	ExtIDs  []string `json:"extids"`
 }
 
+type EntryWithBlockInfo struct {
+	EntryHash string   `json:"entryhash"`
+	ChainID   string   `json:"chainid"`
+	Content   string   `json:"content"`
+	ExtIDs    []string `json:"extids"`
+	DBHeight  int64    `json:"dbheight"`
+	Timestamp int64    `json:"timestamp"`
+	Pruned    bool     `json:"pruned,omitempty"`
+}
+
+type EntriesByChainResponse struct {
+	ChainID string `json:"chainid"`
+	// TotalEntries is 0 when the node does not keep chain info
+	TotalEntries int64                `json:"totalentries"`
+	Entries      []EntryWithBlockInfo `json:"entries"`
+	NextCursor   string               `json:"nextcursor,omitempty"`
+}
+
+type EntriesByExtIDResponse struct {
+	ChainID    string               `json:"chainid"`
+	ExtID      string               `json:"extid"`
+	Entries    []EntryWithBlockInfo `json:"entries"`
+	NextCursor string               `json:"nextcursor,omitempty"`
+}
+
+type AddressTransaction struct {
+	TxID      string `json:"txid"`
+	DBHeight  int64  `json:"dbheight"`
+	Direction string `json:"direction"`
+	Amount    uint64 `json:"amount"`
+}
+
+type AddressTransactionsResponse struct {
+	Address      string               `json:"address"`
+	Transactions []AddressTransaction `json:"transactions"`
+	NextCursor   string               `json:"nextcursor,omitempty"`
+}
+
+type BalanceAtHeightResponse struct {
+	Address string `json:"address"`
+	Height  int64  `json:"height"`
+	Balance int64  `json:"balance"`
+}
+
+type BitcoinAnchor struct {
+	EntryHash       string `json:"entryhash"`
+	RecordHeight    uint32 `json:"recordheight"`
+	Address         string `json:"address"`
+	TransactionHash string `json:"transactionhash"`
+	BlockHeight     int32  `json:"blockheight"`
+	BlockHash       string `json:"blockhash"`
+	Offset          int32  `json:"offset"`
+}
+
+type EthereumAnchor struct {
+	EntryHash       string `json:"entryhash"`
+	RecordHeight    uint32 `json:"recordheight"`
+	Address         string `json:"address"`
+	TransactionHash string `json:"transactionhash"`
+	BlockHeight     int64  `json:"blockheight"`
+	BlockHash       string `json:"blockhash"`
+	Offset          int64  `json:"offset"`
+}
+
+type AnchorsResponse struct {
+	Height   uint32          `json:"directoryblockheight"`
+	KeyMR    string          `json:"directoryblockkeymr"`
+	Bitcoin  *BitcoinAnchor  `json:"bitcoin"`
+	Ethereum *EthereumAnchor `json:"ethereum"`
+}
+
+type ValidationFailure struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type TransactionValidateResponse struct {
+	Type         string              `json:"type"`
+	Valid        bool                `json:"valid"`
+	TxID         string              `json:"txid,omitempty"`
+	EntryHash    string              `json:"entryhash,omitempty"`
+	Fee          uint64              `json:"fee,omitempty"`
+	ExchangeRate uint64              `json:"exchangerate,omitempty"`
+	Failures     []ValidationFailure `json:"failures"`
+}
+
+type FeeEstimateResponse struct {
+	Fee              uint64 `json:"fee"`
+	FeeEC            uint64 `json:"feeec"`
+	Rate             uint64 `json:"rate"`
+	RateChangeHeight uint32 `json:"ratechangeheight,omitempty"`
+	Size             int    `json:"size"`
+	Signatures       int    `json:"signatures"`
+}
+
 type ChainHeadResponse struct {
 	ChainHead          string `json:"chainhead"`
 	ChainInProcessList bool   `json:"chaininprocesslist"`
 }
 
+type ChainInfoResponse struct {
+	ChainID   string `json:"chainid"`
+	ChainHead string `json:"chainhead"`
+	// CreationHeight and FirstEntryHash are missing while the first entry block of
+	// the chain is not synced
+	CreationHeight *int64 `json:"creationheight,omitempty"`
+	FirstEntryHash string `json:"firstentryhash,omitempty"`
+	EBlockCount    int64  `json:"eblockcount"`
+	EntryCount     int64  `json:"entrycount"`
+	PayloadBytes   int64  `json:"payloadbytes"`
+}
+
 type EntryCreditBalanceResponse struct {
 	Balance int64 `json:"balance"`
 }
@@ -241,10 +348,40 @@ type HeightRequest struct {
 	Height int64 `json:"height"`
 }
 
+type BalanceAtHeightRequest struct {
+	Address string `json:"address"`
+	Height  int64  `json:"height"`
+}
+
 type ChainIDRequest struct {
 	ChainID string `json:"chainid"`
 }
 
+type EntriesByChainRequest struct {
+	ChainID   string `json:"chainid"`
+	Height    *int64 `json:"height,omitempty"`
+	Cursor    string `json:"cursor,omitempty"`
+	Direction string `json:"direction,omitempty"`
+	Limit     int    `json:"limit,omitempty"`
+}
+
+type EntriesByExtIDRequest struct {
+	ChainID   string `json:"chainid"`
+	ExtID     string `json:"extid"`
+	Height    *int64 `json:"height,omitempty"`
+	Cursor    string `json:"cursor,omitempty"`
+	Direction string `json:"direction,omitempty"`
+	Limit     int    `json:"limit,omitempty"`
+}
+
+type AddressTransactionsRequest struct {
+	Address   string `json:"address"`
+	Height    *int64 `json:"height,omitempty"`
+	Cursor    string `json:"cursor,omitempty"`
+	Direction string `json:"direction,omitempty"`
+	Limit     int    `json:"limit,omitempty"`
+}
+
 type EntryRequest struct {
 	Entry string `json:"entry"`
 }
@@ -280,6 +417,18 @@ type TransactionRequest struct {
 	Transaction string `json:"transaction"`
 }
 
+type TransactionValidateRequest struct {
+	Type        string `json:"type,omitempty"`
+	Transaction string `json:"transaction"`
+}
+
+type FeeEstimateRequest struct {
+	Transaction string `json:"transaction,omitempty"`
+	Inputs      int    `json:"inputs,omitempty"`
+	Outputs     int    `json:"outputs,omitempty"`
+	ECOutputs   int    `json:"ecoutputs,omitempty"`
+}
+
 type SendRawMessageRequest struct {
 	Message string `json:"message"`
 }
@@ -301,3 +450,48 @@ type MultipleECBalances struct {
 	LastSavedHeight uint32        `json:"lastsavedheight"`
 	Balances        []interface{} `json:"balances"`
 }
+
+type SubscribeRequest struct {
+	Topic   string `json:"topic"`
+	ChainID string `json:"chainid,omitempty"`
+	Address string `json:"address,omitempty"`
+	Hash    string `json:"hash,omitempty"`
+}
+
+type SubscribeResponse struct {
+	Subscription int64 `json:"subscription"`
+}
+
+type UnsubscribeRequest struct {
+	Subscription int64 `json:"subscription"`
+}
+
+type UnsubscribeResponse struct {
+	Unsubscribed bool `json:"unsubscribed"`
+}
+
+type NewDBlockNotification struct {
+	DBHeight  int64  `json:"dbheight"`
+	KeyMR     string `json:"keymr"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type NewEntriesNotification struct {
+	ChainID         string   `json:"chainid"`
+	DBHeight        int64    `json:"dbheight"`
+	EntryBlockKeyMR string   `json:"entryblockkeymr"`
+	EntryHashes     []string `json:"entryhashes"`
+}
+
+type FactoidTransactionNotification struct {
+	TxID      string `json:"txid"`
+	DBHeight  int64  `json:"dbheight"`
+	Address   string `json:"address"`
+	Direction string `json:"direction"`
+	Amount    uint64 `json:"amount"`
+}
+
+type AckStatusNotification struct {
+	Hash   string `json:"hash"`
+	Status string `json:"status"`
+}
@@ -51,12 +51,26 @@ func HandleV2(ctx *web.Context) {
 		return
 	}
 
+	if IsBatchRequest(body) {
+		HandleBatch(ctx, state, body, rateLimited(ctx.Request, permitted(ctx.Request, APIV2, HandleV2Request)))
+		return
+	}
+
 	j, err := primitives.ParseJSON2Request(string(body))
 	if err != nil {
 		HandleV2Error(ctx, nil, NewInvalidRequestError())
 		return
 	}
 
+	if jsonError := CheckRateLimit(state, ctx.Request, j.Method); jsonError != nil {
+		HandleV2Error(ctx, j, jsonError)
+		return
+	}
+	if jsonError := CheckPermission(state, ctx.Request, APIV2, j.Method); jsonError != nil {
+		HandleV2Error(ctx, j, jsonError)
+		return
+	}
+
 	jsonResp, jsonError := HandleV2Request(state, j)
 
 	if jsonError != nil {
@@ -72,9 +86,15 @@ func HandleV2Request(state interfaces.IState, j *primitives.JSON2Request) (*prim
 	var jsonError *primitives.JSONError
 	params := j.Params
 	switch j.Method {
+	case "balance-at-height":
+		resp, jsonError = HandleV2BalanceAtHeight(state, params)
+		break
 	case "chain-head":
 		resp, jsonError = HandleV2ChainHead(state, params)
 		break
+	case "chain-info":
+		resp, jsonError = HandleV2ChainInfo(state, params)
+		break
 	case "commit-chain":
 		resp, jsonError = HandleV2CommitChain(state, params)
 		break
@@ -175,6 +195,18 @@ func HandleV2Request(state interfaces.IState, j *primitives.JSON2Request) (*prim
 		resp, jsonError = HandleV2MultipleFCTBalances(state, params)
 	case "multiple-ec-balances":
 		resp, jsonError = HandleV2MultipleECBalances(state, params)
+	case "entries-by-chain":
+		resp, jsonError = HandleV2EntriesByChain(state, params)
+	case "entries-by-extid":
+		resp, jsonError = HandleV2EntriesByExtID(state, params)
+	case "address-transactions":
+		resp, jsonError = HandleV2AddressTransactions(state, params)
+	case "anchors":
+		resp, jsonError = HandleV2Anchors(state, params)
+	case "transaction-validate":
+		resp, jsonError = HandleV2TransactionValidate(state, params)
+	case "fee-estimate":
+		resp, jsonError = HandleV2FeeEstimate(state, params)
 		//case "factoid-accounts":
 		// resp, jsonError = HandleV2Accounts(state, params)
 	default:
@@ -720,6 +752,8 @@ func HandleV2RawData(state interfaces.IState, params interface{}) (interface{},
 			b, _ = block.MarshalBinary()
 		} else if block, _ = dbase.FetchEntry(h); block != nil {
 			b, _ = block.MarshalBinary()
+		} else if pruned, _ := dbase.IsEntryPruned(h); pruned {
+			return nil, NewEntryPrunedError()
 		} else {
 			return nil, NewObjectNotFoundError()
 		}
@@ -837,6 +871,14 @@ func HandleV2EntryBlock(state interfaces.IState, params interface{}) (interface{
 		e.Header.Timestamp = dblock.GetHeader().GetTimestamp().GetTimeSeconds()
 	}
 
+	e.EntryList = EBlockEntryAddrs(block, e.Header.Timestamp)
+
+	return e, nil
+}
+
+// EBlockEntryAddrs lists the entries of an entry block, each stamped with the time of
+// the minute it was included in.  Minute markers are not returned.
+func EBlockEntryAddrs(block interfaces.IEntryBlock, blockTimestamp int64) []EntryAddr {
 	// create a map of possible minute markers that may be found in the
 	// EBlock Body
 	mins := make(map[string]uint8)
@@ -846,15 +888,16 @@ func HandleV2EntryBlock(state interfaces.IState, params interface{}) (interface{
 		mins[hex.EncodeToString(h)] = i
 	}
 
+	var list []EntryAddr
 	estack := make([]EntryAddr, 0)
 	for _, v := range block.GetBody().GetEBEntries() {
 		if n, exist := mins[v.String()]; exist {
 			// the entry is a minute marker. add time to all of the
 			// previous entries for the minute
-			t := int64(e.Header.Timestamp + 60*int64(n))
+			t := int64(blockTimestamp + 60*int64(n))
 			for _, w := range estack {
 				w.Timestamp = t
-				e.EntryList = append(e.EntryList, w)
+				list = append(list, w)
 			}
 			estack = make([]EntryAddr, 0)
 		} else {
@@ -864,7 +907,7 @@ func HandleV2EntryBlock(state interfaces.IState, params interface{}) (interface{
 		}
 	}
 
-	return e, nil
+	return list
 }
 
 func HandleV2Entry(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
@@ -895,6 +938,9 @@ func HandleV2Entry(state interfaces.IState, params interface{}) (interface{}, *p
 			return nil, NewInvalidHashError()
 		}
 		if entry == nil {
+			if pruned, _ := dbase.IsEntryPruned(h); pruned {
+				return nil, NewEntryPrunedError()
+			}
 			return nil, NewEntryNotFoundError()
 		}
 	}